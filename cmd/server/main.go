@@ -3,6 +3,9 @@ package main
 import (
 	"avito_test_task/internal/app"
 	"log"
+
+	// База часовых поясов для тихих часов уведомлений в образах без tzdata
+	_ "time/tzdata"
 )
 
 func main() {
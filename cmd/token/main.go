@@ -0,0 +1,48 @@
+package main
+
+import (
+	"avito_test_task/internal/auth"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// token выпускает HS256-токен для локальной разработки, секрет берется из APP_JWT_SECRET
+func main() {
+	sub := flag.String("sub", "", "user_id вызывающего")
+	roles := flag.String("roles", "USER", "роли через запятую: ADMIN, USER")
+	ttl := flag.Duration("ttl", time.Hour, "время жизни токена")
+	issuer := flag.String("iss", os.Getenv("APP_JWT_ISSUER"), "издатель")
+	aud := flag.String("aud", os.Getenv("APP_JWT_AUDIENCE"), "аудитория")
+	flag.Parse()
+
+	secret := os.Getenv("APP_JWT_SECRET")
+	if secret == "" || *sub == "" {
+		slog.Error("APP_JWT_SECRET and -sub are required")
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	claims := &auth.Claims{
+		Subject:   *sub,
+		Roles:     strings.Split(*roles, ","),
+		Issuer:    *issuer,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(*ttl).Unix(),
+	}
+
+	if *aud != "" {
+		claims.Audience = []string{*aud}
+	}
+
+	token, err := auth.SignHS256(claims, secret)
+	if err != nil {
+		slog.Error("failed to sign token", "error", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(token)
+}
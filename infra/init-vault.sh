@@ -12,6 +12,9 @@ sleep 5
 export VAULT_ADDR=http://localhost:8201
 export VAULT_TOKEN=root
 
+# секрет подписи JWT: заданный в окружении или случайный, общий секрет-заглушка не используется
+JWT_SECRET="${JWT_SECRET:-$(head -c 32 /dev/urandom | od -An -tx1 | tr -d ' \n')}"
+
 echo "Initializing secrets in Vault..."
 vault kv put secret/avito-test-task \
   PG_USERNAME=postgres \
@@ -19,6 +22,7 @@ vault kv put secret/avito-test-task \
   PG_DATABASE=avito-test-task-db \
   PG_HOST=postgres \
   PG_PORT=5432 \
-  APP_PORT=8080
+  APP_PORT=8080 \
+  JWT_SECRET="$JWT_SECRET"
 
 echo "Secrets initialized. You can now run: docker compose up"
@@ -1,22 +1,80 @@
 package app
 
 import (
+	"avito_test_task/internal/auth"
 	"avito_test_task/internal/config"
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/eventbus"
 	"avito_test_task/internal/handler"
+	"avito_test_task/internal/job"
+	"avito_test_task/internal/middleware"
+	"avito_test_task/internal/notify"
+	"avito_test_task/internal/publisher"
+	"avito_test_task/internal/repository/memory"
 	"avito_test_task/internal/repository/pg"
 	"avito_test_task/internal/usecase"
+	"avito_test_task/internal/webhook"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"log/slog"
+	"time"
 )
 
 func Run() error {
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	cfg := config.New(ctx)
-	repo := pg.New(ctx, cfg.Postgres)
+
+	var repo usecase.Repository
+	switch cfg.Storage {
+	case config.StorageMemory:
+		slog.Info("using in-memory storage")
+		repo = memory.New()
+	case config.StoragePostgres:
+		pgRepo := pg.New(ctx, cfg.Postgres)
+		if pgRepo == nil {
+			return errors.New("error initializing postgres repository")
+		}
+
+		defer pgRepo.Close()
+		repo = pgRepo
+	default:
+		return fmt.Errorf("unknown storage: %s", cfg.Storage)
+	}
+
+	verifier, err := auth.NewVerifier(cfg.Auth)
+	if err != nil {
+		return fmt.Errorf("error initializing auth: %w", err)
+	}
+
+	events, err := publisher.New(cfg.Outbox)
+	if err != nil {
+		return fmt.Errorf("error initializing outbox publisher: %w", err)
+	}
+
+	defer events.Close()
+
 	uc := usecase.New(repo)
-	h := handler.New(uc)
+	bus := eventbus.New()
+	h := handler.New(uc, middleware.NewAuth(verifier, uc), bus, cfg.SSEHeartbeat)
+
+	// фоновые задачи действуют от имени системы: без инициатора в контексте права не выдаются
+	jobCtx := entity.WithSystemActor(ctx)
+	go job.NewAbsenceSync(uc, cfg.AbsenceSyncInterval, cfg.AbsenceReassign).Run(jobCtx)
+	go job.NewWebhookDispatcher(uc, webhook.NewSender(cfg.WebhookTimeout), cfg.WebhookRetry,
+		cfg.WebhookInterval, webhookLease(cfg)).Run(jobCtx)
+	go job.NewOutboxRelay(uc, events, cfg.OutboxRetry, cfg.OutboxInterval,
+		outboxLease(cfg), cfg.OutboxRetention).Run(jobCtx)
+	go job.NewEventFeed(uc, bus, cfg.EventFeedInterval).Run(jobCtx)
+	go job.NewNotifier(uc, notificationSenders(cfg), cfg.NotifyRetry,
+		cfg.NotifyInterval, notificationLease(cfg)).Run(jobCtx)
+	go job.NewReviewSLA(uc, cfg.ReviewSLAInterval).Run(jobCtx)
+	if cfg.RebalanceInterval > 0 {
+		go job.NewRebalance(uc, cfg.RebalanceInterval).Run(jobCtx)
+	}
 
 	r := gin.Default()
 
@@ -27,6 +85,35 @@ func Run() error {
 		return fmt.Errorf("error running server: %w", err)
 	}
 
-	repo.Close()
 	return nil
 }
+
+// webhookLease время, на которое забранная доставка скрыта от других экземпляров:
+// пачка отправляется последовательно, поэтому не меньше таймаута на каждую доставку
+func webhookLease(cfg *config.Config) time.Duration {
+	return cfg.WebhookTimeout * time.Duration(usecase.WebhookBatchSize)
+}
+
+// outboxLease время, на которое забранное событие outbox скрыто от других экземпляров:
+// пачка публикуется последовательно, поэтому не меньше таймаута на каждое событие
+func outboxLease(cfg *config.Config) time.Duration {
+	return cfg.Outbox.Timeout * time.Duration(usecase.OutboxBatchSize)
+}
+
+// notificationSenders отправители уведомлений по каналам; EMAIL доступен, только если задан SMTP-сервер
+func notificationSenders(cfg *config.Config) map[entity.NotificationChannel]usecase.NotificationSender {
+	senders := map[entity.NotificationChannel]usecase.NotificationSender{
+		entity.ChannelChat: notify.NewChatSender(cfg.NotifyTimeout),
+	}
+
+	if cfg.SMTP.Addr != "" {
+		senders[entity.ChannelEmail] = notify.NewSMTPSender(*cfg.SMTP)
+	}
+
+	return senders
+}
+
+// notificationLease время, на которое забранное уведомление скрыто от других экземпляров
+func notificationLease(cfg *config.Config) time.Duration {
+	return cfg.NotifyTimeout * time.Duration(usecase.NotificationBatchSize)
+}
@@ -0,0 +1,13 @@
+package auth
+
+import "time"
+
+// Config параметры проверки JWT: Secret — ключ HS256, JWKSFile — путь к JWKS с открытыми
+// ключами RS256. Issuer и Audience проверяются, только если заданы
+type Config struct {
+	Secret   string
+	JWKSFile string
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+}
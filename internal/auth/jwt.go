@@ -0,0 +1,270 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	algHS256 = "HS256"
+	algRS256 = "RS256"
+
+	defaultLeeway = 30 * time.Second
+
+	// placeholderSecret секрет, который раньше записывал init-vault.sh; токены с ним может
+	// подписать кто угодно
+	placeholderSecret = "change-me-in-production"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token expired")
+)
+
+// Claims утверждения токена, которые использует сервис: sub — user_id, roles — роли (ADMIN, USER)
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Roles     []string `json:"roles"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+}
+
+// audience поле aud, которое по RFC 7519 бывает строкой или массивом строк
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+
+	*a = list
+	return nil
+}
+
+func (a audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+
+	return json.Marshal([]string(a))
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// Verifier проверяет подпись и срок действия JWT. Алгоритм определяется ключом:
+// HS256 принимается только при заданном секрете, RS256 — только при загруженном JWKS
+type Verifier struct {
+	secret   []byte
+	rsaKeys  map[string]*rsa.PublicKey
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+// NewVerifier создает проверку токенов; нужен хотя бы один источник ключей
+func NewVerifier(cfg *Config) (*Verifier, error) {
+	v := &Verifier{
+		secret:   []byte(cfg.Secret),
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		leeway:   cfg.Leeway,
+		now:      time.Now,
+	}
+
+	if v.leeway <= 0 {
+		v.leeway = defaultLeeway
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+
+		v.rsaKeys = keys
+	}
+
+	if cfg.Secret == placeholderSecret {
+		return nil, errors.New("JWT secret is the public placeholder: set a random secret")
+	}
+
+	if len(v.secret) == 0 && len(v.rsaKeys) == 0 {
+		return nil, errors.New("no JWT keys configured: set secret or JWKS file")
+	}
+
+	return v, nil
+}
+
+// Verify разбирает токен, проверяет подпись, срок действия, издателя и аудиторию
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if err := v.verifySignature(h, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if err := v.validate(&claims); err != nil {
+		return nil, err
+	}
+
+	return &claims, nil
+}
+
+func (v *Verifier) verifySignature(h header, signed string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signed))
+
+	switch h.Alg {
+	case algHS256:
+		if len(v.secret) == 0 {
+			return fmt.Errorf("%w: HS256 is not enabled", ErrInvalidToken)
+		}
+
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+
+		return nil
+	case algRS256:
+		key, err := v.rsaKey(h.Kid)
+		if err != nil {
+			return err
+		}
+
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported alg %q", ErrInvalidToken, h.Alg)
+	}
+}
+
+// rsaKey выбирает ключ по kid; без kid допускается, только если в JWKS один ключ
+func (v *Verifier) rsaKey(kid string) (*rsa.PublicKey, error) {
+	if len(v.rsaKeys) == 0 {
+		return nil, fmt.Errorf("%w: RS256 is not enabled", ErrInvalidToken)
+	}
+
+	if key, ok := v.rsaKeys[kid]; ok {
+		return key, nil
+	}
+
+	if kid == "" && len(v.rsaKeys) == 1 {
+		for _, key := range v.rsaKeys {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+}
+
+func (v *Verifier) validate(claims *Claims) error {
+	now := v.now()
+
+	if claims.Subject == "" {
+		return fmt.Errorf("%w: sub is required", ErrInvalidToken)
+	}
+
+	if claims.ExpiresAt == 0 {
+		return fmt.Errorf("%w: exp is required", ErrInvalidToken)
+	}
+
+	if now.After(time.Unix(claims.ExpiresAt, 0).Add(v.leeway)) {
+		return ErrExpiredToken
+	}
+
+	if claims.NotBefore != 0 && now.Add(v.leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return fmt.Errorf("%w: token is not valid yet", ErrInvalidToken)
+	}
+
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+
+	if v.audience != "" && !slices.Contains(claims.Audience, v.audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+
+	return nil
+}
+
+// SignHS256 подписывает утверждения секретом; используется для выпуска токенов в dev-окружении
+func SignHS256(claims *Claims, secret string) (string, error) {
+	h, err := encodeSegment(header{Alg: algHS256, Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signed := h + "." + payload
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+func encodeSegment(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,330 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testSecret = "test-secret"
+
+var testNow = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func testClaims() *Claims {
+	return &Claims{
+		Subject:   "u1",
+		Roles:     []string{"USER"},
+		ExpiresAt: testNow.Add(time.Hour).Unix(),
+	}
+}
+
+func newTestVerifier(t *testing.T, cfg *Config) *Verifier {
+	t.Helper()
+
+	v, err := NewVerifier(cfg)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+
+	v.now = func() time.Time { return testNow }
+	return v
+}
+
+func signHS256(t *testing.T, claims *Claims, secret string) string {
+	t.Helper()
+
+	token, err := SignHS256(claims, secret)
+	if err != nil {
+		t.Fatalf("SignHS256: %v", err)
+	}
+
+	return token
+}
+
+func signRS256(t *testing.T, claims *Claims, key *rsa.PrivateKey, kid string) string {
+	t.Helper()
+
+	h, err := encodeSegment(header{Alg: algRS256, Typ: "JWT", Kid: kid})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := encodeSegment(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := sha256.Sum256([]byte(h + "." + payload))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return h + "." + payload + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// writeJWKS сохраняет открытые ключи в JWKS-файл и возвращает путь к нему
+func writeJWKS(t *testing.T, keys map[string]*rsa.PublicKey) string {
+	t.Helper()
+
+	set := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+
+	for kid, key := range keys {
+		set.Keys = append(set.Keys, jwk{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: algRS256,
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func generateKey(t *testing.T, bits int) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func TestNewVerifier(t *testing.T) {
+	if _, err := NewVerifier(&Config{}); err == nil {
+		t.Error("verifier without keys must fail")
+	}
+
+	if _, err := NewVerifier(&Config{Secret: placeholderSecret}); err == nil {
+		t.Error("placeholder secret must be rejected")
+	}
+
+	if _, err := NewVerifier(&Config{Secret: testSecret}); err != nil {
+		t.Errorf("secret only: %v", err)
+	}
+}
+
+func TestVerifyHS256(t *testing.T) {
+	v := newTestVerifier(t, &Config{Secret: testSecret, Issuer: "issuer", Audience: "api"})
+
+	valid := func() *Claims {
+		c := testClaims()
+		c.Issuer = "issuer"
+		c.Audience = audience{"other", "api"}
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		token   func() string
+		wantErr error
+	}{
+		{
+			name:  "valid",
+			token: func() string { return signHS256(t, valid(), testSecret) },
+		},
+		{
+			name:    "wrong secret",
+			token:   func() string { return signHS256(t, valid(), "other-secret") },
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "expired",
+			token: func() string {
+				c := valid()
+				c.ExpiresAt = testNow.Add(-time.Minute).Unix()
+				return signHS256(t, c, testSecret)
+			},
+			wantErr: ErrExpiredToken,
+		},
+		{
+			name: "expired within leeway",
+			token: func() string {
+				c := valid()
+				c.ExpiresAt = testNow.Add(-10 * time.Second).Unix()
+				return signHS256(t, c, testSecret)
+			},
+		},
+		{
+			name: "not valid yet",
+			token: func() string {
+				c := valid()
+				c.NotBefore = testNow.Add(time.Minute).Unix()
+				return signHS256(t, c, testSecret)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "missing exp",
+			token: func() string {
+				c := valid()
+				c.ExpiresAt = 0
+				return signHS256(t, c, testSecret)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "missing sub",
+			token: func() string {
+				c := valid()
+				c.Subject = ""
+				return signHS256(t, c, testSecret)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				c := valid()
+				c.Issuer = "evil"
+				return signHS256(t, c, testSecret)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				c := valid()
+				c.Audience = audience{"other"}
+				return signHS256(t, c, testSecret)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "alg none",
+			token: func() string {
+				h, _ := encodeSegment(header{Alg: "none"})
+				payload, _ := encodeSegment(valid())
+				return h + "." + payload + "."
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "malformed",
+			token:   func() string { return "not-a-token" },
+			wantErr: ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := v.Verify(tt.token())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Verify error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+
+			if claims.Subject != "u1" || len(claims.Roles) != 1 || claims.Roles[0] != "USER" {
+				t.Errorf("unexpected claims %+v", claims)
+			}
+		})
+	}
+}
+
+func TestVerifyRS256(t *testing.T) {
+	key := generateKey(t, 2048)
+	other := generateKey(t, 2048)
+
+	v := newTestVerifier(t, &Config{JWKSFile: writeJWKS(t, map[string]*rsa.PublicKey{"k1": &key.PublicKey})})
+
+	if _, err := v.Verify(signRS256(t, testClaims(), key, "k1")); err != nil {
+		t.Errorf("valid token: %v", err)
+	}
+
+	if _, err := v.Verify(signRS256(t, testClaims(), key, "")); err != nil {
+		t.Errorf("token without kid and single key: %v", err)
+	}
+
+	if _, err := v.Verify(signRS256(t, testClaims(), key, "k2")); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("unknown kid: got %v", err)
+	}
+
+	if _, err := v.Verify(signRS256(t, testClaims(), other, "k1")); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("foreign key: got %v", err)
+	}
+
+	// без секрета HS256 отключен, в том числе с открытым ключом в роли секрета
+	if _, err := v.Verify(signHS256(t, testClaims(), string(key.PublicKey.N.Bytes()))); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("HS256 without secret: got %v", err)
+	}
+}
+
+func TestVerifyRS256RequiresKidWithSeveralKeys(t *testing.T) {
+	key := generateKey(t, 2048)
+	other := generateKey(t, 2048)
+
+	v := newTestVerifier(t, &Config{JWKSFile: writeJWKS(t, map[string]*rsa.PublicKey{
+		"k1": &key.PublicKey,
+		"k2": &other.PublicKey,
+	})})
+
+	if _, err := v.Verify(signRS256(t, testClaims(), other, "k2")); err != nil {
+		t.Errorf("token with kid: %v", err)
+	}
+
+	if _, err := v.Verify(signRS256(t, testClaims(), key, "")); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("token without kid: got %v", err)
+	}
+}
+
+func TestLoadJWKS(t *testing.T) {
+	if _, err := loadJWKS(writeJWKS(t, map[string]*rsa.PublicKey{"weak": &generateKey(t, 1024).PublicKey})); err == nil {
+		t.Error("key shorter than 2048 bits must be rejected")
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, []byte(`{"keys":[{"kty":"EC","kid":"ec"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loadJWKS(path); err == nil {
+		t.Error("jwks without RSA keys must be rejected")
+	}
+
+	if _, err := loadJWKS(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("missing file must be rejected")
+	}
+}
+
+func TestAudienceJSON(t *testing.T) {
+	var single, list audience
+	if err := json.Unmarshal([]byte(`"api"`), &single); err != nil || len(single) != 1 || single[0] != "api" {
+		t.Errorf("single audience = %v, %v", single, err)
+	}
+
+	if err := json.Unmarshal([]byte(`["a","b"]`), &list); err != nil || len(list) != 2 {
+		t.Errorf("audience list = %v, %v", list, err)
+	}
+}
@@ -1,24 +1,218 @@
 package config
 
 import (
+	"avito_test_task/internal/auth"
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/notify"
+	"avito_test_task/internal/publisher"
 	"avito_test_task/internal/repository/pg"
 	"context"
 	"log/slog"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+
+	defaultAppPort = "8080"
+
+	defaultAbsenceSyncInterval = time.Minute
+	defaultJWTLeeway           = 30 * time.Second
+
+	defaultWebhookInterval    = 5 * time.Second
+	defaultWebhookTimeout     = 10 * time.Second
+	defaultWebhookMaxAttempts = 8
+	defaultWebhookBackoff     = 10 * time.Second
+	defaultWebhookMaxBackoff  = time.Hour
+
+	defaultOutboxInterval    = time.Second
+	defaultOutboxTimeout     = 10 * time.Second
+	defaultOutboxMaxAttempts = 10
+	defaultOutboxBackoff     = 5 * time.Second
+	defaultOutboxMaxBackoff  = 30 * time.Minute
+	defaultOutboxRetention   = 7 * 24 * time.Hour
+
+	defaultEventFeedInterval = 500 * time.Millisecond
+	defaultSSEHeartbeat      = 15 * time.Second
+
+	defaultNotifyInterval    = 5 * time.Second
+	defaultNotifyTimeout     = 10 * time.Second
+	defaultNotifyMaxAttempts = 5
+	defaultNotifyBackoff     = 30 * time.Second
+	defaultNotifyMaxBackoff  = time.Hour
+	defaultSMTPFrom          = "reviews@avito-test-task.local"
+
+	defaultReviewSLAInterval = time.Minute
 )
 
 type Config struct {
 	Postgres *pg.Config
 	AppPort  string
+	Storage  string
+
+	// Auth ключи проверки JWT: секрет HS256 (APP_JWT_SECRET или JWT_SECRET в Vault)
+	// и/или JWKS-файл с ключами RS256
+	Auth *auth.Config
+
+	// AbsenceSyncInterval период синхронизации отсутствий, AbsenceReassign включает
+	// переназначение открытых ревью в начале отсутствия
+	AbsenceSyncInterval time.Duration
+	AbsenceReassign     bool
+
+	// WebhookInterval период отправки доставок вебхуков, WebhookTimeout таймаут одного запроса,
+	// WebhookRetry экспоненциальные повторы неудачных доставок
+	WebhookInterval time.Duration
+	WebhookTimeout  time.Duration
+	WebhookRetry    entity.RetryPolicy
+
+	// Outbox публикатор доменных событий (APP_OUTBOX_PUBLISHER: stdout, file или http),
+	// OutboxInterval период работы релея, OutboxRetry повторы неудачных публикаций,
+	// OutboxRetention срок хранения опубликованных событий
+	Outbox          *publisher.Config
+	OutboxInterval  time.Duration
+	OutboxRetry     entity.RetryPolicy
+	OutboxRetention time.Duration
+
+	// EventFeedInterval период чтения outbox для SSE-потоков, SSEHeartbeat период пингов в потоке
+	EventFeedInterval time.Duration
+	SSEHeartbeat      time.Duration
+
+	// NotifyInterval период рассылки уведомлений, NotifyTimeout таймаут одной отправки,
+	// NotifyRetry повторы неудачных отправок. SMTP сервер канала EMAIL: без APP_SMTP_ADDR
+	// канал отключен, пароль — APP_SMTP_PASSWORD или SMTP_PASSWORD в Vault
+	NotifyInterval time.Duration
+	NotifyTimeout  time.Duration
+	NotifyRetry    entity.RetryPolicy
+	SMTP           *notify.SMTPConfig
+
+	// ReviewSLAInterval период проверки SLA ревью
+	ReviewSLAInterval time.Duration
+
+	// RebalanceInterval период автоматического выравнивания нагрузки ревьюеров, 0 — выключено
+	RebalanceInterval time.Duration
 }
 
 func New(ctx context.Context) *Config {
 	cfg := Config{
 		Postgres: &pg.Config{},
+		AppPort:  os.Getenv("APP_PORT"),
+		Storage:  os.Getenv("APP_STORAGE"),
+		Auth: &auth.Config{
+			Secret:   os.Getenv("APP_JWT_SECRET"),
+			JWKSFile: os.Getenv("APP_JWT_JWKS_FILE"),
+			Issuer:   os.Getenv("APP_JWT_ISSUER"),
+			Audience: os.Getenv("APP_JWT_AUDIENCE"),
+		},
+		Outbox: &publisher.Config{
+			Kind: os.Getenv("APP_OUTBOX_PUBLISHER"),
+			File: os.Getenv("APP_OUTBOX_FILE"),
+			URL:  os.Getenv("APP_OUTBOX_URL"),
+		},
+		SMTP: &notify.SMTPConfig{
+			Addr:     os.Getenv("APP_SMTP_ADDR"),
+			From:     os.Getenv("APP_SMTP_FROM"),
+			Username: os.Getenv("APP_SMTP_USERNAME"),
+			Password: os.Getenv("APP_SMTP_PASSWORD"),
+		},
+	}
+
+	if cfg.Storage == "" {
+		cfg.Storage = StoragePostgres
+	}
+
+	if cfg.Storage == StoragePostgres {
+		if err := loadSecretsFromVault(ctx, &cfg); err != nil {
+			slog.Error(err.Error())
+		}
+	}
+
+	if cfg.AppPort == "" {
+		cfg.AppPort = defaultAppPort
 	}
 
-	if err := loadSecretsFromVault(ctx, &cfg); err != nil {
-		slog.Error(err.Error())
+	cfg.Auth.Leeway = durationEnv("APP_JWT_LEEWAY", defaultJWTLeeway)
+	cfg.AbsenceSyncInterval = durationEnv("APP_ABSENCE_SYNC_INTERVAL", defaultAbsenceSyncInterval)
+	cfg.AbsenceReassign = boolEnv("APP_ABSENCE_REASSIGN", false)
+	cfg.WebhookInterval = durationEnv("APP_WEBHOOK_INTERVAL", defaultWebhookInterval)
+	cfg.WebhookTimeout = durationEnv("APP_WEBHOOK_TIMEOUT", defaultWebhookTimeout)
+	cfg.WebhookRetry = entity.RetryPolicy{
+		MaxAttempts: intEnv("APP_WEBHOOK_MAX_ATTEMPTS", defaultWebhookMaxAttempts),
+		BaseDelay:   durationEnv("APP_WEBHOOK_BACKOFF", defaultWebhookBackoff),
+		MaxDelay:    durationEnv("APP_WEBHOOK_MAX_BACKOFF", defaultWebhookMaxBackoff),
+	}
+	cfg.Outbox.Timeout = durationEnv("APP_OUTBOX_TIMEOUT", defaultOutboxTimeout)
+	cfg.OutboxInterval = durationEnv("APP_OUTBOX_INTERVAL", defaultOutboxInterval)
+	cfg.OutboxRetry = entity.RetryPolicy{
+		MaxAttempts: intEnv("APP_OUTBOX_MAX_ATTEMPTS", defaultOutboxMaxAttempts),
+		BaseDelay:   durationEnv("APP_OUTBOX_BACKOFF", defaultOutboxBackoff),
+		MaxDelay:    durationEnv("APP_OUTBOX_MAX_BACKOFF", defaultOutboxMaxBackoff),
+	}
+	cfg.OutboxRetention = durationEnv("APP_OUTBOX_RETENTION", defaultOutboxRetention)
+	cfg.EventFeedInterval = durationEnv("APP_EVENT_FEED_INTERVAL", defaultEventFeedInterval)
+	cfg.SSEHeartbeat = durationEnv("APP_SSE_HEARTBEAT", defaultSSEHeartbeat)
+	cfg.NotifyInterval = durationEnv("APP_NOTIFY_INTERVAL", defaultNotifyInterval)
+	cfg.NotifyRetry = entity.RetryPolicy{
+		MaxAttempts: intEnv("APP_NOTIFY_MAX_ATTEMPTS", defaultNotifyMaxAttempts),
+		BaseDelay:   durationEnv("APP_NOTIFY_BACKOFF", defaultNotifyBackoff),
+		MaxDelay:    durationEnv("APP_NOTIFY_MAX_BACKOFF", defaultNotifyMaxBackoff),
+	}
+	cfg.NotifyTimeout = durationEnv("APP_NOTIFY_TIMEOUT", defaultNotifyTimeout)
+	cfg.SMTP.Timeout = cfg.NotifyTimeout
+	cfg.ReviewSLAInterval = durationEnv("APP_REVIEW_SLA_INTERVAL", defaultReviewSLAInterval)
+	cfg.RebalanceInterval = durationEnv("APP_REBALANCE_INTERVAL", 0)
+	if cfg.SMTP.From == "" {
+		cfg.SMTP.From = defaultSMTPFrom
 	}
 
 	return &cfg
 }
+
+// durationEnv читает длительность в формате time.ParseDuration, при ошибке возвращает def
+func durationEnv(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		slog.Error("invalid duration, using default", "key", key, "value", value, "default", def)
+		return def
+	}
+
+	return d
+}
+
+// intEnv читает положительное целое, при ошибке возвращает def
+func intEnv(key string, def int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		slog.Error("invalid integer, using default", "key", key, "value", value, "default", def)
+		return def
+	}
+
+	return n
+}
+
+func boolEnv(key string, def bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		slog.Error("invalid bool, using default", "key", key, "value", value, "default", def)
+		return def
+	}
+
+	return b
+}
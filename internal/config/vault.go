@@ -50,5 +50,13 @@ func loadSecretsFromVault(ctx context.Context, cfg *Config) error {
 		cfg.AppPort = v
 	}
 
+	if v, ok := data["JWT_SECRET"].(string); ok && v != "" && cfg.Auth.Secret == "" {
+		cfg.Auth.Secret = v
+	}
+
+	if v, ok := data["SMTP_PASSWORD"].(string); ok && v != "" && cfg.SMTP.Password == "" {
+		cfg.SMTP.Password = v
+	}
+
 	return nil
 }
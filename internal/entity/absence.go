@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// Absence период отсутствия пользователя [StartsAt, EndsAt): пока он идет, пользователь
+// не выбирается ревьюером. AppliedAt и ReleasedAt фиксирует фоновая синхронизация
+// на границах периода, Deactivated означает, что is_active сняла именно она
+type Absence struct {
+	ID          int64      `json:"absence_id"`
+	UserID      string     `json:"user_id"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	Reason      string     `json:"reason"`
+	Deactivated bool       `json:"deactivated"`
+	AppliedAt   *time.Time `json:"applied_at"`
+	ReleasedAt  *time.Time `json:"released_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Covers проверяет, что момент t попадает в период отсутствия
+func (a *Absence) Covers(t time.Time) bool {
+	return !t.Before(a.StartsAt) && t.Before(a.EndsAt)
+}
+
+// AbsenceSyncResult итог прохода синхронизации отсутствий: Processed — обработанные границы,
+// Failed — отсутствия, которые не удалось обработать; они повторяются следующим проходом
+type AbsenceSyncResult struct {
+	Processed int `json:"processed"`
+	Failed    int `json:"failed"`
+}
+
+var ErrAbsenceNotFound = NewError(NotFound, "absence not found")
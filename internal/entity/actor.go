@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+const (
+	RoleAdmin  = "ADMIN"
+	RoleUser   = "USER"
+	RoleSystem = "SYSTEM"
+	RoleAPIKey = "API_KEY"
+
+	// SystemActor инициатор изменений, сделанных фоновыми задачами
+	SystemActor = "system"
+)
+
+// TokenRoles роли, которые может выдать JWT; SYSTEM и API_KEY назначает только сам сервис,
+// TEAM_LEAD — только привязка роли
+var TokenRoles = []string{RoleAdmin, RoleUser}
+
+// Actor аутентифицированный инициатор запроса: user_id и роли из токена. Команды, в которых
+// действуют его права, берутся из привязок ролей, а не из токена. Для API-ключа права
+// ограничены его Scopes
+type Actor struct {
+	ID     string
+	Roles  []string
+	Scopes []Permission
+}
+
+// IsAPIKey проверяет, что запрос выполнен по API-ключу
+func (a Actor) IsAPIKey() bool {
+	return a.HasRole(RoleAPIKey)
+}
+
+// IsAnonymous проверяет, что инициатор не определен: запрос не прошел аутентификацию
+func (a Actor) IsAnonymous() bool {
+	return a.ID == ""
+}
+
+// HasRole проверяет, что у инициатора есть роль role
+func (a Actor) HasRole(role string) bool {
+	return slices.Contains(a.Roles, role)
+}
+
+// Role роли инициатора через запятую, для журналов изменений
+func (a Actor) Role() string {
+	return strings.Join(a.Roles, ",")
+}
+
+type actorKey struct{}
+
+// WithActor сохраняет в контексте инициатора запроса
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// WithSystemActor сохраняет в контексте системного инициатора для фоновых задач
+func WithSystemActor(ctx context.Context) context.Context {
+	return WithActor(ctx, Actor{ID: SystemActor, Roles: []string{RoleSystem}})
+}
+
+// ActorFromContext возвращает инициатора запроса или анонимного без ролей, если он не задан
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorKey{}).(Actor); ok {
+		return actor
+	}
+
+	return Actor{}
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// reservedPrefixes диапазоны, не попадающие под проверки netip, но не маршрутизируемые
+// в интернет: "этот" хост, CGNAT, служебные IANA, бенчмарки, зарезервированные и NAT64
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// IsPublicAddr проверяет, что адрес публичный: не loopback, не частная сеть, не link-local,
+// не multicast и не зарезервированный диапазон. Исходящие запросы по адресам пользователей
+// разрешены только на публичные адреса, чтобы через них нельзя было достучаться до внутренней сети
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsPublicHost проверяет имя хоста из URL до разрешения DNS: IP-адрес должен быть публичным,
+// localhost и его поддомены запрещены. Имя, которое разрешится во внутренний адрес,
+// отсекается только при подключении
+func IsPublicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		return IsPublicAddr(addr)
+	}
+
+	return true
+}
@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "93.184.216.34", want: true},
+		{addr: "2606:4700::6810:84e5", want: true},
+		{addr: "127.0.0.1"},
+		{addr: "::1"},
+		{addr: "10.1.2.3"},
+		{addr: "172.16.0.1"},
+		{addr: "192.168.1.1"},
+		{addr: "169.254.169.254"},
+		{addr: "fe80::1"},
+		{addr: "fd00::1"},
+		{addr: "0.0.0.0"},
+		{addr: "100.64.0.1"},
+		{addr: "224.0.0.1"},
+		{addr: "255.255.255.255"},
+		{addr: "::ffff:127.0.0.1"},
+		{addr: "64:ff9b::a9fe:a9fe"},
+	}
+
+	for _, tt := range tests {
+		if got := IsPublicAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("IsPublicAddr(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestIsPublicHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{host: "chat.example.com", want: true},
+		{host: "93.184.216.34", want: true},
+		{host: "localhost"},
+		{host: "LOCALHOST."},
+		{host: "api.localhost"},
+		{host: "127.0.0.1"},
+		{host: "[::1]"},
+		{host: "169.254.169.254"},
+		{host: ""},
+	}
+
+	for _, tt := range tests {
+		if got := IsPublicHost(tt.host); got != tt.want {
+			t.Errorf("IsPublicHost(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
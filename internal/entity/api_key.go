@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"strconv"
+	"time"
+)
+
+// APIKeyPrefix префикс API-ключей, по нему ключ отличается от JWT в заголовке Authorization
+const APIKeyPrefix = "avk_"
+
+// APIKey ключ сервисного клиента. Хранится только SHA-256 ключа, сам ключ отдается один раз
+// при создании; Prefix — его начало для опознания ключа в списке
+type APIKey struct {
+	ID         int64        `json:"id"`
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix"`
+	Hash       string       `json:"-"`
+	Scopes     []Permission `json:"scopes"`
+	ExpiresAt  *time.Time   `json:"expires_at"`
+	LastUsedAt *time.Time   `json:"last_used_at"`
+	RevokedAt  *time.Time   `json:"revoked_at"`
+	CreatedBy  string       `json:"created_by"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// IsUsable проверяет, что ключ не отозван и не истек к моменту at
+func (k *APIKey) IsUsable(at time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || at.Before(*k.ExpiresAt))
+}
+
+// ActorID идентификатор ключа как инициатора в журналах. Строится по ID, а не по имени:
+// имя отозванного ключа может достаться новому ключу
+func (k *APIKey) ActorID() string {
+	return "apikey:" + strconv.FormatInt(k.ID, 10)
+}
+
+var ErrAPIKeyNotFound = NewError(NotFound, "api key not found")
@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+// AssignmentEvent тип события в истории назначений ревьюеров
+type AssignmentEvent string
+
+const (
+	ReviewerAssigned   AssignmentEvent = "ASSIGNED"
+	ReviewerUnassigned AssignmentEvent = "UNASSIGNED"
+)
+
+// AssignmentReason причина назначения или снятия ревьюера
+type AssignmentReason string
+
+const (
+	ReasonPRCreated        AssignmentReason = "PR_CREATED"
+	ReasonPROpened         AssignmentReason = "PR_OPENED"
+	ReasonManualReassign   AssignmentReason = "MANUAL_REASSIGN"
+	ReasonUserDeactivated  AssignmentReason = "USER_DEACTIVATED"
+	ReasonBulkDeactivation AssignmentReason = "BULK_DEACTIVATION"
+	ReasonAbsence          AssignmentReason = "ABSENCE"
+	ReasonTeamChange       AssignmentReason = "TEAM_CHANGE"
+	ReasonSLAEscalation    AssignmentReason = "SLA_ESCALATION"
+	ReasonRebalance        AssignmentReason = "REBALANCE"
+	// ReasonBackfill назначения, существовавшие до появления истории
+	ReasonBackfill AssignmentReason = "BACKFILL"
+)
+
+// AssignmentHistory запись append-only истории назначений ревьюеров
+type AssignmentHistory struct {
+	ID            int64            `json:"id"`
+	PullRequestID string           `json:"pull_request_id"`
+	UserID        string           `json:"user_id"`
+	Event         AssignmentEvent  `json:"event"`
+	Reason        AssignmentReason `json:"reason"`
+	Actor         string           `json:"actor"`
+	// ReplacedUserID ревьюер, вместо которого назначен пользователь при переназначении
+	ReplacedUserID string    `json:"replaced_user_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// AssignmentHistoryFilter фильтр истории назначений: по PR, по пользователю или по обоим
+type AssignmentHistoryFilter struct {
+	PullRequestID string
+	UserID        string
+}
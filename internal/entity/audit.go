@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+// AuditAction административное действие в журнале аудита
+type AuditAction string
+
+const (
+	AuditCreateTeam          AuditAction = "CREATE_TEAM"
+	AuditSetReviewerStrategy AuditAction = "SET_REVIEWER_STRATEGY"
+	AuditUpdateTeamSettings  AuditAction = "UPDATE_TEAM_SETTINGS"
+	AuditAddTeamMembers      AuditAction = "ADD_TEAM_MEMBERS"
+	AuditRemoveTeamMember    AuditAction = "REMOVE_TEAM_MEMBER"
+	AuditMoveTeamMember      AuditAction = "MOVE_TEAM_MEMBER"
+	AuditRenameTeam          AuditAction = "RENAME_TEAM"
+	AuditDeleteTeam          AuditAction = "DELETE_TEAM"
+	AuditRebalanceTeam       AuditAction = "REBALANCE_TEAM"
+	AuditSetIsActive         AuditAction = "SET_IS_ACTIVE"
+	AuditDeactivateUsers     AuditAction = "DEACTIVATE_USERS"
+	AuditCreateAbsence       AuditAction = "CREATE_ABSENCE"
+	AuditDeleteAbsence       AuditAction = "DELETE_ABSENCE"
+	AuditCreatePR            AuditAction = "CREATE_PR"
+	AuditMergePR             AuditAction = "MERGE_PR"
+	AuditReassignPR          AuditAction = "REASSIGN_PR"
+	AuditReviewPR            AuditAction = "REVIEW_PR"
+	AuditMarkReadyPR         AuditAction = "MARK_READY_PR"
+	AuditClosePR             AuditAction = "CLOSE_PR"
+	AuditReopenPR            AuditAction = "REOPEN_PR"
+	AuditBindRole            AuditAction = "BIND_ROLE"
+	AuditUnbindRole          AuditAction = "UNBIND_ROLE"
+	AuditCreateAPIKey        AuditAction = "CREATE_API_KEY"
+	AuditRevokeAPIKey        AuditAction = "REVOKE_API_KEY"
+	AuditCreateWebhook       AuditAction = "CREATE_WEBHOOK"
+	AuditDeleteWebhook       AuditAction = "DELETE_WEBHOOK"
+)
+
+// AuditEntityType тип сущности, над которой выполнено действие
+type AuditEntityType string
+
+const (
+	AuditTeam        AuditEntityType = "TEAM"
+	AuditUser        AuditEntityType = "USER"
+	AuditAbsence     AuditEntityType = "ABSENCE"
+	AuditPullRequest AuditEntityType = "PULL_REQUEST"
+	AuditRoleBinding AuditEntityType = "ROLE_BINDING"
+	AuditAPIKey      AuditEntityType = "API_KEY"
+	AuditWebhook     AuditEntityType = "WEBHOOK"
+)
+
+// AuditOutcome результат действия
+type AuditOutcome string
+
+const (
+	AuditSuccess AuditOutcome = "SUCCESS"
+	AuditFailure AuditOutcome = "FAILURE"
+)
+
+// AuditEntry запись журнала аудита. Успешные действия записываются в транзакции изменения,
+// неудачные — отдельно после ее отката, с кодом ошибки
+type AuditEntry struct {
+	ID          int64           `json:"id"`
+	Actor       string          `json:"actor"`
+	Role        string          `json:"role"`
+	Action      AuditAction     `json:"action"`
+	EntityType  AuditEntityType `json:"entity_type"`
+	EntityID    string          `json:"entity_id"`
+	Endpoint    string          `json:"endpoint"`
+	PayloadHash string          `json:"payload_hash"`
+	Outcome     AuditOutcome    `json:"outcome"`
+	ErrorCode   ErrorCode       `json:"error_code,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// AuditFilter фильтр журнала аудита; записи отдаются от новых к старым, курсор — ID записи
+type AuditFilter struct {
+	Actor      string
+	EntityType AuditEntityType
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+	Page
+}
+
+// RequestInfo сведения о HTTP-запросе для журнала аудита
+type RequestInfo struct {
+	Endpoint    string
+	PayloadHash string
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo сохраняет в контексте сведения о запросе
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext возвращает сведения о запросе; вне HTTP-запроса они пустые
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
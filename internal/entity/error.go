@@ -1,19 +1,108 @@
 package entity
 
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorResponse тело ответа с ошибкой
 type ErrorResponse struct {
-	ErrorCode int       `json:"error_code"`
-	Message   ErrorCode `json:"message"`
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
 }
 
+// ErrorCode код доменной ошибки, сам по себе является ошибкой и используется
+// как цель для errors.Is
 type ErrorCode string
 
 const (
-	TeamExists     ErrorCode = "TEAM_EXISTS"
-	PRExists       ErrorCode = "PR_EXISTS"
-	PRMerged       ErrorCode = "PR_MERGED"
-	NotAssigned    ErrorCode = "NOT_ASSIGNED"
-	NoCandidate    ErrorCode = "NO_CANDIDATE"
-	NotFound       ErrorCode = "NOT_FOUND"
-	InvalidRequest ErrorCode = "INVALID_REQUEST"
-	InternalServer ErrorCode = "INTERNAL_SERVER_ERROR"
+	TeamExists        ErrorCode = "TEAM_EXISTS"
+	PRExists          ErrorCode = "PR_EXISTS"
+	PRMerged          ErrorCode = "PR_MERGED"
+	NotAssigned       ErrorCode = "NOT_ASSIGNED"
+	NoCandidate       ErrorCode = "NO_CANDIDATE"
+	NotFound          ErrorCode = "NOT_FOUND"
+	PolicyViolated    ErrorCode = "POLICY_VIOLATION"
+	NotApproved       ErrorCode = "NOT_APPROVED"
+	PRNotOpen         ErrorCode = "PR_NOT_OPEN"
+	InvalidTransition ErrorCode = "INVALID_TRANSITION"
+	MemberExists      ErrorCode = "MEMBER_EXISTS"
+	TeamNotEmpty      ErrorCode = "TEAM_NOT_EMPTY"
+	BindingExists     ErrorCode = "BINDING_EXISTS"
+	APIKeyExists      ErrorCode = "API_KEY_EXISTS"
+	InvalidRequest    ErrorCode = "INVALID_REQUEST"
+	Unauthorized      ErrorCode = "UNAUTHORIZED"
+	Forbidden         ErrorCode = "FORBIDDEN"
+	InternalServer    ErrorCode = "INTERNAL_SERVER_ERROR"
+)
+
+func (c ErrorCode) Error() string {
+	return string(c)
+}
+
+// Error доменная ошибка с кодом и сообщением для клиента
+type Error struct {
+	Code    ErrorCode
+	Message string
+	Err     error
+}
+
+// NewError создает доменную ошибку
+func NewError(code ErrorCode, format string, args ...any) *Error {
+	return &Error{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// WrapError создает доменную ошибку поверх исходной
+func WrapError(code ErrorCode, err error, format string, args ...any) *Error {
+	return &Error{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+		Err:     err,
+	}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is позволяет сравнивать ошибку с кодом: errors.Is(err, entity.NotFound)
+func (e *Error) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && code == e.Code
+}
+
+// CodeOf возвращает код доменной ошибки или INTERNAL_SERVER_ERROR
+func CodeOf(err error) ErrorCode {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+
+	var code ErrorCode
+	if errors.As(err, &code) {
+		return code
+	}
+
+	return InternalServer
+}
+
+var (
+	ErrTeamNotFound = NewError(NotFound, "team not found")
+	ErrUserNotFound = NewError(NotFound, "user not found")
+	ErrPRNotFound   = NewError(NotFound, "PR not found")
 )
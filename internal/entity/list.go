@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// Cursor позиция последнего элемента страницы. Списки упорядочены по уникальному ключу ID,
+// списки PR — по (CreatedAt DESC, ID DESC)
+type Cursor struct {
+	CreatedAt *time.Time `json:"t,omitempty"`
+	ID        string     `json:"id"`
+}
+
+// Encode возвращает непрозрачное строковое представление курсора
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor разбирает курсор из запроса; пустая строка означает первую страницу
+func DecodeCursor(value string) (*Cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, WrapError(InvalidRequest, err, "invalid cursor")
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, NewError(InvalidRequest, "invalid cursor")
+	}
+
+	return &c, nil
+}
+
+// Page параметры страницы: After — курсор последнего элемента предыдущей страницы
+type Page struct {
+	Limit int
+	After *Cursor
+}
+
+// TeamFilter фильтр списка команд
+type TeamFilter struct {
+	NameContains string
+	Page
+}
+
+// UserFilter фильтр списка пользователей; NameContains ищет по user_id и username
+type UserFilter struct {
+	TeamName     string
+	IsActive     *bool
+	NameContains string
+	Page
+}
+
+// PRFilter фильтр списка PR; TeamName — команда автора, интервалы полуоткрытые [From, To)
+type PRFilter struct {
+	TeamName     string
+	Status       string
+	AuthorID     string
+	ReviewerID   string
+	NameContains string
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	MergedFrom   *time.Time
+	MergedTo     *time.Time
+	Page
+}
+
+// TeamSummary краткие сведения о команде для списка
+type TeamSummary struct {
+	Name             string           `json:"team_name"`
+	ReviewerStrategy ReviewerStrategy `json:"reviewer_strategy"`
+	Members          int              `json:"members"`
+	ActiveMembers    int              `json:"active_members"`
+	CreatedAt        time.Time        `json:"created_at"`
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	c, err := DecodeCursor(Cursor{CreatedAt: &now, ID: "pr1"}.Encode())
+	if err != nil || c.ID != "pr1" || c.CreatedAt == nil || !c.CreatedAt.Equal(now) {
+		t.Fatalf("round trip = %+v, %v", c, err)
+	}
+
+	if c, err := DecodeCursor(""); c != nil || err != nil {
+		t.Errorf("empty cursor = %+v, %v, want first page", c, err)
+	}
+
+	tampered := []struct {
+		name  string
+		value string
+	}{
+		{"not base64", "!!!"},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("pr1"))},
+		{"without id", base64.RawURLEncoding.EncodeToString([]byte(`{"t":"2024-01-01T00:00:00Z"}`))},
+		{"bad time", base64.RawURLEncoding.EncodeToString([]byte(`{"t":"yesterday","id":"pr1"}`))},
+		{"padded", base64.URLEncoding.EncodeToString([]byte(`{"id":"u1"}`))},
+	}
+
+	for _, tt := range tampered {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.value); CodeOf(err) != InvalidRequest {
+				t.Errorf("DecodeCursor(%q) error = %v, want %s", tt.value, err, InvalidRequest)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// NotificationChannel канал доставки уведомлений
+type NotificationChannel string
+
+const (
+	ChannelEmail NotificationChannel = "EMAIL"
+	ChannelChat  NotificationChannel = "CHAT"
+)
+
+// NotificationChannels все каналы уведомлений
+var NotificationChannels = []NotificationChannel{ChannelEmail, ChannelChat}
+
+// IsValid проверяет, что канал известен
+func (c NotificationChannel) IsValid() bool {
+	return c == ChannelEmail || c == ChannelChat
+}
+
+// QuietHours тихие часы пользователя: с Start до End ("HH:MM") в часовом поясе Timezone
+// уведомления не отправляются и откладываются до End. Окно может переходить через полночь
+type QuietHours struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone"`
+}
+
+// Validate проверяет формат времени и часовой пояс
+func (q *QuietHours) Validate() error {
+	if _, err := parseClock(q.Start); err != nil {
+		return err
+	}
+
+	if _, err := parseClock(q.End); err != nil {
+		return err
+	}
+
+	if _, err := time.LoadLocation(q.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", q.Timezone)
+	}
+
+	return nil
+}
+
+// Defer возвращает момент, когда можно отправить уведомление, возникшее в t:
+// само t вне тихих часов или их ближайший конец
+func (q *QuietHours) Defer(t time.Time) time.Time {
+	start, errStart := parseClock(q.Start)
+	end, errEnd := parseClock(q.End)
+	loc, errLoc := time.LoadLocation(q.Timezone)
+	if errStart != nil || errEnd != nil || errLoc != nil || start == end {
+		return t
+	}
+
+	local := t.In(loc)
+	now := local.Hour()*60 + local.Minute()
+
+	var quiet bool
+	if start < end {
+		quiet = now >= start && now < end
+	} else {
+		quiet = now >= start || now < end
+	}
+
+	if !quiet {
+		return t
+	}
+
+	until := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, loc)
+	if !until.After(local) {
+		until = until.AddDate(0, 0, 1)
+	}
+
+	return until
+}
+
+// parseClock переводит "HH:MM" в минуты от начала суток
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// NotificationPreferences настройки уведомлений пользователя: включенные каналы, адреса
+// и тихие часы. Без настроек уведомления пользователю не отправляются
+type NotificationPreferences struct {
+	UserID         string                `json:"user_id"`
+	Email          string                `json:"email,omitempty"`
+	ChatWebhookURL string                `json:"chat_webhook_url,omitempty"`
+	Channels       []NotificationChannel `json:"channels"`
+	QuietHours     *QuietHours           `json:"quiet_hours"`
+	UpdatedAt      *time.Time            `json:"updated_at"`
+}
+
+// Address адрес пользователя в канале
+func (p *NotificationPreferences) Address(channel NotificationChannel) string {
+	switch channel {
+	case ChannelEmail:
+		return p.Email
+	case ChannelChat:
+		return p.ChatWebhookURL
+	default:
+		return ""
+	}
+}
+
+// NotificationStatus состояние уведомления
+type NotificationStatus string
+
+const (
+	NotificationPending  NotificationStatus = "PENDING"
+	NotificationRetrying NotificationStatus = "RETRYING"
+	NotificationSent     NotificationStatus = "SENT"
+	NotificationFailed   NotificationStatus = "FAILED"
+)
+
+// NotificationStatuses все состояния уведомления
+var NotificationStatuses = []NotificationStatus{NotificationPending, NotificationRetrying, NotificationSent, NotificationFailed}
+
+// Notification уведомление пользователя о событии в одном канале. Текст рендерится
+// шаблоном канала из Payload при отправке; ScheduledAt учитывает тихие часы
+type Notification struct {
+	ID          int64               `json:"id"`
+	UserID      string              `json:"user_id"`
+	Channel     NotificationChannel `json:"channel"`
+	Address     string              `json:"address"`
+	EventKey    string              `json:"event_key"`
+	EventType   EventType           `json:"event_type"`
+	Payload     json.RawMessage     `json:"payload"`
+	Status      NotificationStatus  `json:"status"`
+	Attempts    int                 `json:"attempts"`
+	LastError   string              `json:"last_error,omitempty"`
+	ScheduledAt time.Time           `json:"scheduled_at"`
+	CreatedAt   time.Time           `json:"created_at"`
+	SentAt      *time.Time          `json:"sent_at"`
+}
+
+// NotificationFilter фильтр журнала уведомлений; записи от новых к старым, курсор — ID
+type NotificationFilter struct {
+	UserID string
+	Status NotificationStatus
+	Page
+}
+
+var ErrPreferencesNotFound = NewError(NotFound, "notification preferences not found")
+
+// NotificationRecipients пользователи, которых уведомляют о событии: назначенный и снятый
+// ревьюер, ревьюер, которому напоминают о ревью, а о мерже — ревьюеры и автор PR.
+// Для остальных событий — nil
+func (e *OutboxEvent) NotificationRecipients() []string {
+	users := e.ReviewQueueUsers()
+	if e.Type == EventPRMerged || e.Type == EventReviewerReminded {
+		var event struct {
+			Data struct {
+				AuthorID   string `json:"author_id"`
+				ReviewerID string `json:"reviewer_id"`
+			} `json:"data"`
+		}
+
+		if err := json.Unmarshal(e.Payload, &event); err == nil {
+			for _, id := range []string{event.Data.AuthorID, event.Data.ReviewerID} {
+				if id != "" {
+					users = append(users, id)
+				}
+			}
+		}
+	}
+
+	recipients := make([]string, 0, len(users))
+	for _, id := range users {
+		if !slices.Contains(recipients, id) {
+			recipients = append(recipients, id)
+		}
+	}
+
+	return recipients
+}
@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEvent доменное событие в outbox: записывается в транзакции изменения и публикуется
+// релеем. Key — ключ идемпотентности (ID события), по нему получатели отбрасывают повторы:
+// доставка гарантируется не менее одного раза. NextAttemptAt — срок следующей попытки,
+// FailedAt — момент, когда событие исчерпало попытки и больше не публикуется
+type OutboxEvent struct {
+	ID            int64           `json:"id"`
+	Key           string          `json:"key"`
+	Type          EventType       `json:"type"`
+	TeamName      string          `json:"team_name"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+	PublishedAt   *time.Time      `json:"published_at"`
+	FailedAt      *time.Time      `json:"failed_at,omitempty"`
+
+	// TxID транзакция, записавшая событие; вместе с ID задает порядок чтения outbox
+	TxID int64 `json:"-"`
+}
+
+// OutboxPosition позиция чтения outbox. ID событий выдаются в порядке записи, а не фиксации
+// транзакций, поэтому события читаются по паре (TxID, ID) и только из завершенных транзакций:
+// событие, зафиксированное позже, не окажется позади уже прочитанной позиции
+type OutboxPosition struct {
+	TxID int64
+	ID   int64
+}
+
+// Before проверяет, что позиция p предшествует q
+func (p OutboxPosition) Before(q OutboxPosition) bool {
+	if p.TxID != q.TxID {
+		return p.TxID < q.TxID
+	}
+
+	return p.ID < q.ID
+}
+
+// Position позиция события в порядке чтения outbox
+func (e *OutboxEvent) Position() OutboxPosition {
+	return OutboxPosition{TxID: e.TxID, ID: e.ID}
+}
+
+// ReviewQueueUsers пользователи, чью очередь ревью меняет событие: назначенный и снятый
+// ревьюер, ревьюеры смерженного PR. Для остальных событий — nil
+func (e *OutboxEvent) ReviewQueueUsers() []string {
+	if e.Type != EventReviewerAssigned && e.Type != EventReviewerReassigned && e.Type != EventPRMerged {
+		return nil
+	}
+
+	var event struct {
+		Data struct {
+			ReviewerID    string   `json:"reviewer_id"`
+			OldReviewerID string   `json:"old_reviewer_id"`
+			Reviewers     []string `json:"reviewers"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(e.Payload, &event); err != nil {
+		return nil
+	}
+
+	users := event.Data.Reviewers
+	for _, id := range []string{event.Data.ReviewerID, event.Data.OldReviewerID} {
+		if id != "" {
+			users = append(users, id)
+		}
+	}
+
+	return users
+}
+
+// Concerns проверяет, что событие меняет очередь ревью пользователя userID
+func (e *OutboxEvent) Concerns(userID string) bool {
+	for _, id := range e.ReviewQueueUsers() {
+		if id == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+var ErrOutboxEventNotFound = NewError(NotFound, "outbox event not found")
@@ -8,8 +8,10 @@ type PullRequest struct {
 	AuthorID        string
 	Status          string
 	AssignReviewers []string
+	Reviews         []*Review
 	CreatedAt       *time.Time
 	MergedAt        *time.Time
+	ClosedAt        *time.Time
 }
 
 type PullRequestShort struct {
@@ -20,6 +22,92 @@ type PullRequestShort struct {
 }
 
 const (
+	DRAFT  = "DRAFT"
 	OPEN   = "OPEN"
 	MERGED = "MERGED"
+	CLOSED = "CLOSED"
 )
+
+// Review решение ревьюера по pull request
+type Review struct {
+	ReviewerID string         `json:"reviewer_id"`
+	Decision   ReviewDecision `json:"decision"`
+	Comment    string         `json:"comment,omitempty"`
+	CreatedAt  *time.Time     `json:"created_at"`
+}
+
+type ReviewDecision string
+
+const (
+	Approved         ReviewDecision = "APPROVED"
+	ChangesRequested ReviewDecision = "CHANGES_REQUESTED"
+	Commented        ReviewDecision = "COMMENTED"
+)
+
+// IsValid проверяет, что решение известно
+func (d ReviewDecision) IsValid() bool {
+	switch d {
+	case Approved, ChangesRequested, Commented:
+		return true
+	}
+
+	return false
+}
+
+// LatestDecisions возвращает последнее значимое решение (APPROVED или CHANGES_REQUESTED)
+// каждого назначенного ревьюера reviewers; комментарии решение не меняют. Решения, принятые
+// до текущего назначения ревьюера (например, до того, как его сняли и назначили снова), не учитываются
+func (pr *PullRequest) LatestDecisions(reviewers []*AssignedReviewer) map[string]ReviewDecision {
+	assignedAt := make(map[string]time.Time, len(reviewers))
+	for _, r := range reviewers {
+		assignedAt[r.UserID] = r.AssignedAt
+	}
+
+	decisions := make(map[string]ReviewDecision)
+	for _, r := range pr.Reviews {
+		since, assigned := assignedAt[r.ReviewerID]
+		if !assigned || r.Decision == Commented || r.CreatedAt == nil || r.CreatedAt.Before(since) {
+			continue
+		}
+
+		decisions[r.ReviewerID] = r.Decision
+	}
+
+	return decisions
+}
+
+// ReviewReassignment результат переназначения ревью при выводе пользователя из ротации;
+// если замены нет, NewReviewerID пуст, а Error равен NO_CANDIDATE
+type ReviewReassignment struct {
+	PullRequestID string    `json:"pull_request_id"`
+	OldReviewerID string    `json:"old_reviewer_id"`
+	NewReviewerID string    `json:"new_reviewer_id,omitempty"`
+	Error         ErrorCode `json:"error,omitempty"`
+}
+
+// AssignedReviewer ревьюер PR со временем назначения
+type AssignedReviewer struct {
+	UserID     string    `json:"user_id"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+// Reassignment запись о замене ревьюера PR
+type Reassignment struct {
+	OldReviewerID string    `json:"old_reviewer_id"`
+	NewReviewerID string    `json:"new_reviewer_id"`
+	ReassignedAt  time.Time `json:"reassigned_at"`
+}
+
+// PullRequestDetails PR со временем назначения ревьюеров, решениями и историей переназначений
+type PullRequestDetails struct {
+	ID            string              `json:"pull_request_id"`
+	Name          string              `json:"pull_request_name"`
+	AuthorID      string              `json:"author_id"`
+	Status        string              `json:"status"`
+	Reviewers     []*AssignedReviewer `json:"reviewers"`
+	Reviews       []*Review           `json:"reviews"`
+	Reassignments []*Reassignment     `json:"reassignments"`
+	CreatedAt     *time.Time          `json:"created_at"`
+	MergedAt      *time.Time          `json:"merged_at"`
+	ClosedAt      *time.Time          `json:"closed_at"`
+}
@@ -0,0 +1,113 @@
+package entity
+
+import "time"
+
+// Permission право на действие в сервисе
+type Permission string
+
+const (
+	PermTeamRead       Permission = "team:read"
+	PermTeamManage     Permission = "team:manage"
+	PermUserRead       Permission = "user:read"
+	PermUserDeactivate Permission = "user:deactivate"
+	PermPRRead         Permission = "pr:read"
+	PermPRCreate       Permission = "pr:create"
+	PermPRMerge        Permission = "pr:merge"
+	PermPRReassign     Permission = "pr:reassign"
+	PermPRReview       Permission = "pr:review"
+	PermPRUpdate       Permission = "pr:update"
+	PermStatsRead      Permission = "stats:read"
+	PermAuditRead      Permission = "audit:read"
+	PermRBACManage     Permission = "rbac:manage"
+	PermAPIKeyManage   Permission = "apikey:manage"
+	PermWebhookManage  Permission = "webhook:manage"
+	PermNotifyManage   Permission = "notification:manage"
+
+	// Права на действия со своими объектами: PR, где вызывающий автор, его собственные ревью
+	// и его настройки уведомлений
+	PermPRCreateOwn   Permission = "pr:create:own"
+	PermPRMergeOwn    Permission = "pr:merge:own"
+	PermPRReassignOwn Permission = "pr:reassign:own"
+	PermPRReviewOwn   Permission = "pr:review:own"
+	PermNotifyOwn     Permission = "notification:manage:own"
+)
+
+// OwnPermissions право на свои объекты, которое заменяет общее право, если его нет
+var OwnPermissions = map[Permission]Permission{
+	PermPRCreate:     PermPRCreateOwn,
+	PermPRMerge:      PermPRMergeOwn,
+	PermPRReassign:   PermPRReassignOwn,
+	PermPRReview:     PermPRReviewOwn,
+	PermNotifyManage: PermNotifyOwn,
+}
+
+// Permissions все права сервиса
+var Permissions = []Permission{
+	PermTeamRead, PermTeamManage, PermUserRead, PermUserDeactivate,
+	PermPRRead, PermPRCreate, PermPRMerge, PermPRReassign, PermPRReview, PermPRUpdate,
+	PermStatsRead, PermAuditRead, PermRBACManage, PermAPIKeyManage, PermWebhookManage,
+	PermNotifyManage, PermPRCreateOwn, PermPRMergeOwn, PermPRReassignOwn, PermPRReviewOwn,
+	PermNotifyOwn,
+}
+
+// RoleTeamLead роль лида команды; выдается привязкой к команде
+const RoleTeamLead = "TEAM_LEAD"
+
+var readPermissions = []Permission{PermTeamRead, PermUserRead, PermPRRead, PermStatsRead}
+
+// RolePermissions права встроенных ролей. Роли из токена действуют глобально,
+// роли из привязок — глобально или в пределах одной команды
+var RolePermissions = map[string][]Permission{
+	RoleAdmin: Permissions,
+	RoleUser: append([]Permission{
+		PermPRReviewOwn, PermPRCreateOwn, PermPRMergeOwn, PermPRReassignOwn, PermNotifyOwn,
+	}, readPermissions...),
+	RoleTeamLead: append([]Permission{
+		PermUserDeactivate, PermPRCreate, PermPRMerge, PermPRReassign, PermPRReviewOwn, PermPRUpdate,
+	}, readPermissions...),
+}
+
+// RoleGrants проверяет, что роль дает право perm
+func RoleGrants(role string, perm Permission) bool {
+	for _, p := range RolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RoleBinding выдача роли пользователю: глобально (пустой TeamName) или в команде
+type RoleBinding struct {
+	ID        int64     `json:"id"`
+	UserID    string    `json:"user_id"`
+	Role      string    `json:"role"`
+	TeamName  string    `json:"team_name,omitempty"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RoleBindingFilter фильтр привязок ролей
+type RoleBindingFilter struct {
+	UserID   string
+	TeamName string
+}
+
+// AuthTarget объекты запроса, по которым определяются команды для проверки прав:
+// команды заданы явно или через пользователей, PR (команда автора) и отсутствия.
+// OwnerIDs — пользователи, от имени которых действует запрос; без них владелец — автор PR
+type AuthTarget struct {
+	Teams     []string
+	UserIDs   []string
+	PRID      string
+	AbsenceID int64
+	OwnerIDs  []string
+}
+
+// IsEmpty проверяет, что запрос не относится ни к одной команде
+func (t AuthTarget) IsEmpty() bool {
+	return len(t.Teams) == 0 && len(t.UserIDs) == 0 && t.PRID == "" && t.AbsenceID == 0
+}
+
+var ErrBindingNotFound = NewError(NotFound, "role binding not found")
@@ -0,0 +1,22 @@
+package entity
+
+// RebalanceMove передача одного открытого ревью от перегруженного участника команды недогруженному
+type RebalanceMove struct {
+	PullRequestID string `json:"pull_request_id"`
+	FromUserID    string `json:"from_user_id"`
+	ToUserID      string `json:"to_user_id"`
+}
+
+// Rebalance результат перераспределения нагрузки команды: число открытых ревью активных
+// участников до и после, целевое распределение и минимальный набор передач до него.
+// Передача невозможна, если ревьюер уже принял решение, обязателен как тимлид или всем
+// недогруженным участникам PR не подходит, поэтому WorkloadAfter может не совпасть с Target.
+// При DryRun передачи только рассчитаны и не сохранены
+type Rebalance struct {
+	TeamName       string           `json:"team_name"`
+	DryRun         bool             `json:"dry_run"`
+	WorkloadBefore map[string]int   `json:"workload_before"`
+	Target         map[string]int   `json:"target"`
+	WorkloadAfter  map[string]int   `json:"workload_after"`
+	Moves          []*RebalanceMove `json:"moves"`
+}
@@ -0,0 +1,75 @@
+package entity
+
+import "time"
+
+// StaleReview назначение ревьюера на открытый PR, по которому он не принимает решения
+// дольше SLA команды автора. IdleSince — назначение или последний комментарий ревьюера;
+// RemindedAt и EscalatedAt — последние напоминание и попытка замены
+type StaleReview struct {
+	PullRequestID string
+	ReviewerID    string
+	TeamName      string
+	IdleSince     time.Time
+	ReviewSLA     time.Duration
+	Escalation    time.Duration
+	RemindedAt    *time.Time
+	EscalatedAt   *time.Time
+}
+
+// ReminderDue проверяет, что пора напомнить: SLA истек, а за текущий простой напоминания не было
+func (s *StaleReview) ReminderDue(now time.Time) bool {
+	return s.ReviewSLA > 0 && !now.Before(s.IdleSince.Add(s.ReviewSLA)) && !s.Reminded()
+}
+
+// Reminded проверяет, что за текущий простой ревьюеру уже напомнили
+func (s *StaleReview) Reminded() bool {
+	return s.RemindedAt != nil && !s.RemindedAt.Before(s.IdleSince)
+}
+
+// EscalationDue проверяет, что пора заменить ревьюера: наступил срок замены, а за текущий
+// простой замену еще не пробовали
+func (s *StaleReview) EscalationDue(now time.Time) bool {
+	at := s.EscalatesAt()
+	return at != nil && !now.Before(*at) && (s.EscalatedAt == nil || s.EscalatedAt.Before(s.IdleSince))
+}
+
+// EscalatesAt момент автоматической замены ревьюера или nil, если эскалация отключена или
+// еще не запланирована. При включенных напоминаниях ревьюера заменяют только после напоминания
+// за текущий простой, через разницу порогов от момента напоминания, чтобы у него было время
+// ответить; без напоминаний — через второй порог от начала простоя
+func (s *StaleReview) EscalatesAt() *time.Time {
+	if s.Escalation <= 0 {
+		return nil
+	}
+
+	if s.ReviewSLA <= 0 {
+		at := s.IdleSince.Add(s.Escalation)
+		return &at
+	}
+
+	if !s.Reminded() {
+		return nil
+	}
+
+	at := s.RemindedAt.Add(s.Escalation - s.ReviewSLA)
+	return &at
+}
+
+// SLAResult итог прохода проверки SLA ревью: сколько назначений просмотрено, сколько
+// ревьюеров получили напоминание, сколько заменено, сколько замен не удалось и сколько
+// назначений пропущено из-за внутренней ошибки
+type SLAResult struct {
+	Checked   int `json:"checked"`
+	Reminded  int `json:"reminded"`
+	Escalated int `json:"escalated"`
+	Failed    int `json:"failed"`
+	Errors    int `json:"errors"`
+}
+
+// MoreDue проверяет, что проход обработал полную пачку из batchSize назначений и каждое получило
+// отметку напоминания или замены: следующая выборка вернет уже другие назначения, и ее можно
+// запросить сразу. Необработанное назначение вернулось бы в выборку снова, поэтому после
+// ошибок повтор откладывается до следующего запуска
+func (r SLAResult) MoreDue(batchSize int) bool {
+	return r.Checked == batchSize && r.Reminded+r.Escalated+r.Failed == r.Checked
+}
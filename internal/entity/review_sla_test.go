@@ -0,0 +1,25 @@
+package entity
+
+import "testing"
+
+func TestSLAResultMoreDue(t *testing.T) {
+	tests := []struct {
+		name   string
+		result SLAResult
+		want   bool
+	}{
+		{"partial batch", SLAResult{Checked: 2, Reminded: 2}, false},
+		{"full batch handled", SLAResult{Checked: 3, Reminded: 1, Escalated: 1, Failed: 1}, true},
+		{"full batch with errors", SLAResult{Checked: 3, Reminded: 2, Errors: 1}, false},
+		{"full batch with skipped", SLAResult{Checked: 3, Reminded: 2}, false},
+		{"empty", SLAResult{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.MoreDue(3); got != tt.want {
+				t.Errorf("MoreDue(3) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package entity
+
+import "time"
+
+// StatsFilter фильтр статистики: команда и полуинтервал [From, To)
+type StatsFilter struct {
+	TeamName string
+	From     *time.Time
+	To       *time.Time
+}
+
+// Contains проверяет, попадает ли момент времени в интервал фильтра
+func (f StatsFilter) Contains(t time.Time) bool {
+	if f.From != nil && t.Before(*f.From) {
+		return false
+	}
+
+	if f.To != nil && !t.Before(*f.To) {
+		return false
+	}
+
+	return true
+}
+
+// ReviewerStats статистика ревью пользователя
+type ReviewerStats struct {
+	UserID                   string   `json:"user_id"`
+	Username                 string   `json:"username"`
+	TeamName                 string   `json:"team_name"`
+	IsActive                 bool     `json:"is_active"`
+	Assigned                 int      `json:"assigned"`
+	Open                     int      `json:"open"`
+	MergedReviewed           int      `json:"merged_reviewed"`
+	ReassignedIn             int      `json:"reassigned_in"`
+	ReassignedOut            int      `json:"reassigned_out"`
+	MedianTimeToMergeSeconds *float64 `json:"median_time_to_merge_seconds"`
+}
+
+// TeamStats статистика pull request'ов и ревью команды
+type TeamStats struct {
+	TeamName                 string   `json:"team_name"`
+	Members                  int      `json:"members"`
+	ActiveMembers            int      `json:"active_members"`
+	PRsCreated               int      `json:"prs_created"`
+	PRsOpen                  int      `json:"prs_open"`
+	PRsMerged                int      `json:"prs_merged"`
+	ReviewsAssigned          int      `json:"reviews_assigned"`
+	Reassignments            int      `json:"reassignments"`
+	MedianTimeToMergeSeconds *float64 `json:"median_time_to_merge_seconds"`
+}
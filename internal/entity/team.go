@@ -3,9 +3,10 @@ package entity
 import "time"
 
 type Team struct {
-	Name      string        `json:"team_name"`
-	Members   []*TeamMember `json:"team_members"`
-	CreatedAt *time.Time
+	Name             string           `json:"team_name"`
+	Members          []*TeamMember    `json:"team_members"`
+	ReviewerStrategy ReviewerStrategy `json:"reviewer_strategy,omitempty"`
+	CreatedAt        *time.Time
 }
 
 type TeamMember struct {
@@ -13,3 +14,81 @@ type TeamMember struct {
 	Name     string `json:"username"`
 	IsActive bool   `json:"is_active"`
 }
+
+// ReviewerStrategy стратегия выбора ревьюеров в команде
+type ReviewerStrategy string
+
+const (
+	LeastBusy      ReviewerStrategy = "LEAST_BUSY"
+	RoundRobin     ReviewerStrategy = "ROUND_ROBIN"
+	RandomWeighted ReviewerStrategy = "RANDOM_WEIGHTED"
+	SeniorityAware ReviewerStrategy = "SENIORITY_AWARE"
+)
+
+// IsValid проверяет, что стратегия известна
+func (s ReviewerStrategy) IsValid() bool {
+	switch s {
+	case LeastBusy, RoundRobin, RandomWeighted, SeniorityAware:
+		return true
+	}
+
+	return false
+}
+
+// TeamSettings политика ревью команды. ReviewSLAMinutes — сколько ревьюер может не принимать
+// решения по открытому PR до напоминания, EscalationMinutes — до автоматической замены; 0 отключает
+type TeamSettings struct {
+	TeamName          string     `json:"team_name"`
+	MinReviewers      int        `json:"min_reviewers"`
+	MaxReviewers      int        `json:"max_reviewers"`
+	RequiredApprovals int        `json:"required_approvals"`
+	RequireTeamLead   bool       `json:"require_team_lead"`
+	TeamLeadID        string     `json:"team_lead_id,omitempty"`
+	ReviewSLAMinutes  int        `json:"review_sla_minutes"`
+	EscalationMinutes int        `json:"escalation_minutes"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+}
+
+// TeamSettingsUpdate изменение политики ревью команды. Поля SLA необязательны: отсутствующее
+// в запросе поле сохраняет текущее значение команды (или значение по умолчанию)
+type TeamSettingsUpdate struct {
+	TeamSettings
+	ReviewSLAMinutes  *int `json:"review_sla_minutes"`
+	EscalationMinutes *int `json:"escalation_minutes"`
+}
+
+// Значения политики по умолчанию для команд без настроек
+const (
+	DefaultMinReviewers      = 0
+	DefaultMaxReviewers      = 2
+	DefaultRequiredApprovals = 0
+	DefaultReviewSLAMinutes  = 48 * 60
+	DefaultEscalationMinutes = 96 * 60
+)
+
+// MembershipAction тип изменения состава команды
+type MembershipAction string
+
+const (
+	MemberAdded   MembershipAction = "ADDED"
+	MemberRemoved MembershipAction = "REMOVED"
+	MemberMoved   MembershipAction = "MOVED"
+)
+
+// MembershipChange запись журнала изменений состава команд; FromTeam пуст при добавлении,
+// ToTeam пуст при удалении
+type MembershipChange struct {
+	ID        int64            `json:"id"`
+	UserID    string           `json:"user_id"`
+	Action    MembershipAction `json:"action"`
+	FromTeam  string           `json:"from_team,omitempty"`
+	ToTeam    string           `json:"to_team,omitempty"`
+	ChangedAt time.Time        `json:"changed_at"`
+}
+
+// MembershipResult результат удаления или перевода участника: пользователь после изменения
+// и переназначения его открытых ревью в прежней команде
+type MembershipResult struct {
+	User          *User                 `json:"user"`
+	Reassignments []*ReviewReassignment `json:"reassignments"`
+}
@@ -10,3 +10,11 @@ type User struct {
 	CreatedAt *time.Time
 	UpdatedAt *time.Time
 }
+
+// Deactivation результат массовой деактивации пользователей; при DryRun изменения
+// только рассчитаны и не сохранены
+type Deactivation struct {
+	DryRun        bool                  `json:"dry_run"`
+	Users         []*User               `json:"users"`
+	Reassignments []*ReviewReassignment `json:"reassignments"`
+}
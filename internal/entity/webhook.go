@@ -0,0 +1,178 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// EventType тип доменного события для внешних подписчиков
+type EventType string
+
+const (
+	EventPRCreated          EventType = "pr.created"
+	EventPRMerged           EventType = "pr.merged"
+	EventPRClosed           EventType = "pr.closed"
+	EventReviewerAssigned   EventType = "reviewer.assigned"
+	EventReviewerReassigned EventType = "reviewer.reassigned"
+	EventReviewerReminded   EventType = "reviewer.reminded"
+	EventUserDeactivated    EventType = "user.deactivated"
+	EventUserActivated      EventType = "user.activated"
+)
+
+// EventTypes все типы событий, на которые можно подписаться
+var EventTypes = []EventType{
+	EventPRCreated, EventPRMerged, EventPRClosed, EventReviewerAssigned, EventReviewerReassigned,
+	EventReviewerReminded, EventUserDeactivated, EventUserActivated,
+}
+
+// IsValid проверяет, что тип события известен
+func (t EventType) IsValid() bool {
+	for _, known := range EventTypes {
+		if t == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Event доменное событие; TeamName — команда, к которой оно относится (для PR — команда автора)
+type Event struct {
+	ID         string    `json:"id"`
+	Type       EventType `json:"type"`
+	TeamName   string    `json:"team_name"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// NewEvent создает событие со случайным идентификатором
+func NewEvent(eventType EventType, teamName string, data any) *Event {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	return &Event{
+		ID:         hex.EncodeToString(id),
+		Type:       eventType,
+		TeamName:   teamName,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+// PREventData данные событий pr.created и pr.merged
+type PREventData struct {
+	PullRequestID string   `json:"pull_request_id"`
+	Name          string   `json:"pull_request_name"`
+	AuthorID      string   `json:"author_id"`
+	Status        string   `json:"status"`
+	Reviewers     []string `json:"reviewers"`
+}
+
+// ReviewerEventData данные событий reviewer.assigned и reviewer.reassigned
+type ReviewerEventData struct {
+	PullRequestID string           `json:"pull_request_id"`
+	ReviewerID    string           `json:"reviewer_id"`
+	OldReviewerID string           `json:"old_reviewer_id,omitempty"`
+	Reason        AssignmentReason `json:"reason"`
+}
+
+// ReminderEventData данные события reviewer.reminded: ревьюер не принимает решения
+// с IdleSince; EscalatesAt — когда его заменят, если эскалация у команды включена
+type ReminderEventData struct {
+	PullRequestID string     `json:"pull_request_id"`
+	ReviewerID    string     `json:"reviewer_id"`
+	IdleSince     time.Time  `json:"idle_since"`
+	EscalatesAt   *time.Time `json:"escalates_at,omitempty"`
+}
+
+// UserEventData данные событий user.deactivated и user.activated; у ручной активации причины нет
+type UserEventData struct {
+	UserID string           `json:"user_id"`
+	Reason AssignmentReason `json:"reason,omitempty"`
+}
+
+// Webhook подписка внешнего эндпоинта на события команды (пустой TeamName — всех команд).
+// Secret подписывает тело доставки HMAC-SHA256 и отдается только при создании
+type Webhook struct {
+	ID        int64       `json:"id"`
+	URL       string      `json:"url"`
+	TeamName  string      `json:"team_name,omitempty"`
+	Events    []EventType `json:"events"`
+	Secret    string      `json:"-"`
+	CreatedBy string      `json:"created_by"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// DeliveryStatus состояние доставки события на вебхук
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "PENDING"
+	DeliveryDelivered DeliveryStatus = "DELIVERED"
+	DeliveryRetrying  DeliveryStatus = "RETRYING"
+	DeliveryDead      DeliveryStatus = "DEAD"
+)
+
+// DeliveryStatuses все состояния доставки
+var DeliveryStatuses = []DeliveryStatus{DeliveryPending, DeliveryDelivered, DeliveryRetrying, DeliveryDead}
+
+// WebhookDelivery доставка одного события на один вебхук
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int64           `json:"webhook_id"`
+	EventID        string          `json:"event_id"`
+	EventType      EventType       `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	LastError      string          `json:"last_error,omitempty"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+
+	// URL и Secret вебхука, заполняются при выборке доставок к отправке
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// DeadLetter доставка, исчерпавшая попытки; DeliveryID nil, если доставка удалена вместе с подпиской
+type DeadLetter struct {
+	ID         int64           `json:"id"`
+	DeliveryID *int64          `json:"delivery_id"`
+	WebhookID  int64           `json:"webhook_id"`
+	EventID    string          `json:"event_id"`
+	EventType  EventType       `json:"event_type"`
+	Payload    json.RawMessage `json:"payload"`
+	Attempts   int             `json:"attempts"`
+	LastError  string          `json:"last_error"`
+	FailedAt   time.Time       `json:"failed_at"`
+}
+
+// DeliveryFilter фильтр журнала доставок; записи от новых к старым, курсор — ID доставки
+type DeliveryFilter struct {
+	WebhookID int64
+	Status    DeliveryStatus
+	Page
+}
+
+// RetryPolicy экспоненциальная задержка между попытками доставки
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// Delay задержка перед попыткой после attempts неудачных: BaseDelay * 2^(attempts-1), не больше MaxDelay
+func (p RetryPolicy) Delay(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, p.MaxDelay)
+}
+
+var ErrWebhookNotFound = NewError(NotFound, "webhook not found")
@@ -0,0 +1,93 @@
+package eventbus
+
+import (
+	"avito_test_task/internal/entity"
+	"sync"
+)
+
+// subscriptionBuffer сколько событий может ждать отправки подписчику
+const subscriptionBuffer = 64
+
+// Bus раздает события очереди ревью подписчикам внутри процесса
+type Bus struct {
+	mu   sync.Mutex
+	subs map[string]map[*Subscription]struct{}
+}
+
+// Subscription подписка на события очереди ревью одного пользователя. Events закрывается при
+// отписке или если подписчик не успевает читать: клиент переподключается и догоняет пропущенное
+type Subscription struct {
+	userID string
+	events chan *entity.OutboxEvent
+}
+
+func New() *Bus {
+	return &Bus{subs: make(map[string]map[*Subscription]struct{})}
+}
+
+// Events канал событий подписки
+func (s *Subscription) Events() <-chan *entity.OutboxEvent {
+	return s.events
+}
+
+// Subscribe подписывает на события очереди ревью пользователя userID
+func (b *Bus) Subscribe(userID string) *Subscription {
+	sub := &Subscription{userID: userID, events: make(chan *entity.OutboxEvent, subscriptionBuffer)}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[*Subscription]struct{})
+	}
+
+	b.subs[userID][sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe отменяет подписку; повторный вызов ничего не делает
+func (b *Bus) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(sub)
+}
+
+// Publish отправляет событие подписчикам всех пользователей, чью очередь оно меняет
+func (b *Bus) Publish(event *entity.OutboxEvent) {
+	users := event.ReviewQueueUsers()
+	if len(users) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, userID := range users {
+		for sub := range b.subs[userID] {
+			select {
+			case sub.events <- event:
+			default:
+				b.remove(sub)
+			}
+		}
+	}
+}
+
+// remove удаляет подписку и закрывает ее канал; вызывается под b.mu
+func (b *Bus) remove(sub *Subscription) {
+	subs, ok := b.subs[sub.userID]
+	if !ok {
+		return
+	}
+
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	close(sub.events)
+	if len(subs) == 0 {
+		delete(b.subs, sub.userID)
+	}
+}
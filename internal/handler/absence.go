@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"avito_test_task/internal/entity"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+type AddAbsenceRequest struct {
+	UserID   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason"`
+}
+
+type DeleteAbsenceRequest struct {
+	AbsenceID int64 `json:"absence_id"`
+}
+
+// AddAbsence POST /users/addAbsence
+func (h *Handler) AddAbsence(c *gin.Context) {
+	var req AddAbsenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	absence, err := h.uc.CreateAbsence(c.Request.Context(), &entity.Absence{
+		UserID:   req.UserID,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"absence": absence,
+	})
+}
+
+// GetAbsences GET /users/getAbsences
+func (h *Handler) GetAbsences(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		_ = c.Error(entity.NewError(entity.InvalidRequest, "user_id is required"))
+		return
+	}
+
+	absences, err := h.uc.GetAbsences(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":  userID,
+		"absences": absences,
+	})
+}
+
+// DeleteAbsence POST /users/deleteAbsence
+func (h *Handler) DeleteAbsence(c *gin.Context) {
+	var req DeleteAbsenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	if err := h.uc.DeleteAbsence(c.Request.Context(), req.AbsenceID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"absence_id": req.AbsenceID,
+	})
+}
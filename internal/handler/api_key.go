@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"avito_test_task/internal/entity"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+type CreateAPIKeyRequest struct {
+	Name      string              `json:"name"`
+	Scopes    []entity.Permission `json:"scopes"`
+	ExpiresAt *time.Time          `json:"expires_at"`
+}
+
+type RevokeAPIKeyRequest struct {
+	APIKeyID int64 `json:"api_key_id"`
+}
+
+// CreateAPIKey POST /apiKeys/create
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	key, raw, err := h.uc.CreateAPIKey(c.Request.Context(), req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"api_key": key,
+		"key":     raw,
+	})
+}
+
+// ListAPIKeys GET /apiKeys/list
+func (h *Handler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.uc.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"api_keys": keys,
+	})
+}
+
+// RevokeAPIKey POST /apiKeys/revoke
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	var req RevokeAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	key, err := h.uc.RevokeAPIKey(c.Request.Context(), req.APIKeyID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"api_key": key,
+	})
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"avito_test_task/internal/entity"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ListAuditEntries GET /audit/list
+func (h *Handler) ListAuditEntries(c *gin.Context) {
+	page, err := parsePage(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	filter := entity.AuditFilter{
+		Actor:      c.Query("actor"),
+		EntityType: entity.AuditEntityType(c.Query("entity_type")),
+		EntityID:   c.Query("entity_id"),
+		Page:       page,
+	}
+
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	entries, next, err := h.uc.ListAuditEntries(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries":     entries,
+		"next_cursor": next,
+	})
+}
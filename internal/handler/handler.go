@@ -1,48 +1,143 @@
 package handler
 
 import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/eventbus"
 	"avito_test_task/internal/middleware"
 	"avito_test_task/internal/usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"time"
 )
 
 type Handler struct {
-	uc *usecase.UseCase
+	uc   *usecase.UseCase
+	auth *middleware.Auth
+
+	// bus события очереди ревью для SSE, heartbeat период комментариев-пингов в потоке
+	bus       *eventbus.Bus
+	heartbeat time.Duration
 }
 
-func New(uc *usecase.UseCase) *Handler {
+func New(uc *usecase.UseCase, auth *middleware.Auth, bus *eventbus.Bus, heartbeat time.Duration) *Handler {
 	return &Handler{
-		uc: uc,
+		uc:        uc,
+		auth:      auth,
+		bus:       bus,
+		heartbeat: heartbeat,
 	}
 }
 
 func (h *Handler) InitRoutes(r *gin.Engine) {
 	r.Use(middleware.PrometheusMiddleware())
+	r.Use(middleware.ErrorHandler())
+	r.Use(middleware.AuditRequest())
 
-	requireAdmin := middleware.RequireAdmin()
-	requireUserOrAdmin := middleware.RequireAdminOrUser()
+	require := h.auth.Require
+	teamQuery := middleware.FromQuery(middleware.TargetTeam, "team_name")
+	teamBody := middleware.FromBody(middleware.TargetTeam, "team_name")
+	userQuery := middleware.FromQuery(middleware.TargetUser, "user_id")
+	userBody := middleware.FromBody(middleware.TargetUser, "user_id")
+	prQuery := middleware.FromQuery(middleware.TargetPR, "pull_request_id")
+	prBody := middleware.FromBody(middleware.TargetPR, "pull_request_id")
 
 	// Teams
 	team := r.Group("avito-test-task/team")
 	{
-		team.POST("/add", requireAdmin, h.CreateTeam)
-		team.GET("/get", requireUserOrAdmin, h.GetTeam)
+		team.POST("/add", require(entity.PermTeamManage, teamBody), h.CreateTeam)
+		team.GET("/get", require(entity.PermTeamRead, teamQuery), h.GetTeam)
+		team.GET("/list", require(entity.PermTeamRead), h.ListTeams)
+		team.POST("/setReviewerStrategy", require(entity.PermTeamManage, teamBody), h.SetReviewerStrategy)
+		team.GET("/settings", require(entity.PermTeamRead, teamQuery), h.GetTeamSettings)
+		team.POST("/settings", require(entity.PermTeamManage, teamBody), h.UpdateTeamSettings)
+		team.POST("/addMembers", require(entity.PermTeamManage, teamBody), h.AddTeamMembers)
+		team.POST("/removeMember", require(entity.PermTeamManage, teamBody), h.RemoveTeamMember)
+		team.POST("/moveMember", require(entity.PermTeamManage, teamBody, userBody), h.MoveTeamMember)
+		team.POST("/rename", require(entity.PermTeamManage, teamBody), h.RenameTeam)
+		team.POST("/delete", require(entity.PermTeamManage, teamBody), h.DeleteTeam)
+		team.GET("/history", require(entity.PermTeamRead, teamQuery), h.GetMembershipChanges)
+		team.POST("/rebalance", require(entity.PermTeamManage, teamBody), h.RebalanceTeam)
 	}
 
 	// Users
 	users := r.Group("avito-test-task/users")
 	{
-		users.POST("/setIsActive", requireAdmin, h.SetIsActive)
-		users.GET("/getReview", requireUserOrAdmin, h.GetReviews)
+		users.POST("/setIsActive", require(entity.PermUserDeactivate, userBody), h.SetIsActive)
+		users.POST("/deactivate", require(entity.PermUserDeactivate, teamBody,
+			middleware.FromBody(middleware.TargetUser, "user_ids")), h.DeactivateUsers)
+		users.POST("/addAbsence", require(entity.PermUserDeactivate, userBody), h.AddAbsence)
+		users.GET("/getAbsences", require(entity.PermUserRead, userQuery), h.GetAbsences)
+		users.POST("/deleteAbsence", require(entity.PermUserDeactivate,
+			middleware.FromBody(middleware.TargetAbsence, "absence_id")), h.DeleteAbsence)
+		users.GET("/getReview", require(entity.PermUserRead, userQuery), h.GetReviews)
+		users.GET("/reviews/stream", require(entity.PermUserRead, userQuery), h.StreamReviews)
+		users.GET("/list", require(entity.PermUserRead, teamQuery), h.ListUsers)
+		users.GET("/assignmentHistory", require(entity.PermUserRead, userQuery, prQuery), h.GetAssignmentHistory)
+		users.GET("/notifications/preferences", require(entity.PermNotifyManage, userQuery,
+			middleware.FromQuery(middleware.TargetOwner, "user_id")), h.GetNotificationPreferences)
+		users.POST("/notifications/preferences", require(entity.PermNotifyManage, userBody,
+			middleware.FromBody(middleware.TargetOwner, "user_id")), h.SetNotificationPreferences)
+		users.GET("/notifications", require(entity.PermNotifyManage, userQuery,
+			middleware.FromQuery(middleware.TargetOwner, "user_id")), h.ListNotifications)
 	}
 
 	// Pull Requests
 	pullRequests := r.Group("avito-test-task/pullRequest")
 	{
-		pullRequests.POST("/create", requireAdmin, h.CreatePR)
-		pullRequests.POST("/merge", requireAdmin, h.MergePR)
-		pullRequests.POST("/reassign", requireAdmin, h.ReassignPR)
+		pullRequests.GET("/list", require(entity.PermPRRead, teamQuery), h.ListPRs)
+		pullRequests.GET("/get", require(entity.PermPRRead, prQuery), h.GetPR)
+		pullRequests.GET("/history", require(entity.PermPRRead, prQuery, userQuery), h.GetAssignmentHistory)
+		pullRequests.POST("/create", require(entity.PermPRCreate,
+			middleware.FromBody(middleware.TargetUser, "author_id"),
+			middleware.FromBody(middleware.TargetOwner, "author_id")), h.CreatePR)
+		pullRequests.POST("/merge", require(entity.PermPRMerge, prBody), h.MergePR)
+		pullRequests.POST("/reassign", require(entity.PermPRReassign, prBody,
+			middleware.FromBody(middleware.TargetOwner, "old_user_id")), h.ReassignPR)
+		pullRequests.POST("/review", require(entity.PermPRReview, prBody,
+			middleware.FromBody(middleware.TargetOwner, "reviewer_id")), h.ReviewPR)
+		pullRequests.POST("/markReady", require(entity.PermPRUpdate, prBody), h.MarkReadyPR)
+		pullRequests.POST("/close", require(entity.PermPRUpdate, prBody), h.ClosePR)
+		pullRequests.POST("/reopen", require(entity.PermPRUpdate, prBody), h.ReopenPR)
+	}
+
+	// Stats
+	stats := r.Group("avito-test-task/stats")
+	{
+		stats.GET("/reviewers", require(entity.PermStatsRead, teamQuery), h.GetReviewerStats)
+		stats.GET("/teams", require(entity.PermStatsRead), h.GetTeamStats)
+	}
+
+	// Audit
+	audit := r.Group("avito-test-task/audit")
+	{
+		audit.GET("/list", require(entity.PermAuditRead), h.ListAuditEntries)
+	}
+
+	// RBAC
+	rbac := r.Group("avito-test-task/rbac")
+	{
+		rbac.GET("/roles", require(entity.PermRBACManage), h.ListRoles)
+		rbac.GET("/bindings", require(entity.PermRBACManage, teamQuery), h.GetRoleBindings)
+		rbac.POST("/bind", require(entity.PermRBACManage, teamBody), h.BindRole)
+		rbac.POST("/unbind", require(entity.PermRBACManage), h.UnbindRole)
+	}
+
+	// API keys
+	apiKeys := r.Group("avito-test-task/apiKeys")
+	{
+		apiKeys.POST("/create", require(entity.PermAPIKeyManage), h.CreateAPIKey)
+		apiKeys.GET("/list", require(entity.PermAPIKeyManage), h.ListAPIKeys)
+		apiKeys.POST("/revoke", require(entity.PermAPIKeyManage), h.RevokeAPIKey)
+	}
+
+	// Webhooks
+	webhooks := r.Group("avito-test-task/webhooks")
+	{
+		webhooks.POST("/create", require(entity.PermWebhookManage, teamBody), h.CreateWebhook)
+		webhooks.GET("/list", require(entity.PermWebhookManage, teamQuery), h.ListWebhooks)
+		webhooks.POST("/delete", require(entity.PermWebhookManage), h.DeleteWebhook)
+		webhooks.GET("/deliveries", require(entity.PermWebhookManage), h.ListWebhookDeliveries)
+		webhooks.GET("/deadLetters", require(entity.PermWebhookManage), h.ListDeadLetters)
 	}
 
 	// metrics endpoint
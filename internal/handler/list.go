@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"avito_test_task/internal/entity"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ListTeams GET /team/list
+func (h *Handler) ListTeams(c *gin.Context) {
+	page, err := parsePage(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	teams, next, err := h.uc.ListTeams(c.Request.Context(), entity.TeamFilter{
+		NameContains: c.Query("q"),
+		Page:         page,
+	})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"teams":       teams,
+		"next_cursor": next,
+	})
+}
+
+// ListUsers GET /users/list
+func (h *Handler) ListUsers(c *gin.Context) {
+	page, err := parsePage(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	filter := entity.UserFilter{
+		TeamName:     c.Query("team_name"),
+		NameContains: c.Query("q"),
+		Page:         page,
+	}
+
+	if value := c.Query("is_active"); value != "" {
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			_ = c.Error(entity.NewError(entity.InvalidRequest, "is_active must be true or false"))
+			return
+		}
+
+		filter.IsActive = &active
+	}
+
+	users, next, err := h.uc.ListUsers(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":       users,
+		"next_cursor": next,
+	})
+}
+
+// ListPRs GET /pullRequest/list
+func (h *Handler) ListPRs(c *gin.Context) {
+	page, err := parsePage(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	filter := entity.PRFilter{
+		TeamName:     c.Query("team_name"),
+		Status:       c.Query("status"),
+		AuthorID:     c.Query("author_id"),
+		ReviewerID:   c.Query("reviewer_id"),
+		NameContains: c.Query("q"),
+		Page:         page,
+	}
+
+	for key, dst := range map[string]**time.Time{
+		"created_from": &filter.CreatedFrom,
+		"created_to":   &filter.CreatedTo,
+		"merged_from":  &filter.MergedFrom,
+		"merged_to":    &filter.MergedTo,
+	} {
+		if *dst, err = parseTimeQuery(c, key); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+
+	prs, next, err := h.uc.ListPRs(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pull_requests": prs,
+		"next_cursor":   next,
+	})
+}
+
+// parsePage разбирает limit и cursor
+func parsePage(c *gin.Context) (entity.Page, error) {
+	var page entity.Page
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return page, entity.NewError(entity.InvalidRequest, "limit must be a positive integer")
+		}
+
+		page.Limit = limit
+	}
+
+	after, err := entity.DecodeCursor(c.Query("cursor"))
+	if err != nil {
+		return page, err
+	}
+
+	page.After = after
+	return page, nil
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"avito_test_task/internal/entity"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type SetNotificationPreferencesRequest struct {
+	UserID         string                       `json:"user_id" binding:"required"`
+	Email          string                       `json:"email"`
+	ChatWebhookURL string                       `json:"chat_webhook_url"`
+	Channels       []entity.NotificationChannel `json:"channels"`
+	QuietHours     *entity.QuietHours           `json:"quiet_hours"`
+}
+
+// GetNotificationPreferences GET /users/notifications/preferences
+func (h *Handler) GetNotificationPreferences(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		_ = c.Error(entity.NewError(entity.InvalidRequest, "user_id is required"))
+		return
+	}
+
+	prefs, err := h.uc.GetNotificationPreferences(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"preferences": prefs,
+	})
+}
+
+// SetNotificationPreferences POST /users/notifications/preferences
+func (h *Handler) SetNotificationPreferences(c *gin.Context) {
+	var req SetNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	prefs, err := h.uc.SetNotificationPreferences(c.Request.Context(), &entity.NotificationPreferences{
+		UserID:         req.UserID,
+		Email:          req.Email,
+		ChatWebhookURL: req.ChatWebhookURL,
+		Channels:       req.Channels,
+		QuietHours:     req.QuietHours,
+	})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"preferences": prefs,
+	})
+}
+
+// ListNotifications GET /users/notifications
+func (h *Handler) ListNotifications(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		_ = c.Error(entity.NewError(entity.InvalidRequest, "user_id is required"))
+		return
+	}
+
+	page, err := parsePage(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	notifications, next, err := h.uc.ListNotifications(c.Request.Context(), entity.NotificationFilter{
+		UserID: userID,
+		Status: entity.NotificationStatus(c.Query("status")),
+		Page:   page,
+	})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"next_cursor":   next,
+	})
+}
@@ -2,73 +2,86 @@ package handler
 
 import (
 	"avito_test_task/internal/entity"
-	"fmt"
+	"context"
 	"github.com/gin-gonic/gin"
-	"log/slog"
 	"net/http"
-	"strings"
 )
 
 type CreatePRRequest struct {
 	PullRequestID   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 	AuthorID        string `json:"author_id"`
+	Draft           bool   `json:"draft"`
 }
 
 type MergePRRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 }
 
+type PRStatusRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
 type ReassignPRRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldUserID     string `json:"old_user_id"`
 }
 
+type ReviewPRRequest struct {
+	PullRequestID string                `json:"pull_request_id"`
+	ReviewerID    string                `json:"reviewer_id"`
+	Decision      entity.ReviewDecision `json:"decision"`
+	Comment       string                `json:"comment"`
+}
+
+// GetPR GET /pullRequest/get
+func (h *Handler) GetPR(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		_ = c.Error(entity.NewError(entity.InvalidRequest, "pull_request_id is required"))
+		return
+	}
+
+	pr, err := h.uc.GetPRDetails(c.Request.Context(), prID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	respondWithETag(c, gin.H{
+		"pr": pr,
+	})
+}
+
+// GetAssignmentHistory GET /pullRequest/history, GET /users/assignmentHistory
+func (h *Handler) GetAssignmentHistory(c *gin.Context) {
+	filter := entity.AssignmentHistoryFilter{
+		PullRequestID: c.Query("pull_request_id"),
+		UserID:        c.Query("user_id"),
+	}
+
+	history, err := h.uc.GetAssignmentHistory(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"history": history,
+	})
+}
+
 // CreatePR POST /pullRequest/create
 func (h *Handler) CreatePR(c *gin.Context) {
 	var req CreatePRRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		slog.Error("failed to parse request", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":    entity.InvalidRequest,
-				"message": fmt.Errorf("invalid request body: %w", err).Error(),
-			},
-		})
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
 		return
 	}
 
-	pr, err := h.uc.CreatePR(c.Request.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID)
+	pr, err := h.uc.CreatePR(c.Request.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID, req.Draft)
 	if err != nil {
-		if strings.Contains(err.Error(), "PR_EXISTS") {
-			slog.Error("pull request already exists", "error", err)
-			c.JSON(http.StatusConflict, gin.H{
-				"error": gin.H{
-					"code":    entity.PRExists,
-					"message": fmt.Sprintf("pull request already exists: %s", req.PullRequestID),
-				},
-			})
-			return
-		}
-
-		if strings.Contains(err.Error(), "NOT_FOUND") {
-			slog.Error("pull request author not found", "error", err)
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":    entity.NotFound,
-					"message": fmt.Sprintf("pull request author not found: %s", req.AuthorID),
-				},
-			})
-			return
-		}
-
-		slog.Error("failed to create pull request", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":    entity.InternalServer,
-				"message": fmt.Errorf("internal server error: %w", err).Error(),
-			},
-		})
+		_ = c.Error(err)
 		return
 	}
 
@@ -81,36 +94,13 @@ func (h *Handler) CreatePR(c *gin.Context) {
 func (h *Handler) MergePR(c *gin.Context) {
 	var req MergePRRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		slog.Error("failed to parse request", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":    entity.InvalidRequest,
-				"message": fmt.Errorf("invalid request body: %w", err).Error(),
-			},
-		})
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
 		return
 	}
 
 	pr, err := h.uc.MergePR(c.Request.Context(), req.PullRequestID)
 	if err != nil {
-		if strings.Contains(err.Error(), "NOT_FOUND") {
-			slog.Error("pull request not found", "error", err)
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":    entity.NotFound,
-					"message": fmt.Sprintf("pull request not found: %s", req.PullRequestID),
-				},
-			})
-			return
-		}
-
-		slog.Error("failed to merge pull request", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":    entity.InternalServer,
-				"message": fmt.Errorf("internal server error: %w", err).Error(),
-			},
-		})
+		_ = c.Error(err)
 		return
 	}
 
@@ -123,69 +113,13 @@ func (h *Handler) MergePR(c *gin.Context) {
 func (h *Handler) ReassignPR(c *gin.Context) {
 	var req ReassignPRRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		slog.Error("failed to parse request", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":    entity.InvalidRequest,
-				"message": fmt.Errorf("invalid request body: %w", err).Error(),
-			},
-		})
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
 		return
 	}
 
-	pr, newReviewerID, err := h.uc.ReassignReviewer(c.Request.Context(), req.PullRequestID, req.OldUserID)
+	pr, newReviewerID, err := h.uc.ReassignReviewer(c.Request.Context(), req.PullRequestID, req.OldUserID, entity.ReasonManualReassign)
 	if err != nil {
-		if strings.Contains(err.Error(), "NOT_FOUND") {
-			slog.Error("pull request not found", "error", err)
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":    entity.NotFound,
-					"message": fmt.Sprintf("pull request not found: %s", req.PullRequestID),
-				},
-			})
-			return
-		}
-
-		if strings.Contains(err.Error(), "PR_MERGED") {
-			slog.Error("pull request already merged", "error", err)
-			c.JSON(http.StatusConflict, gin.H{
-				"error": gin.H{
-					"code":    entity.PRMerged,
-					"message": fmt.Sprintf("pull request already merged: %s", req.PullRequestID),
-				},
-			})
-			return
-		}
-
-		if strings.Contains(err.Error(), "NOT_ASSIGNED") {
-			slog.Error("reviewer is not assigned to this PR", "error", err)
-			c.JSON(http.StatusConflict, gin.H{
-				"error": gin.H{
-					"code":    entity.NotAssigned,
-					"message": fmt.Sprintf("reviewer is not assigned to this PR: %s", req.PullRequestID),
-				},
-			})
-			return
-		}
-
-		if strings.Contains(err.Error(), "NO_CANDIDATE") {
-			slog.Error("no candidates to assign", "error", err)
-			c.JSON(http.StatusConflict, gin.H{
-				"error": gin.H{
-					"code":    entity.NoCandidate,
-					"message": fmt.Sprintf("no candidates to assign: %s", req.PullRequestID),
-				},
-			})
-			return
-		}
-
-		slog.Error("failed to reassign pull request", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":    entity.InternalServer,
-				"message": fmt.Errorf("internal server error: %w", err).Error(),
-			},
-		})
+		_ = c.Error(err)
 		return
 	}
 
@@ -194,3 +128,56 @@ func (h *Handler) ReassignPR(c *gin.Context) {
 		"replaced_by": newReviewerID,
 	})
 }
+
+// ReviewPR POST /pullRequest/review
+func (h *Handler) ReviewPR(c *gin.Context) {
+	var req ReviewPRRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	pr, err := h.uc.SubmitReview(c.Request.Context(), req.PullRequestID, req.ReviewerID, req.Decision, req.Comment)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pr": *pr,
+	})
+}
+
+// MarkReadyPR POST /pullRequest/markReady
+func (h *Handler) MarkReadyPR(c *gin.Context) {
+	h.changePRStatus(c, h.uc.MarkReady)
+}
+
+// ClosePR POST /pullRequest/close
+func (h *Handler) ClosePR(c *gin.Context) {
+	h.changePRStatus(c, h.uc.ClosePR)
+}
+
+// ReopenPR POST /pullRequest/reopen
+func (h *Handler) ReopenPR(c *gin.Context) {
+	h.changePRStatus(c, h.uc.ReopenPR)
+}
+
+// changePRStatus общая обработка запросов на смену статуса PR
+func (h *Handler) changePRStatus(c *gin.Context, change func(ctx context.Context, prID string) (*entity.PullRequest, error)) {
+	var req PRStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	pr, err := change(c.Request.Context(), req.PullRequestID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pr": *pr,
+	})
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"avito_test_task/internal/entity"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type BindRoleRequest struct {
+	UserID   string `json:"user_id"`
+	Role     string `json:"role"`
+	TeamName string `json:"team_name"`
+}
+
+type UnbindRoleRequest struct {
+	BindingID int64 `json:"binding_id"`
+}
+
+// ListRoles GET /rbac/roles
+func (h *Handler) ListRoles(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"roles": entity.RolePermissions,
+	})
+}
+
+// GetRoleBindings GET /rbac/bindings
+func (h *Handler) GetRoleBindings(c *gin.Context) {
+	bindings, err := h.uc.GetRoleBindings(c.Request.Context(), entity.RoleBindingFilter{
+		UserID:   c.Query("user_id"),
+		TeamName: c.Query("team_name"),
+	})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bindings": bindings,
+	})
+}
+
+// BindRole POST /rbac/bind
+func (h *Handler) BindRole(c *gin.Context) {
+	var req BindRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	binding, err := h.uc.BindRole(c.Request.Context(), &entity.RoleBinding{
+		UserID:   req.UserID,
+		Role:     req.Role,
+		TeamName: req.TeamName,
+	})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"binding": binding,
+	})
+}
+
+// UnbindRole POST /rbac/unbind
+func (h *Handler) UnbindRole(c *gin.Context) {
+	var req UnbindRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	binding, err := h.uc.UnbindRole(c.Request.Context(), req.BindingID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"binding": binding,
+	})
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"avito_test_task/internal/entity"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// StreamReviews GET /users/reviews/stream
+//
+// Отдает события очереди ревью пользователя через Server-Sent Events. ID события SSE — ID
+// в outbox: клиент, переподключаясь с Last-Event-ID, сначала получает пропущенные события.
+// События идут в порядке фиксации транзакций, поэтому ID в потоке не обязательно возрастают
+func (h *Handler) StreamReviews(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		_ = c.Error(entity.NewError(entity.InvalidRequest, "user_id is required"))
+		return
+	}
+
+	lastID, err := parseLastEventID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// подписка до чтения истории, чтобы не потерять события между ними
+	sub := h.bus.Subscribe(userID)
+	defer h.bus.Unsubscribe(sub)
+
+	position, err := h.uc.ReviewStreamPosition(ctx, userID, lastID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	for {
+		events, scanned, err := h.uc.ReviewQueueEvents(ctx, userID, position)
+		if err != nil {
+			return
+		}
+
+		for _, event := range events {
+			renderReviewEvent(c, event)
+		}
+
+		if scanned == position {
+			break
+		}
+
+		position = scanned
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				// подписчик отстал: закрываем поток, клиент догонит по Last-Event-ID
+				return
+			}
+
+			if !position.Before(event.Position()) {
+				continue
+			}
+
+			position = event.Position()
+			renderReviewEvent(c, event)
+		case <-heartbeat.C:
+			_, _ = c.Writer.WriteString(": heartbeat\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+func renderReviewEvent(c *gin.Context, event *entity.OutboxEvent) {
+	c.Render(-1, sse.Event{
+		Id:    strconv.FormatInt(event.ID, 10),
+		Event: string(event.Type),
+		Data:  string(event.Payload),
+	})
+	c.Writer.Flush()
+}
+
+// parseLastEventID разбирает заголовок Last-Event-ID или параметр last_event_id
+func parseLastEventID(c *gin.Context) (int64, error) {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0, entity.NewError(entity.InvalidRequest, "Last-Event-ID must be a non-negative integer")
+	}
+
+	return id, nil
+}
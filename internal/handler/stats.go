@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"avito_test_task/internal/entity"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// GetReviewerStats GET /stats/reviewers
+func (h *Handler) GetReviewerStats(c *gin.Context) {
+	filter, err := parseStatsFilter(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	stats, err := h.uc.GetReviewerStats(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviewers": stats,
+	})
+}
+
+// GetTeamStats GET /stats/teams
+func (h *Handler) GetTeamStats(c *gin.Context) {
+	filter, err := parseStatsFilter(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	stats, err := h.uc.GetTeamStats(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"teams": stats,
+	})
+}
+
+// parseStatsFilter разбирает team_name и интервал from/to (RFC3339 или YYYY-MM-DD)
+func parseStatsFilter(c *gin.Context) (entity.StatsFilter, error) {
+	filter := entity.StatsFilter{
+		TeamName: c.Query("team_name"),
+	}
+
+	var err error
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		return filter, err
+	}
+
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+func parseTimeQuery(c *gin.Context, key string) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+
+	return nil, entity.NewError(entity.InvalidRequest, "%s must be RFC3339 or YYYY-MM-DD", key)
+}
@@ -2,47 +2,26 @@ package handler
 
 import (
 	"avito_test_task/internal/entity"
-	"fmt"
 	"github.com/gin-gonic/gin"
-	"log/slog"
 	"net/http"
-	"strings"
 )
 
+type SetReviewerStrategyRequest struct {
+	TeamName string                  `json:"team_name"`
+	Strategy entity.ReviewerStrategy `json:"reviewer_strategy"`
+}
+
 // CreateTeam POST /team/add
 func (h *Handler) CreateTeam(c *gin.Context) {
 	var req entity.Team
 	if err := c.ShouldBindJSON(&req); err != nil {
-		slog.Error("failed to parse request", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":    entity.InvalidRequest,
-				"message": fmt.Errorf("invalid request body: %w", err).Error(),
-			},
-		})
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
 		return
 	}
 
 	team, err := h.uc.CreateTeam(c.Request.Context(), &req)
 	if err != nil {
-		if strings.Contains(err.Error(), "TEAM_EXISTS") {
-			slog.Error("team already exists", "error", err)
-			c.JSON(http.StatusConflict, gin.H{
-				"error": gin.H{
-					"code":    entity.TeamExists,
-					"message": fmt.Errorf("team already exists").Error(),
-				},
-			})
-			return
-		}
-
-		slog.Error("failed to create team", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":    entity.InternalServer,
-				"message": fmt.Errorf("internal server error: %w", err).Error(),
-			},
-		})
+		_ = c.Error(err)
 		return
 	}
 
@@ -55,36 +34,13 @@ func (h *Handler) CreateTeam(c *gin.Context) {
 func (h *Handler) GetTeam(c *gin.Context) {
 	teamName := c.Query("team_name")
 	if teamName == "" {
-		slog.Error("team_name must be required")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":    entity.InvalidRequest,
-				"message": fmt.Errorf("invalid request body").Error(),
-			},
-		})
+		_ = c.Error(entity.NewError(entity.InvalidRequest, "team_name is required"))
 		return
 	}
 
 	team, err := h.uc.GetTeam(c.Request.Context(), teamName)
 	if err != nil {
-		if strings.Contains(err.Error(), "NOT_FOUND") {
-			slog.Error("team not found", "error", err)
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":    entity.NotFound,
-					"message": fmt.Errorf("team not found: %w", err).Error(),
-				},
-			})
-			return
-		}
-
-		slog.Error("failed to get team", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":    entity.InternalServer,
-				"message": fmt.Errorf("internal server error: %w", err).Error(),
-			},
-		})
+		_ = c.Error(err)
 		return
 	}
 
@@ -92,3 +48,211 @@ func (h *Handler) GetTeam(c *gin.Context) {
 		"team": *team,
 	})
 }
+
+// SetReviewerStrategy POST /team/setReviewerStrategy
+func (h *Handler) SetReviewerStrategy(c *gin.Context) {
+	var req SetReviewerStrategyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	team, err := h.uc.SetReviewerStrategy(c.Request.Context(), req.TeamName, req.Strategy)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"team": *team,
+	})
+}
+
+// GetTeamSettings GET /team/settings
+func (h *Handler) GetTeamSettings(c *gin.Context) {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		_ = c.Error(entity.NewError(entity.InvalidRequest, "team_name is required"))
+		return
+	}
+
+	settings, err := h.uc.GetTeamSettings(c.Request.Context(), teamName)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"settings": settings,
+	})
+}
+
+// UpdateTeamSettings POST /team/settings
+func (h *Handler) UpdateTeamSettings(c *gin.Context) {
+	var req entity.TeamSettingsUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	settings, err := h.uc.UpdateTeamSettings(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"settings": settings,
+	})
+}
+
+type AddTeamMembersRequest struct {
+	TeamName string               `json:"team_name"`
+	Members  []*entity.TeamMember `json:"team_members"`
+}
+
+type TeamMemberRequest struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
+}
+
+type RenameTeamRequest struct {
+	TeamName    string `json:"team_name"`
+	NewTeamName string `json:"new_team_name"`
+}
+
+type DeleteTeamRequest struct {
+	TeamName string `json:"team_name"`
+}
+
+// AddTeamMembers POST /team/addMembers
+func (h *Handler) AddTeamMembers(c *gin.Context) {
+	var req AddTeamMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	team, err := h.uc.AddTeamMembers(c.Request.Context(), req.TeamName, req.Members)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"team": team,
+	})
+}
+
+// RemoveTeamMember POST /team/removeMember
+func (h *Handler) RemoveTeamMember(c *gin.Context) {
+	var req TeamMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	result, err := h.uc.RemoveTeamMember(c.Request.Context(), req.TeamName, req.UserID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// MoveTeamMember POST /team/moveMember
+func (h *Handler) MoveTeamMember(c *gin.Context) {
+	var req TeamMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	result, err := h.uc.MoveTeamMember(c.Request.Context(), req.UserID, req.TeamName)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RenameTeam POST /team/rename
+func (h *Handler) RenameTeam(c *gin.Context) {
+	var req RenameTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	team, err := h.uc.RenameTeam(c.Request.Context(), req.TeamName, req.NewTeamName)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"team": team,
+	})
+}
+
+// DeleteTeam POST /team/delete
+func (h *Handler) DeleteTeam(c *gin.Context) {
+	var req DeleteTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	if err := h.uc.DeleteTeam(c.Request.Context(), req.TeamName); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"team_name": req.TeamName,
+	})
+}
+
+// GetMembershipChanges GET /team/history
+func (h *Handler) GetMembershipChanges(c *gin.Context) {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		_ = c.Error(entity.NewError(entity.InvalidRequest, "team_name is required"))
+		return
+	}
+
+	changes, err := h.uc.GetMembershipChanges(c.Request.Context(), teamName)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"team_name": teamName,
+		"changes":   changes,
+	})
+}
+
+type RebalanceTeamRequest struct {
+	TeamName string `json:"team_name"`
+	DryRun   bool   `json:"dry_run"`
+}
+
+// RebalanceTeam POST /team/rebalance
+func (h *Handler) RebalanceTeam(c *gin.Context) {
+	var req RebalanceTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	result, err := h.uc.Rebalance(c.Request.Context(), req.TeamName, req.DryRun)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
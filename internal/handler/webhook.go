@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"avito_test_task/internal/entity"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type CreateWebhookRequest struct {
+	URL      string             `json:"url"`
+	TeamName string             `json:"team_name"`
+	Events   []entity.EventType `json:"events"`
+	Secret   string             `json:"secret"`
+}
+
+type DeleteWebhookRequest struct {
+	WebhookID int64 `json:"webhook_id"`
+}
+
+// CreateWebhook POST /webhooks/create
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	hook, secret, err := h.uc.CreateWebhook(c.Request.Context(), &entity.Webhook{
+		URL:      req.URL,
+		TeamName: req.TeamName,
+		Events:   req.Events,
+		Secret:   req.Secret,
+	})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"webhook": hook,
+		"secret":  secret,
+	})
+}
+
+// ListWebhooks GET /webhooks/list
+func (h *Handler) ListWebhooks(c *gin.Context) {
+	hooks, err := h.uc.ListWebhooks(c.Request.Context(), c.Query("team_name"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": hooks,
+	})
+}
+
+// DeleteWebhook POST /webhooks/delete
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	var req DeleteWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "invalid request body"))
+		return
+	}
+
+	if err := h.uc.DeleteWebhook(c.Request.Context(), req.WebhookID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhook_id": req.WebhookID,
+	})
+}
+
+// ListWebhookDeliveries GET /webhooks/deliveries
+func (h *Handler) ListWebhookDeliveries(c *gin.Context) {
+	filter, err := parseDeliveryFilter(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	filter.Status = entity.DeliveryStatus(c.Query("status"))
+	deliveries, next, err := h.uc.ListWebhookDeliveries(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries":  deliveries,
+		"next_cursor": next,
+	})
+}
+
+// ListDeadLetters GET /webhooks/deadLetters
+func (h *Handler) ListDeadLetters(c *gin.Context) {
+	filter, err := parseDeliveryFilter(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	letters, next, err := h.uc.ListDeadLetters(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"dead_letters": letters,
+		"next_cursor":  next,
+	})
+}
+
+// parseDeliveryFilter разбирает webhook_id, limit и cursor
+func parseDeliveryFilter(c *gin.Context) (entity.DeliveryFilter, error) {
+	var filter entity.DeliveryFilter
+	if value := c.Query("webhook_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id <= 0 {
+			return filter, entity.NewError(entity.InvalidRequest, "webhook_id must be a positive integer")
+		}
+
+		filter.WebhookID = id
+	}
+
+	page, err := parsePage(c)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.Page = page
+	return filter, nil
+}
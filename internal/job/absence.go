@@ -0,0 +1,58 @@
+package job
+
+import (
+	"avito_test_task/internal/usecase"
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log/slog"
+	"time"
+)
+
+var absenceSyncAbsences = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "absence_sync_absences_total",
+		Help: "Absence boundaries processed by the absence sync job",
+	},
+	[]string{"result"},
+)
+
+// AbsenceSync периодически обрабатывает границы отсутствий пользователей
+type AbsenceSync struct {
+	uc       *usecase.UseCase
+	interval time.Duration
+	reassign bool
+}
+
+func NewAbsenceSync(uc *usecase.UseCase, interval time.Duration, reassign bool) *AbsenceSync {
+	return &AbsenceSync{
+		uc:       uc,
+		interval: interval,
+		reassign: reassign,
+	}
+}
+
+// Run выполняет синхронизацию сразу и затем каждые interval, пока не отменен ctx
+func (j *AbsenceSync) Run(ctx context.Context) {
+	slog.Info("absence sync started", "interval", j.interval, "reassign", j.reassign)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := j.uc.SyncAbsences(ctx, time.Now(), j.reassign)
+		if err != nil {
+			slog.Error("absence sync failed", "error", err)
+		}
+
+		absenceSyncAbsences.WithLabelValues("processed").Add(float64(result.Processed))
+		absenceSyncAbsences.WithLabelValues("failed").Add(float64(result.Failed))
+
+		select {
+		case <-ctx.Done():
+			slog.Info("absence sync stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
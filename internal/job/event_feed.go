@@ -0,0 +1,68 @@
+package job
+
+import (
+	"avito_test_task/internal/eventbus"
+	"avito_test_task/internal/usecase"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// EventFeed читает новые события outbox в порядке фиксации и раздает их подписчикам шины.
+// Читает outbox сам, а не через релей, поэтому каждый экземпляр сервиса видит все события
+// независимо от того, какой из них их публикует
+type EventFeed struct {
+	uc       *usecase.UseCase
+	bus      *eventbus.Bus
+	interval time.Duration
+}
+
+func NewEventFeed(uc *usecase.UseCase, bus *eventbus.Bus, interval time.Duration) *EventFeed {
+	return &EventFeed{
+		uc:       uc,
+		bus:      bus,
+		interval: interval,
+	}
+}
+
+// Run раздает события, записанные после запуска, каждые interval, пока не отменен ctx
+func (j *EventFeed) Run(ctx context.Context) {
+	slog.Info("event feed started", "interval", j.interval)
+
+	position, err := j.uc.OutboxEnd(ctx)
+	for err != nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(j.interval):
+		}
+
+		position, err = j.uc.OutboxEnd(ctx)
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		events, err := j.uc.OutboxEventsAfter(ctx, position)
+		if err != nil {
+			slog.Error("event feed failed", "error", err)
+		}
+
+		for _, event := range events {
+			j.bus.Publish(event)
+			position = event.Position()
+		}
+
+		if len(events) == usecase.OutboxBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("event feed stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package job
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/usecase"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Notifier периодически планирует уведомления по новым событиям outbox и отправляет
+// уведомления, время которых наступило
+type Notifier struct {
+	uc       *usecase.UseCase
+	senders  map[entity.NotificationChannel]usecase.NotificationSender
+	policy   entity.RetryPolicy
+	interval time.Duration
+	lease    time.Duration
+}
+
+// NewNotifier создает рассыльщик; каналы без отправителя не доставляются, их уведомления
+// помечаются FAILED. lease — на сколько забранное уведомление скрывается от других экземпляров
+func NewNotifier(uc *usecase.UseCase, senders map[entity.NotificationChannel]usecase.NotificationSender, policy entity.RetryPolicy, interval, lease time.Duration) *Notifier {
+	return &Notifier{
+		uc:       uc,
+		senders:  senders,
+		policy:   policy,
+		interval: interval,
+		lease:    lease,
+	}
+}
+
+// Run планирует и отправляет уведомления сразу и затем каждые interval, пока не отменен ctx;
+// полные пачки обрабатываются без ожидания следующего тика
+func (j *Notifier) Run(ctx context.Context) {
+	channels := make([]entity.NotificationChannel, 0, len(j.senders))
+	for channel := range j.senders {
+		channels = append(channels, channel)
+	}
+
+	slog.Info("notifier started", "interval", j.interval, "channels", channels)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		read, planErr := j.uc.PlanNotifications(ctx, time.Now())
+		if planErr != nil {
+			slog.Error("notification planning failed", "error", planErr)
+		}
+
+		sent, sendErr := j.uc.SendNotifications(ctx, j.senders, j.policy, j.lease)
+		if sendErr != nil {
+			slog.Error("notification sending failed", "error", sendErr)
+		}
+
+		if (planErr == nil && read == usecase.OutboxBatchSize) || (sendErr == nil && sent == usecase.NotificationBatchSize) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("notifier stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
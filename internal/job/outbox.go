@@ -0,0 +1,84 @@
+package job
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/usecase"
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log/slog"
+	"time"
+)
+
+// outboxPruneInterval период удаления опубликованных событий старше срока хранения
+const outboxPruneInterval = time.Hour
+
+var outboxBacklog = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "outbox_backlog_events",
+		Help: "Number of outbox events waiting to be published",
+	},
+)
+
+// OutboxRelay периодически публикует события outbox, обновляет метрику их очереди
+// и удаляет опубликованные события старше retention
+type OutboxRelay struct {
+	uc        *usecase.UseCase
+	publisher usecase.EventPublisher
+	policy    entity.RetryPolicy
+	interval  time.Duration
+	lease     time.Duration
+	retention time.Duration
+}
+
+// NewOutboxRelay создает релей; lease — на сколько забранное событие скрывается от других
+// экземпляров сервиса, должен превышать время публикации пачки
+func NewOutboxRelay(uc *usecase.UseCase, publisher usecase.EventPublisher, policy entity.RetryPolicy, interval, lease, retention time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		uc:        uc,
+		publisher: publisher,
+		policy:    policy,
+		interval:  interval,
+		lease:     lease,
+		retention: retention,
+	}
+}
+
+// Run публикует события сразу и затем каждые interval, пока не отменен ctx; полная
+// пачка обрабатывается без ожидания следующего тика. Раз в outboxPruneInterval
+// удаляются события, опубликованные раньше retention
+func (j *OutboxRelay) Run(ctx context.Context) {
+	slog.Info("outbox relay started", "interval", j.interval, "maxAttempts", j.policy.MaxAttempts, "retention", j.retention)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	var pruned time.Time
+	for {
+		attempted, err := j.uc.RelayOutbox(ctx, j.publisher, j.policy, j.lease)
+		if err != nil {
+			slog.Error("outbox relay failed", "error", err)
+		}
+
+		if backlog, err := j.uc.OutboxBacklog(ctx); err == nil {
+			outboxBacklog.Set(float64(backlog))
+		}
+
+		if time.Since(pruned) >= outboxPruneInterval {
+			if _, err := j.uc.PruneOutbox(ctx, time.Now().Add(-j.retention)); err == nil {
+				pruned = time.Now()
+			}
+		}
+
+		if err == nil && attempted == usecase.OutboxBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package job
+
+import (
+	"avito_test_task/internal/usecase"
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log/slog"
+	"time"
+)
+
+var rebalanceMoves = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Name: "rebalance_moves_total",
+		Help: "Open reviews moved between team members by the rebalance job",
+	},
+)
+
+// Rebalance периодически выравнивает число открытых ревью между активными участниками команд.
+// Работает на всех экземплярах, но проход выполняет только взявший блокировку лидера
+type Rebalance struct {
+	uc       *usecase.UseCase
+	interval time.Duration
+}
+
+func NewRebalance(uc *usecase.UseCase, interval time.Duration) *Rebalance {
+	return &Rebalance{
+		uc:       uc,
+		interval: interval,
+	}
+}
+
+// Run выравнивает нагрузку каждые interval, пока не отменен ctx
+func (j *Rebalance) Run(ctx context.Context) {
+	slog.Info("rebalance job started", "interval", j.interval)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("rebalance job stopped")
+			return
+		case <-ticker.C:
+		}
+
+		moved, err := j.uc.RebalanceTeams(ctx)
+		if err != nil {
+			slog.Error("rebalance failed", "error", err)
+		}
+
+		rebalanceMoves.Add(float64(moved))
+	}
+}
@@ -0,0 +1,64 @@
+package job
+
+import (
+	"avito_test_task/internal/usecase"
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log/slog"
+	"time"
+)
+
+var reviewSLAActions = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "review_sla_actions_total",
+		Help: "Reminders and reviewer escalations made by the review SLA job",
+	},
+	[]string{"action"},
+)
+
+// ReviewSLA периодически напоминает ревьюерам о просроченных ревью и заменяет простаивающих.
+// Работает на всех экземплярах, но проход выполняет только взявший блокировку лидера
+type ReviewSLA struct {
+	uc       *usecase.UseCase
+	interval time.Duration
+}
+
+func NewReviewSLA(uc *usecase.UseCase, interval time.Duration) *ReviewSLA {
+	return &ReviewSLA{
+		uc:       uc,
+		interval: interval,
+	}
+}
+
+// Run проверяет SLA сразу и затем каждые interval, пока не отменен ctx; следующая пачка после
+// полностью обработанной запрашивается без ожидания тика
+func (j *ReviewSLA) Run(ctx context.Context) {
+	slog.Info("review SLA job started", "interval", j.interval)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := j.uc.EnforceReviewSLA(ctx, time.Now())
+		if err != nil {
+			slog.Error("review SLA check failed", "error", err)
+		}
+
+		reviewSLAActions.WithLabelValues("reminder").Add(float64(result.Reminded))
+		reviewSLAActions.WithLabelValues("escalation").Add(float64(result.Escalated))
+		reviewSLAActions.WithLabelValues("escalation_failed").Add(float64(result.Failed))
+		reviewSLAActions.WithLabelValues("error").Add(float64(result.Errors))
+
+		if err == nil && result.MoreDue(usecase.SLABatchSize) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("review SLA job stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
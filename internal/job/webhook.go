@@ -0,0 +1,57 @@
+package job
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/usecase"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WebhookDispatcher периодически отправляет накопившиеся доставки вебхуков
+type WebhookDispatcher struct {
+	uc       *usecase.UseCase
+	sender   usecase.WebhookSender
+	policy   entity.RetryPolicy
+	interval time.Duration
+	lease    time.Duration
+}
+
+// NewWebhookDispatcher создает диспетчер; lease — на сколько забранная доставка скрывается
+// от других экземпляров сервиса, должен превышать время отправки пачки
+func NewWebhookDispatcher(uc *usecase.UseCase, sender usecase.WebhookSender, policy entity.RetryPolicy, interval, lease time.Duration) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		uc:       uc,
+		sender:   sender,
+		policy:   policy,
+		interval: interval,
+		lease:    lease,
+	}
+}
+
+// Run отправляет доставки сразу и затем каждые interval, пока не отменен ctx; полная
+// пачка обрабатывается без ожидания следующего тика
+func (j *WebhookDispatcher) Run(ctx context.Context) {
+	slog.Info("webhook dispatcher started", "interval", j.interval, "maxAttempts", j.policy.MaxAttempts)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		sent, err := j.uc.DispatchWebhooks(ctx, j.sender, j.policy, j.lease)
+		if err != nil {
+			slog.Error("webhook dispatch failed", "error", err)
+		}
+
+		if err == nil && sent == usecase.WebhookBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"avito_test_task/internal/entity"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+)
+
+// AuditRequest сохраняет в контексте запроса эндпоинт и SHA-256 тела для журнала аудита.
+// Тело читается целиком и подменяется копией, чтобы обработчик мог его разобрать
+func AuditRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet {
+			c.Next()
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			body, err = io.ReadAll(c.Request.Body)
+			if err != nil {
+				_ = c.Error(entity.WrapError(entity.InvalidRequest, err, "failed to read request body"))
+				c.Abort()
+				return
+			}
+
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		hash := sha256.Sum256(body)
+		c.Request = c.Request.WithContext(entity.WithRequestInfo(c.Request.Context(), entity.RequestInfo{
+			Endpoint:    c.Request.Method + " " + c.FullPath(),
+			PayloadHash: hex.EncodeToString(hash[:]),
+		}))
+		c.Next()
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"avito_test_task/internal/entity"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+)
+
+var errorStatuses = map[entity.ErrorCode]int{
+	entity.InvalidRequest:    http.StatusBadRequest,
+	entity.Unauthorized:      http.StatusUnauthorized,
+	entity.Forbidden:         http.StatusForbidden,
+	entity.NotFound:          http.StatusNotFound,
+	entity.TeamExists:        http.StatusConflict,
+	entity.PRExists:          http.StatusConflict,
+	entity.PRMerged:          http.StatusConflict,
+	entity.PRNotOpen:         http.StatusConflict,
+	entity.NotAssigned:       http.StatusConflict,
+	entity.NoCandidate:       http.StatusConflict,
+	entity.PolicyViolated:    http.StatusConflict,
+	entity.NotApproved:       http.StatusConflict,
+	entity.InvalidTransition: http.StatusConflict,
+	entity.MemberExists:      http.StatusConflict,
+	entity.TeamNotEmpty:      http.StatusConflict,
+	entity.BindingExists:     http.StatusConflict,
+	entity.APIKeyExists:      http.StatusConflict,
+}
+
+// ErrorHandler отдает ошибки, добавленные обработчиками через c.Error, в формате entity.ErrorResponse
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		code := entity.CodeOf(err)
+
+		status, ok := errorStatuses[code]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+
+		message := "internal server error"
+		var domainErr *entity.Error
+		if errors.As(err, &domainErr) && status != http.StatusInternalServerError {
+			message = domainErr.Message
+		}
+
+		if status >= http.StatusInternalServerError {
+			slog.Error("request failed", "error", err, "path", c.FullPath())
+		} else {
+			slog.Warn("request rejected", "code", code, "error", err, "path", c.FullPath())
+		}
+
+		c.JSON(status, entity.ErrorResponse{
+			Error: entity.ErrorBody{
+				Code:    code,
+				Message: message,
+			},
+		})
+	}
+}
@@ -1,44 +1,131 @@
 package middleware
 
 import (
+	"avito_test_task/internal/auth"
+	"avito_test_task/internal/entity"
+	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
-	"net/http"
+	"slices"
 	"strings"
 )
 
-func RequireAdmin() gin.HandlerFunc {
+// IdentityKey ключ gin-контекста, под которым хранится entity.Actor вызывающего
+const IdentityKey = "identity"
+
+// Authorizer проверяет API-ключи и право инициатора на действие над объектами запроса
+type Authorizer interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (entity.Actor, error)
+	Authorize(ctx context.Context, actor entity.Actor, perm entity.Permission, target entity.AuthTarget) error
+}
+
+// Auth проверяет JWT или API-ключ из заголовка Authorization: Bearer (либо X-API-Key)
+// и права вызывающего
+type Auth struct {
+	verifier   *auth.Verifier
+	authorizer Authorizer
+}
+
+func NewAuth(verifier *auth.Verifier, authorizer Authorizer) *Auth {
+	return &Auth{verifier: verifier, authorizer: authorizer}
+}
+
+// Require пропускает запрос с валидным токеном и правом perm. Команды, в которых проверяется
+// право, определяют resolvers по полям запроса; без них учитываются только глобальные роли
+func (a *Auth) Require(perm entity.Permission, resolvers ...TargetResolver) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := strings.ToUpper(strings.TrimSpace(c.GetHeader("token")))
-		if token != "ADMIN" {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": gin.H{
-					"code":    "FORBIDDEN",
-					"message": "admin access required",
-				},
-			})
+		actor, err := a.authenticate(c)
+		if err != nil {
+			_ = c.Error(err)
 			c.Abort()
 			return
 		}
-		c.Set("role", token)
-		c.Next()
-	}
-}
 
-func RequireAdminOrUser() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		token := strings.TrimSpace(c.GetHeader("token"))
-		token = strings.ToUpper(token)
-		if token != "ADMIN" && token != "USER" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": gin.H{
-					"code":    "UNAUTHORIZED",
-					"message": "token required: ADMIN or USER",
-				},
-			})
+		var target entity.AuthTarget
+		for _, resolve := range resolvers {
+			if err := resolve(c, &target); err != nil {
+				_ = c.Error(err)
+				c.Abort()
+				return
+			}
+		}
+
+		if err := a.authorizer.Authorize(c.Request.Context(), actor, perm, target); err != nil {
+			_ = c.Error(err)
 			c.Abort()
 			return
 		}
-		c.Set("role", token)
+
 		c.Next()
 	}
 }
+
+// authenticate проверяет JWT или API-ключ и сохраняет вызывающего в gin-контексте и контексте запроса
+func (a *Auth) authenticate(c *gin.Context) (entity.Actor, error) {
+	if actor, ok := Identity(c); ok {
+		return actor, nil
+	}
+
+	token := strings.TrimSpace(c.GetHeader("X-API-Key"))
+	if token == "" {
+		scheme, value, ok := strings.Cut(strings.TrimSpace(c.GetHeader("Authorization")), " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(value) == "" {
+			return entity.Actor{}, entity.NewError(entity.Unauthorized, "bearer token or api key required")
+		}
+
+		token = strings.TrimSpace(value)
+	}
+
+	var (
+		actor entity.Actor
+		err   error
+	)
+
+	if strings.HasPrefix(token, entity.APIKeyPrefix) {
+		actor, err = a.authorizer.AuthenticateAPIKey(c.Request.Context(), token)
+	} else {
+		actor, err = a.verifyJWT(token)
+	}
+
+	if err != nil {
+		return entity.Actor{}, err
+	}
+
+	c.Set(IdentityKey, actor)
+	c.Request = c.Request.WithContext(entity.WithActor(c.Request.Context(), actor))
+	return actor, nil
+}
+
+func (a *Auth) verifyJWT(token string) (entity.Actor, error) {
+	claims, err := a.verifier.Verify(token)
+	if err != nil {
+		if errors.Is(err, auth.ErrExpiredToken) {
+			return entity.Actor{}, entity.WrapError(entity.Unauthorized, err, "token expired")
+		}
+
+		return entity.Actor{}, entity.WrapError(entity.Unauthorized, err, "invalid token")
+	}
+
+	actor := entity.Actor{ID: claims.Subject}
+	for _, role := range claims.Roles {
+		role = strings.ToUpper(strings.TrimSpace(role))
+		if !slices.Contains(entity.TokenRoles, role) {
+			return entity.Actor{}, entity.NewError(entity.Unauthorized, "role %s cannot be granted by token", role)
+		}
+
+		actor.Roles = append(actor.Roles, role)
+	}
+
+	return actor, nil
+}
+
+// Identity возвращает вызывающего, прошедшего аутентификацию
+func Identity(c *gin.Context) (entity.Actor, bool) {
+	value, ok := c.Get(IdentityKey)
+	if !ok {
+		return entity.Actor{}, false
+	}
+
+	actor, ok := value.(entity.Actor)
+	return actor, ok
+}
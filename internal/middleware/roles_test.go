@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"avito_test_task/internal/auth"
+	"avito_test_task/internal/entity"
+	"context"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSecret = "test-secret"
+
+// allowAll разрешает любое действие и запоминает проверенного инициатора и объекты запроса
+type allowAll struct {
+	actor  entity.Actor
+	target entity.AuthTarget
+}
+
+func (a *allowAll) AuthenticateAPIKey(context.Context, string) (entity.Actor, error) {
+	return entity.Actor{}, entity.NewError(entity.Unauthorized, "api keys are not supported")
+}
+
+func (a *allowAll) Authorize(_ context.Context, actor entity.Actor, _ entity.Permission, target entity.AuthTarget) error {
+	a.actor = actor
+	a.target = target
+	return nil
+}
+
+func TestRequireTokenRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	verifier, err := auth.NewVerifier(&auth.Config{Secret: testSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		roles      []string
+		wantStatus int
+		wantRoles  []string
+	}{
+		{name: "admin", roles: []string{"ADMIN"}, wantStatus: http.StatusOK, wantRoles: []string{"ADMIN"}},
+		{name: "lowercase user", roles: []string{" user "}, wantStatus: http.StatusOK, wantRoles: []string{"USER"}},
+		{name: "system", roles: []string{"system"}, wantStatus: http.StatusUnauthorized},
+		{name: "api key", roles: []string{"USER", "API_KEY"}, wantStatus: http.StatusUnauthorized},
+		{name: "team lead", roles: []string{"TEAM_LEAD"}, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authorizer := &allowAll{}
+			r := gin.New()
+			r.Use(ErrorHandler())
+			r.GET("/", NewAuth(verifier, authorizer).Require(entity.PermTeamRead), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			token, err := auth.SignHS256(&auth.Claims{
+				Subject:   "u1",
+				Roles:     tt.roles,
+				ExpiresAt: time.Now().Add(time.Hour).Unix(),
+			}, testSecret)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			if len(authorizer.actor.Roles) != len(tt.wantRoles) || authorizer.actor.Roles[0] != tt.wantRoles[0] {
+				t.Errorf("roles = %v, want %v", authorizer.actor.Roles, tt.wantRoles)
+			}
+		})
+	}
+}
+
+func TestRequireResolvesTarget(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	verifier, err := auth.NewVerifier(&auth.Config{Secret: testSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := auth.SignHS256(&auth.Claims{
+		Subject:   "u1",
+		Roles:     []string{"USER"},
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}, testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantTarget entity.AuthTarget
+	}{
+		{
+			name:       "all fields",
+			body:       `{"team_name":"backend","user_ids":["u1","u2"],"pull_request_id":"pr1","absence_id":7,"author_id":"u1"}`,
+			wantStatus: http.StatusOK,
+			wantTarget: entity.AuthTarget{Teams: []string{"backend", "frontend"}, UserIDs: []string{"u1", "u2"}, PRID: "pr1", AbsenceID: 7, OwnerIDs: []string{"u1"}},
+		},
+		{
+			name:       "field name in another case",
+			body:       `{"Team_Name":"backend"}`,
+			wantStatus: http.StatusOK,
+			wantTarget: entity.AuthTarget{Teams: []string{"backend", "frontend"}},
+		},
+		{name: "duplicate field", body: `{"team_name":"frontend","TEAM_NAME":"backend"}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid absence id", body: `{"absence_id":"seven"}`, wantStatus: http.StatusBadRequest},
+		{name: "not an object", body: `["backend"]`, wantStatus: http.StatusBadRequest},
+		{name: "trailing data", body: `{"team_name":"backend"}{"team_name":"frontend"}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authorizer := &allowAll{}
+			r := gin.New()
+			r.Use(ErrorHandler())
+			r.POST("/", NewAuth(verifier, authorizer).Require(entity.PermTeamManage,
+				FromBody(TargetTeam, "team_name"),
+				FromQuery(TargetTeam, "team"),
+				FromBody(TargetUser, "user_ids"),
+				FromBody(TargetPR, "pull_request_id"),
+				FromBody(TargetAbsence, "absence_id"),
+				FromBody(TargetOwner, "author_id"),
+			), func(c *gin.Context) {
+				// тело остается доступным обработчику
+				body, _ := io.ReadAll(c.Request.Body)
+				if string(body) != tt.body {
+					t.Errorf("handler body = %s, want %s", body, tt.body)
+				}
+
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/?team=frontend", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			if tt.wantStatus == http.StatusOK && !reflect.DeepEqual(authorizer.target, tt.wantTarget) {
+				t.Errorf("target = %+v, want %+v", authorizer.target, tt.wantTarget)
+			}
+		})
+	}
+}
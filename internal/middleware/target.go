@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"avito_test_task/internal/entity"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const bodyFieldsKey = "bodyFields"
+
+// TargetField вид объекта, по которому определяется команда запроса
+type TargetField int
+
+const (
+	TargetTeam TargetField = iota
+	TargetUser
+	TargetPR
+	TargetAbsence
+	// TargetOwner пользователь, от имени которого действует запрос
+	TargetOwner
+)
+
+// TargetResolver добавляет в target объекты из запроса
+type TargetResolver func(c *gin.Context, target *entity.AuthTarget) error
+
+// FromQuery берет объект из query-параметра key
+func FromQuery(field TargetField, key string) TargetResolver {
+	return func(c *gin.Context, target *entity.AuthTarget) error {
+		if value := c.Query(key); value != "" {
+			return addTarget(target, field, []string{value})
+		}
+
+		return nil
+	}
+}
+
+// FromBody берет объект из поля key JSON-тела; поле может быть строкой, числом или массивом строк.
+// Имя поля сравнивается без учета регистра, как при привязке тела в обработчике.
+// Тело не расходуется и остается доступным обработчику
+func FromBody(field TargetField, key string) TargetResolver {
+	return func(c *gin.Context, target *entity.AuthTarget) error {
+		fields, err := bodyFields(c)
+		if err != nil {
+			return err
+		}
+
+		raw, ok := lookupField(fields, key)
+		if !ok {
+			return nil
+		}
+
+		var values []string
+		var single any
+		if err := json.Unmarshal(raw, &values); err != nil {
+			if err := json.Unmarshal(raw, &single); err != nil {
+				return entity.WrapError(entity.InvalidRequest, err, "invalid %s", key)
+			}
+
+			switch v := single.(type) {
+			case string:
+				values = []string{v}
+			case float64:
+				values = []string{strconv.FormatFloat(v, 'f', -1, 64)}
+			}
+		}
+
+		return addTarget(target, field, values)
+	}
+}
+
+func addTarget(target *entity.AuthTarget, field TargetField, values []string) error {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+
+		switch field {
+		case TargetTeam:
+			target.Teams = append(target.Teams, value)
+		case TargetUser:
+			target.UserIDs = append(target.UserIDs, value)
+		case TargetPR:
+			target.PRID = value
+		case TargetAbsence:
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return entity.WrapError(entity.InvalidRequest, err, "invalid absence id")
+			}
+
+			target.AbsenceID = id
+		case TargetOwner:
+			target.OwnerIDs = append(target.OwnerIDs, value)
+		}
+	}
+
+	return nil
+}
+
+// bodyFields разбирает JSON-тело в поля верхнего уровня и кэширует результат в gin-контексте.
+// Обработчик привязывает тело без учета регистра и берет последнее из повторов, поэтому тело
+// с повторяющимися полями или полями, различающимися регистром, отклоняется: иначе права
+// проверялись бы по одному значению, а действие выполнялось бы с другим
+func bodyFields(c *gin.Context) (map[string]json.RawMessage, error) {
+	if cached, ok := c.Get(bodyFieldsKey); ok {
+		return cached.(map[string]json.RawMessage), nil
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if c.Request.Body != nil {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return nil, entity.WrapError(entity.InvalidRequest, err, "failed to read request body")
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		if len(bytes.TrimSpace(body)) > 0 {
+			if fields, err = decodeFields(body); err != nil {
+				return nil, entity.NewError(entity.InvalidRequest, "invalid request body: %v", err)
+			}
+		}
+	}
+
+	c.Set(bodyFieldsKey, fields)
+	return fields, nil
+}
+
+// decodeFields разбирает JSON-объект в поля верхнего уровня, проверяя, что имена полей
+// не повторяются без учета регистра, а после объекта нет других данных
+func decodeFields(body []byte) (map[string]json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, errors.New("body must be a JSON object")
+	}
+
+	fields := make(map[string]json.RawMessage)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok := tok.(string)
+		if !ok {
+			return nil, errors.New("invalid field name")
+		}
+
+		if _, ok := lookupField(fields, key); ok {
+			return nil, fmt.Errorf("duplicate field %s", key)
+		}
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+
+		fields[key] = raw
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("unexpected data after JSON object")
+	}
+
+	return fields, nil
+}
+
+// lookupField ищет поле без учета регистра, как encoding/json при привязке к структуре
+func lookupField(fields map[string]json.RawMessage, key string) (json.RawMessage, bool) {
+	if raw, ok := fields[key]; ok {
+		return raw, true
+	}
+
+	for name, raw := range fields {
+		if strings.EqualFold(name, key) {
+			return raw, true
+		}
+	}
+
+	return nil, false
+}
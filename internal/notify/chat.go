@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"avito_test_task/internal/entity"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrNonPublicAddress подключение к непубличному адресу запрещено
+var ErrNonPublicAddress = errors.New("connections to non-public addresses are not allowed")
+
+// ChatSender отправляет уведомления канала CHAT во входящий вебхук чата (Slack, Mattermost
+// и совместимые): POST JSON {"text": ...}; успешной считается отправка с ответом 2xx.
+// URL задает пользователь, поэтому подключения, в том числе после редиректов, разрешены только
+// к публичным адресам, а прокси из окружения не используется
+type ChatSender struct {
+	client *http.Client
+}
+
+func NewChatSender(timeout time.Duration) *ChatSender {
+	return &ChatSender{client: NewPublicClient(timeout)}
+}
+
+// NewPublicClient HTTP-клиент для запросов по URL, заданным пользователями: подключается,
+// в том числе после редиректов, только к публичным адресам и не использует прокси из окружения
+func NewPublicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: publicOnly}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// publicOnly проверяет адрес уже после разрешения DNS, поэтому имя, указывающее
+// на внутренний адрес, отсекается так же, как IP-адрес в URL
+func publicOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+
+	if !entity.IsPublicAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddress, addr)
+	}
+
+	return nil
+}
+
+// Send рендерит сообщение и отправляет его на URL вебхука пользователя
+func (s *ChatSender) Send(ctx context.Context, n *entity.Notification) error {
+	msg, err := Render(n)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]string{"text": msg.Body})
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.Address, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "avito-test-task-notifications")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return nil
+}
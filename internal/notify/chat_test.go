@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestChatSenderRefusesNonPublicAddress(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	payload, _ := json.Marshal(entity.NewEvent(entity.EventPRMerged, "backend", entity.PREventData{PullRequestID: "pr1"}))
+	err := NewChatSender(time.Second).Send(context.Background(), &entity.Notification{
+		Channel:   entity.ChannelChat,
+		Address:   srv.URL,
+		EventType: entity.EventPRMerged,
+		Payload:   payload,
+	})
+
+	if !errors.Is(err, ErrNonPublicAddress) {
+		t.Fatalf("Send error = %v, want %v", err, ErrNonPublicAddress)
+	}
+
+	if called {
+		t.Error("request reached a loopback server")
+	}
+}
@@ -0,0 +1,154 @@
+package notify
+
+import (
+	"avito_test_task/internal/entity"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+	"time"
+)
+
+// Message отрендеренное уведомление: тема (используется только в письмах) и текст
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// templateData данные шаблона: событие из outbox и получатель уведомления
+type templateData struct {
+	UserID string
+	Event  struct {
+		Type       entity.EventType `json:"type"`
+		TeamName   string           `json:"team_name"`
+		OccurredAt string           `json:"occurred_at"`
+		Data       struct {
+			PullRequestID string   `json:"pull_request_id"`
+			Name          string   `json:"pull_request_name"`
+			AuthorID      string   `json:"author_id"`
+			Reviewers     []string `json:"reviewers"`
+			ReviewerID    string   `json:"reviewer_id"`
+			OldReviewerID string   `json:"old_reviewer_id"`
+			Reason        string   `json:"reason"`
+			IdleSince     string   `json:"idle_since"`
+			EscalatesAt   string   `json:"escalates_at"`
+		} `json:"data"`
+	}
+}
+
+type channelTemplates struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// templates шаблоны уведомлений по каналу и типу события. Переназначение рендерится по-разному
+// для нового ревьюера и для снятого: шаблон сравнивает .UserID с ReviewerID
+var templates = map[entity.NotificationChannel]map[entity.EventType]channelTemplates{
+	entity.ChannelEmail: {
+		entity.EventReviewerAssigned: parse(
+			`Вас назначили ревьюером {{.Event.Data.PullRequestID}}`,
+			`Здравствуйте, {{.UserID}}!
+
+Вас назначили ревьюером pull request {{.Event.Data.PullRequestID}} в команде {{.Event.TeamName}}.
+Причина: {{.Event.Data.Reason}}.
+`),
+		entity.EventReviewerReassigned: parse(
+			`{{if eq .UserID .Event.Data.ReviewerID}}Вас назначили ревьюером{{else}}Вас сняли с ревью{{end}} {{.Event.Data.PullRequestID}}`,
+			`Здравствуйте, {{.UserID}}!
+
+{{if eq .UserID .Event.Data.ReviewerID -}}
+Вы заменили {{.Event.Data.OldReviewerID}} в ревью pull request {{.Event.Data.PullRequestID}}.
+{{- else -}}
+Ревью pull request {{.Event.Data.PullRequestID}} передано {{.Event.Data.ReviewerID}}.
+{{- end}}
+Причина: {{.Event.Data.Reason}}.
+`),
+		entity.EventReviewerReminded: parse(
+			`Напоминание: ревью {{.Event.Data.PullRequestID}} ждет вашего решения`,
+			`Здравствуйте, {{.UserID}}!
+
+Pull request {{.Event.Data.PullRequestID}} в команде {{.Event.TeamName}} ждет вашего решения с {{time .Event.Data.IdleSince}}.
+{{- if .Event.Data.EscalatesAt}}
+Если решения не будет, ревью автоматически передадут другому участнику в {{time .Event.Data.EscalatesAt}}.
+{{- end}}
+`),
+		entity.EventPRMerged: parse(
+			`PR {{.Event.Data.PullRequestID}} смержен`,
+			`Здравствуйте, {{.UserID}}!
+
+Pull request {{.Event.Data.PullRequestID}} «{{.Event.Data.Name}}» автора {{.Event.Data.AuthorID}} смержен.
+{{- if .Event.Data.Reviewers}}
+Ревьюеры: {{range $i, $r := .Event.Data.Reviewers}}{{if $i}}, {{end}}{{$r}}{{end}}.
+{{- end}}
+`),
+	},
+	entity.ChannelChat: {
+		entity.EventReviewerAssigned: parse("",
+			`:eyes: {{.UserID}}, вас назначили ревьюером *{{.Event.Data.PullRequestID}}* ({{.Event.Data.Reason}})`),
+		entity.EventReviewerReassigned: parse("",
+			`{{if eq .UserID .Event.Data.ReviewerID -}}
+:eyes: {{.UserID}}, вы заменили {{.Event.Data.OldReviewerID}} в ревью *{{.Event.Data.PullRequestID}}* ({{.Event.Data.Reason}})
+{{- else -}}
+:wave: {{.UserID}}, ревью *{{.Event.Data.PullRequestID}}* передано {{.Event.Data.ReviewerID}} ({{.Event.Data.Reason}})
+{{- end}}`),
+		entity.EventReviewerReminded: parse("",
+			`:hourglass: {{.UserID}}, *{{.Event.Data.PullRequestID}}* ждет вашего ревью с {{time .Event.Data.IdleSince}}
+{{- if .Event.Data.EscalatesAt}}, передача другому ревьюеру в {{time .Event.Data.EscalatesAt}}{{end}}`),
+		entity.EventPRMerged: parse("",
+			`:white_check_mark: *{{.Event.Data.PullRequestID}}* «{{.Event.Data.Name}}» смержен`),
+	},
+}
+
+// Render рендерит уведомление шаблоном его канала и типа события
+func Render(n *entity.Notification) (Message, error) {
+	tmpl, ok := templates[n.Channel][n.EventType]
+	if !ok {
+		return Message{}, fmt.Errorf("no %s template for event %s", n.Channel, n.EventType)
+	}
+
+	data := templateData{UserID: n.UserID}
+	if err := json.Unmarshal(n.Payload, &data.Event); err != nil {
+		return Message{}, fmt.Errorf("decode event: %w", err)
+	}
+
+	var msg Message
+	var buf bytes.Buffer
+	if tmpl.subject != nil {
+		if err := tmpl.subject.Execute(&buf, data); err != nil {
+			return Message{}, fmt.Errorf("render subject: %w", err)
+		}
+
+		msg.Subject = buf.String()
+		buf.Reset()
+	}
+
+	if err := tmpl.body.Execute(&buf, data); err != nil {
+		return Message{}, fmt.Errorf("render body: %w", err)
+	}
+
+	msg.Body = buf.String()
+	return msg, nil
+}
+
+var funcs = template.FuncMap{
+	"time": formatTime,
+}
+
+func parse(subject, body string) channelTemplates {
+	t := channelTemplates{body: template.Must(template.New("body").Funcs(funcs).Parse(body))}
+	if subject != "" {
+		t.subject = template.Must(template.New("subject").Funcs(funcs).Parse(subject))
+	}
+
+	return t
+}
+
+// formatTime выводит время события в UTC с точностью до минуты
+func formatTime(value string) string {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return value
+	}
+
+	return t.UTC().Format("02.01.2006 15:04 UTC")
+}
@@ -0,0 +1,57 @@
+package publisher
+
+import (
+	"avito_test_task/internal/entity"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Заголовки публикации; по Idempotency-Key получатель отбрасывает повторы
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	HeaderEventType      = "X-Event-Type"
+)
+
+// HTTP отправляет каждое событие POST-запросом; успешным считается ответ 2xx
+type HTTP struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTP(url string, timeout time.Duration) *HTTP {
+	return &HTTP{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+// Publish отправляет тело события с ключом идемпотентности
+func (p *HTTP) Publish(ctx context.Context, event *entity.OutboxEvent) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(event.Payload))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderIdempotencyKey, event.Key)
+	req.Header.Set(HeaderEventType, string(event.Type))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (p *HTTP) Close() error {
+	return nil
+}
@@ -0,0 +1,51 @@
+package publisher
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	KindStdout = "stdout"
+	KindFile   = "file"
+	KindHTTP   = "http"
+)
+
+// Config выбор публикатора событий outbox: stdout, file (File — путь к JSONL-файлу)
+// или http (URL получателя, Timeout — таймаут запроса)
+type Config struct {
+	Kind    string
+	File    string
+	URL     string
+	Timeout time.Duration
+}
+
+// Publisher публикует события outbox; Close освобождает ресурсы публикатора
+type Publisher interface {
+	Publish(ctx context.Context, event *entity.OutboxEvent) error
+	Close() error
+}
+
+// New создает публикатор по конфигурации
+func New(cfg *Config) (Publisher, error) {
+	switch cfg.Kind {
+	case KindStdout, "":
+		return NewStdout(), nil
+	case KindFile:
+		if cfg.File == "" {
+			return nil, fmt.Errorf("outbox file is required for %s publisher", KindFile)
+		}
+
+		return NewFile(cfg.File)
+	case KindHTTP:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("outbox url is required for %s publisher", KindHTTP)
+		}
+
+		return NewHTTP(cfg.URL, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown outbox publisher: %s", cfg.Kind)
+	}
+}
@@ -0,0 +1,60 @@
+package publisher
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// Writer пишет события построчно в JSON (JSONL) в stdout или файл
+type Writer struct {
+	mu sync.Mutex
+	w  io.Writer
+	c  io.Closer
+}
+
+// record строка JSONL: ключ идемпотентности и тип рядом с самим событием
+type record struct {
+	Key     string           `json:"key"`
+	Type    entity.EventType `json:"type"`
+	Payload json.RawMessage  `json:"payload"`
+}
+
+func NewStdout() *Writer {
+	return &Writer{w: os.Stdout}
+}
+
+// NewFile открывает файл на дозапись, создавая его при необходимости
+func NewFile(path string) (*Writer, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{w: f, c: f}, nil
+}
+
+// Publish дописывает событие одной строкой
+func (p *Writer) Publish(_ context.Context, event *entity.OutboxEvent) error {
+	line, err := json.Marshal(record{Key: event.Key, Type: event.Type, Payload: event.Payload})
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err = p.w.Write(append(line, '\n'))
+	return err
+}
+
+func (p *Writer) Close() error {
+	if p.c == nil {
+		return nil
+	}
+
+	return p.c.Close()
+}
@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"sort"
+	"time"
+)
+
+// CreateAbsence добавляет период отсутствия пользователя
+func (r *Repository) CreateAbsence(ctx context.Context, absence *entity.Absence) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[absence.UserID]; !ok {
+		return entity.ErrUserNotFound
+	}
+
+	r.nextAbsenceID++
+	absence.ID = r.nextAbsenceID
+	absence.CreatedAt = time.Now()
+	r.absences[absence.ID] = copyAbsence(absence)
+	return nil
+}
+
+// GetAbsence получает период отсутствия по идентификатору
+func (r *Repository) GetAbsence(ctx context.Context, id int64) (*entity.Absence, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a, ok := r.absences[id]
+	if !ok {
+		return nil, entity.ErrAbsenceNotFound
+	}
+
+	return copyAbsence(a), nil
+}
+
+// GetAbsences получает периоды отсутствия пользователя, отсортированные по началу
+func (r *Repository) GetAbsences(ctx context.Context, userID string) ([]*entity.Absence, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	absences := make([]*entity.Absence, 0)
+	for _, a := range r.absences {
+		if a.UserID == userID {
+			absences = append(absences, copyAbsence(a))
+		}
+	}
+
+	sort.Slice(absences, func(i, j int) bool {
+		return absences[i].StartsAt.Before(absences[j].StartsAt)
+	})
+
+	return absences, nil
+}
+
+// GetDueAbsences получает отсутствия, у которых наступила необработанная граница:
+// начало без applied_at или конец без released_at
+func (r *Repository) GetDueAbsences(ctx context.Context, at time.Time) ([]*entity.Absence, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	absences := make([]*entity.Absence, 0)
+	for _, a := range r.absences {
+		if a.ReleasedAt != nil {
+			continue
+		}
+
+		if !a.EndsAt.After(at) || (a.AppliedAt == nil && !a.StartsAt.After(at)) {
+			absences = append(absences, copyAbsence(a))
+		}
+	}
+
+	boundary := func(a *entity.Absence) time.Time {
+		if a.AppliedAt == nil {
+			return a.StartsAt
+		}
+
+		return a.EndsAt
+	}
+
+	sort.Slice(absences, func(i, j int) bool {
+		bi, bj := boundary(absences[i]), boundary(absences[j])
+		if !bi.Equal(bj) {
+			return bi.Before(bj)
+		}
+
+		return absences[i].ID < absences[j].ID
+	})
+
+	return absences, nil
+}
+
+// GetCurrentAbsence получает незавершенное отсутствие пользователя, покрывающее момент at,
+// или nil, если его нет
+func (r *Repository) GetCurrentAbsence(ctx context.Context, userID string, at time.Time) (*entity.Absence, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var current *entity.Absence
+	for _, a := range r.absences {
+		if a.UserID != userID || a.ReleasedAt != nil || !a.Covers(at) {
+			continue
+		}
+
+		if current == nil || a.StartsAt.Before(current.StartsAt) {
+			current = a
+		}
+	}
+
+	if current == nil {
+		return nil, nil
+	}
+
+	return copyAbsence(current), nil
+}
+
+// UpdateAbsence сохраняет состояние синхронизации отсутствия
+func (r *Repository) UpdateAbsence(ctx context.Context, absence *entity.Absence) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, ok := r.absences[absence.ID]
+	if !ok {
+		return entity.ErrAbsenceNotFound
+	}
+
+	a.Deactivated = absence.Deactivated
+	a.AppliedAt = copyTime(absence.AppliedAt)
+	a.ReleasedAt = copyTime(absence.ReleasedAt)
+	return nil
+}
+
+// DeleteAbsence удаляет период отсутствия
+func (r *Repository) DeleteAbsence(ctx context.Context, id int64) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.absences[id]; !ok {
+		return entity.ErrAbsenceNotFound
+	}
+
+	delete(r.absences, id)
+	return nil
+}
+
+// isAway проверяет, отсутствует ли пользователь в момент at; вызывается под блокировкой
+func (r *Repository) isAway(userID string, at time.Time) bool {
+	for _, a := range r.absences {
+		if a.UserID == userID && a.Covers(at) {
+			return true
+		}
+	}
+
+	return false
+}
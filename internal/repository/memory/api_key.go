@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"sort"
+	"time"
+)
+
+// apiKeyTouchInterval не чаще этого интервала обновляется LastUsedAt
+const apiKeyTouchInterval = time.Minute
+
+// CreateAPIKey сохраняет API-ключ; имя должно быть уникально среди неотозванных ключей
+func (r *Repository) CreateAPIKey(ctx context.Context, key *entity.APIKey) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, k := range r.apiKeys {
+		if k.Name == key.Name && k.RevokedAt == nil {
+			return entity.NewError(entity.APIKeyExists, "api key %s already exists", key.Name)
+		}
+	}
+
+	r.nextAPIKeyID++
+	key.ID = r.nextAPIKeyID
+	key.CreatedAt = time.Now()
+	r.apiKeys[key.ID] = copyAPIKey(key)
+	return nil
+}
+
+// GetAPIKeyByHash получает API-ключ по SHA-256 ключа
+func (r *Repository) GetAPIKeyByHash(ctx context.Context, hash string) (*entity.APIKey, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, k := range r.apiKeys {
+		if k.Hash == hash {
+			return copyAPIKey(k), nil
+		}
+	}
+
+	return nil, entity.ErrAPIKeyNotFound
+}
+
+// ListAPIKeys получает все API-ключи, новые первыми
+func (r *Repository) ListAPIKeys(ctx context.Context) ([]*entity.APIKey, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*entity.APIKey, 0, len(r.apiKeys))
+	for _, k := range r.apiKeys {
+		keys = append(keys, copyAPIKey(k))
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ID > keys[j].ID
+	})
+
+	return keys, nil
+}
+
+// RevokeAPIKey отзывает API-ключ; повторный отзыв сохраняет исходное время
+func (r *Repository) RevokeAPIKey(ctx context.Context, id int64, at time.Time) (*entity.APIKey, error) {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k, ok := r.apiKeys[id]
+	if !ok {
+		return nil, entity.ErrAPIKeyNotFound
+	}
+
+	if k.RevokedAt == nil {
+		k.RevokedAt = &at
+	}
+
+	return copyAPIKey(k), nil
+}
+
+// TouchAPIKey отмечает использование API-ключа
+func (r *Repository) TouchAPIKey(ctx context.Context, id int64, at time.Time) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k, ok := r.apiKeys[id]
+	if !ok {
+		return entity.ErrAPIKeyNotFound
+	}
+
+	if k.LastUsedAt == nil || k.LastUsedAt.Before(at.Add(-apiKeyTouchInterval)) {
+		k.LastUsedAt = &at
+	}
+
+	return nil
+}
+
+func copyAPIKey(k *entity.APIKey) *entity.APIKey {
+	c := *k
+	c.Scopes = append([]entity.Permission(nil), k.Scopes...)
+	c.ExpiresAt = copyTime(k.ExpiresAt)
+	c.LastUsedAt = copyTime(k.LastUsedAt)
+	c.RevokedAt = copyTime(k.RevokedAt)
+	return &c
+}
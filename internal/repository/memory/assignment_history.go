@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"time"
+)
+
+// AddAssignmentHistory дописывает записи в историю назначений ревьюеров
+func (r *Repository) AddAssignmentHistory(ctx context.Context, entries []*entity.AssignmentHistory) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, e := range entries {
+		if _, ok := r.prs[e.PullRequestID]; !ok {
+			return entity.ErrPRNotFound
+		}
+
+		e.ID = int64(len(r.assignmentHistory) + 1)
+		e.CreatedAt = now
+		r.assignmentHistory = append(r.assignmentHistory, *e)
+	}
+
+	return nil
+}
+
+// GetAssignmentHistory получает историю назначений в хронологическом порядке
+func (r *Repository) GetAssignmentHistory(ctx context.Context, filter entity.AssignmentHistoryFilter) ([]*entity.AssignmentHistory, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	history := make([]*entity.AssignmentHistory, 0)
+	for _, e := range r.assignmentHistory {
+		if filter.PullRequestID != "" && e.PullRequestID != filter.PullRequestID {
+			continue
+		}
+
+		if filter.UserID != "" && e.UserID != filter.UserID {
+			continue
+		}
+
+		entry := e
+		history = append(history, &entry)
+	}
+
+	return history, nil
+}
+
+// GetLastAssignedReviewer возвращает последнего назначенного ревьюера среди участников команды
+// или пустую строку, если назначений не было
+func (r *Repository) GetLastAssignedReviewer(ctx context.Context, teamName string) (string, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(r.assignmentHistory) - 1; i >= 0; i-- {
+		h := r.assignmentHistory[i]
+		if h.Event != entity.ReviewerAssigned {
+			continue
+		}
+
+		if u, ok := r.users[h.UserID]; ok && u.teamName == teamName {
+			return h.UserID, nil
+		}
+	}
+
+	return "", nil
+}
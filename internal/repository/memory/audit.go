@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"strconv"
+	"time"
+)
+
+// AddAuditEntry записывает действие в журнал аудита
+func (r *Repository) AddAuditEntry(ctx context.Context, e *entity.AuditEntry) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e.ID = int64(len(r.auditLog) + 1)
+	e.CreatedAt = time.Now()
+	r.auditLog = append(r.auditLog, *e)
+	return nil
+}
+
+// ListAuditEntries получает записи журнала аудита от новых к старым, не больше filter.Limit
+func (r *Repository) ListAuditEntries(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var before int64
+	if filter.After != nil {
+		id, err := strconv.ParseInt(filter.After.ID, 10, 64)
+		if err != nil {
+			return nil, entity.NewError(entity.InvalidRequest, "invalid cursor")
+		}
+
+		before = id
+	}
+
+	entries := make([]*entity.AuditEntry, 0)
+	for i := len(r.auditLog) - 1; i >= 0 && len(entries) < filter.Limit; i-- {
+		e := r.auditLog[i]
+		if before != 0 && e.ID >= before {
+			continue
+		}
+
+		if filter.Actor != "" && e.Actor != filter.Actor {
+			continue
+		}
+
+		if filter.EntityType != "" && e.EntityType != filter.EntityType {
+			continue
+		}
+
+		if filter.EntityID != "" && e.EntityID != filter.EntityID {
+			continue
+		}
+
+		if !inRange(&e.CreatedAt, filter.From, filter.To) {
+			continue
+		}
+
+		entries = append(entries, &e)
+	}
+
+	return entries, nil
+}
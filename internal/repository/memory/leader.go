@@ -0,0 +1,19 @@
+package memory
+
+import (
+	"context"
+	"sync"
+)
+
+// WithLeaderLock выполняет fn, только если блокировка name свободна. В памяти сервис работает
+// в одном экземпляре, блокировка лишь не дает задаче выполняться параллельно самой себе
+func (r *Repository) WithLeaderLock(ctx context.Context, name string, fn func(ctx context.Context) error) (bool, error) {
+	value, _ := r.leaderLocks.LoadOrStore(name, &sync.Mutex{})
+	lock := value.(*sync.Mutex)
+	if !lock.TryLock() {
+		return false, nil
+	}
+
+	defer lock.Unlock()
+	return true, fn(ctx)
+}
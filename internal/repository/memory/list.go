@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ListTeams получает команды по возрастанию имени, не больше filter.Limit
+func (r *Repository) ListTeams(ctx context.Context, filter entity.TeamFilter) ([]*entity.TeamSummary, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	teams := make([]*entity.TeamSummary, 0)
+	for _, t := range r.teams {
+		if !containsFold(t.name, filter.NameContains) {
+			continue
+		}
+
+		if filter.After != nil && t.name <= filter.After.ID {
+			continue
+		}
+
+		summary := &entity.TeamSummary{
+			Name:             t.name,
+			ReviewerStrategy: t.strategy,
+			CreatedAt:        t.createdAt,
+		}
+
+		for _, u := range r.teamMembers(t.name) {
+			summary.Members++
+			if u.isActive {
+				summary.ActiveMembers++
+			}
+		}
+
+		teams = append(teams, summary)
+	}
+
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].Name < teams[j].Name
+	})
+
+	return teams[:minimum(filter.Limit, len(teams))], nil
+}
+
+// ListUsers получает пользователей по возрастанию user_id, не больше filter.Limit
+func (r *Repository) ListUsers(ctx context.Context, filter entity.UserFilter) ([]*entity.User, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]*entity.User, 0)
+	for _, u := range r.users {
+		if filter.TeamName != "" && u.teamName != filter.TeamName {
+			continue
+		}
+
+		if filter.IsActive != nil && u.isActive != *filter.IsActive {
+			continue
+		}
+
+		if !containsFold(u.id, filter.NameContains) && !containsFold(u.name, filter.NameContains) {
+			continue
+		}
+
+		if filter.After != nil && u.id <= filter.After.ID {
+			continue
+		}
+
+		users = append(users, u.toEntity())
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].UserID < users[j].UserID
+	})
+
+	return users[:minimum(filter.Limit, len(users))], nil
+}
+
+// ListPRs получает PR от новых к старым с назначенными ревьюерами, не больше filter.Limit;
+// решения ревьюеров не загружаются
+func (r *Repository) ListPRs(ctx context.Context, filter entity.PRFilter) ([]*entity.PullRequest, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := make([]*pullRequest, 0)
+	for _, pr := range r.prs {
+		if filter.TeamName != "" {
+			author, ok := r.users[pr.authorID]
+			if !ok || author.teamName != filter.TeamName {
+				continue
+			}
+		}
+
+		if filter.Status != "" && pr.status != filter.Status {
+			continue
+		}
+
+		if filter.AuthorID != "" && pr.authorID != filter.AuthorID {
+			continue
+		}
+
+		if filter.ReviewerID != "" && !pr.hasReviewer(filter.ReviewerID) {
+			continue
+		}
+
+		if !containsFold(pr.name, filter.NameContains) {
+			continue
+		}
+
+		if !inRange(&pr.createdAt, filter.CreatedFrom, filter.CreatedTo) ||
+			!inRange(pr.mergedAt, filter.MergedFrom, filter.MergedTo) {
+			continue
+		}
+
+		if filter.After != nil && filter.After.CreatedAt != nil && !prBefore(pr, *filter.After.CreatedAt, filter.After.ID) {
+			continue
+		}
+
+		matched = append(matched, pr)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return prBefore(matched[j], matched[i].createdAt, matched[i].id)
+	})
+
+	matched = matched[:minimum(filter.Limit, len(matched))]
+	prs := make([]*entity.PullRequest, 0, len(matched))
+	for _, pr := range matched {
+		result := pr.toEntity()
+		result.Reviews = nil
+		prs = append(prs, result)
+	}
+
+	return prs, nil
+}
+
+// prBefore проверяет, что PR идет после позиции (createdAt, id) в порядке от новых к старым
+func prBefore(pr *pullRequest, createdAt time.Time, id string) bool {
+	if !pr.createdAt.Equal(createdAt) {
+		return pr.createdAt.Before(createdAt)
+	}
+
+	return pr.id < id
+}
+
+// inRange проверяет попадание t в [from, to); при заданных границах пустое t не подходит
+func inRange(t, from, to *time.Time) bool {
+	if from == nil && to == nil {
+		return true
+	}
+
+	if t == nil {
+		return false
+	}
+
+	return (from == nil || !t.Before(*from)) && (to == nil || t.Before(*to))
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func minimum(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,205 @@
+package memory
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"slices"
+	"sort"
+	"time"
+)
+
+// GetNotificationPreferences получает настройки уведомлений пользователя
+func (r *Repository) GetNotificationPreferences(ctx context.Context, userID string) (*entity.NotificationPreferences, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	prefs, ok := r.notificationPrefs[userID]
+	if !ok {
+		return nil, entity.ErrPreferencesNotFound
+	}
+
+	return copyPreferences(prefs), nil
+}
+
+// UpsertNotificationPreferences сохраняет настройки уведомлений пользователя
+func (r *Repository) UpsertNotificationPreferences(ctx context.Context, prefs *entity.NotificationPreferences) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[prefs.UserID]; !ok {
+		return entity.ErrUserNotFound
+	}
+
+	now := time.Now()
+	prefs.UpdatedAt = &now
+	r.notificationPrefs[prefs.UserID] = copyPreferences(prefs)
+	return nil
+}
+
+// LockConsumerOffset возвращает позицию потребителя outbox; новый потребитель начинает с конца outbox.
+// Транзакции хранилища в памяти выполняются по одной, поэтому отдельная блокировка не нужна
+func (r *Repository) LockConsumerOffset(ctx context.Context, name string) (entity.OutboxPosition, error) {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	position, ok := r.consumerOffsets[name]
+	if !ok {
+		position = entity.OutboxPosition{TxID: r.nextOutboxID, ID: r.nextOutboxID}
+		r.consumerOffsets[name] = position
+	}
+
+	return position, nil
+}
+
+// SetConsumerOffset сохраняет позицию потребителя outbox
+func (r *Repository) SetConsumerOffset(ctx context.Context, name string, position entity.OutboxPosition) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.consumerOffsets[name] = position
+	return nil
+}
+
+// AddNotifications ставит уведомления в очередь; повтор события не создает дубликатов
+func (r *Repository) AddNotifications(ctx context.Context, notifications []*entity.Notification) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, n := range notifications {
+		exists := slices.ContainsFunc(r.notifications, func(existing *entity.Notification) bool {
+			return existing.UserID == n.UserID && existing.Channel == n.Channel && existing.EventKey == n.EventKey
+		})
+		if exists {
+			continue
+		}
+
+		c := copyNotification(n)
+		c.ID = int64(len(r.notifications) + 1)
+		c.Status = entity.NotificationPending
+		c.CreatedAt = now
+		r.notifications = append(r.notifications, c)
+	}
+
+	return nil
+}
+
+// ClaimNotifications забирает до limit уведомлений, время отправки которых наступило, и откладывает
+// их на lease, чтобы параллельные обработчики не отправили их повторно
+func (r *Repository) ClaimNotifications(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.Notification, error) {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	due := make([]*entity.Notification, 0)
+	for _, n := range r.notifications {
+		if n.Status != entity.NotificationPending && n.Status != entity.NotificationRetrying {
+			continue
+		}
+
+		if n.ScheduledAt.After(now) {
+			continue
+		}
+
+		due = append(due, n)
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].ScheduledAt.Before(due[j].ScheduledAt)
+	})
+
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*entity.Notification, 0, len(due))
+	for _, n := range due {
+		n.ScheduledAt = now.Add(lease)
+		claimed = append(claimed, copyNotification(n))
+	}
+
+	return claimed, nil
+}
+
+// UpdateNotification сохраняет результат попытки отправки
+func (r *Repository) UpdateNotification(ctx context.Context, n *entity.Notification) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n.ID < 1 || int(n.ID) > len(r.notifications) {
+		return nil
+	}
+
+	existing := r.notifications[n.ID-1]
+	existing.Status = n.Status
+	existing.Attempts = n.Attempts
+	existing.LastError = n.LastError
+	existing.ScheduledAt = n.ScheduledAt
+	existing.SentAt = copyTime(n.SentAt)
+	return nil
+}
+
+// ListNotifications получает журнал уведомлений от новых к старым, не больше filter.Limit
+func (r *Repository) ListNotifications(ctx context.Context, filter entity.NotificationFilter) ([]*entity.Notification, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	before, err := cursorID(filter.After)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := make([]*entity.Notification, 0)
+	for i := len(r.notifications) - 1; i >= 0 && len(notifications) < filter.Limit; i-- {
+		n := r.notifications[i]
+		if before != 0 && n.ID >= before {
+			continue
+		}
+
+		if filter.UserID != "" && n.UserID != filter.UserID {
+			continue
+		}
+
+		if filter.Status != "" && n.Status != filter.Status {
+			continue
+		}
+
+		notifications = append(notifications, copyNotification(n))
+	}
+
+	return notifications, nil
+}
+
+func copyPreferences(p *entity.NotificationPreferences) *entity.NotificationPreferences {
+	c := *p
+	c.Channels = append([]entity.NotificationChannel(nil), p.Channels...)
+	c.UpdatedAt = copyTime(p.UpdatedAt)
+	if p.QuietHours != nil {
+		quiet := *p.QuietHours
+		c.QuietHours = &quiet
+	}
+
+	return &c
+}
+
+func copyNotification(n *entity.Notification) *entity.Notification {
+	c := *n
+	c.Payload = append([]byte(nil), n.Payload...)
+	c.SentAt = copyTime(n.SentAt)
+	return &c
+}
@@ -0,0 +1,215 @@
+package memory
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"slices"
+	"time"
+)
+
+// AddOutboxEvent записывает событие в outbox; вызывается в транзакции изменения
+func (r *Repository) AddOutboxEvent(ctx context.Context, event *entity.OutboxEvent) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextOutboxID++
+	event.ID = r.nextOutboxID
+	event.TxID = event.ID
+	event.CreatedAt = time.Now()
+	event.NextAttemptAt = event.CreatedAt
+	r.outbox = append(r.outbox, copyOutboxEvent(event))
+	return nil
+}
+
+// ClaimOutboxEvents забирает до limit первых неопубликованных событий по порядку,
+// останавливаясь на первом, срок попытки которого не наступил, и откладывает их на lease
+func (r *Repository) ClaimOutboxEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.OutboxEvent, error) {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make([]*entity.OutboxEvent, 0)
+	for _, e := range r.outbox {
+		if len(events) == limit {
+			break
+		}
+
+		if e.PublishedAt != nil || e.FailedAt != nil {
+			continue
+		}
+
+		if e.NextAttemptAt.After(now) {
+			break
+		}
+
+		e.NextAttemptAt = now.Add(lease)
+		events = append(events, copyOutboxEvent(e))
+	}
+
+	return events, nil
+}
+
+// ReleaseOutboxEvents возвращает забранные, но не опубликованные события в очередь к сроку at
+func (r *Repository) ReleaseOutboxEvents(ctx context.Context, ids []int64, at time.Time) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.outbox {
+		if e.PublishedAt == nil && slices.Contains(ids, e.ID) {
+			e.NextAttemptAt = at
+		}
+	}
+
+	return nil
+}
+
+// MarkOutboxPublished отмечает события опубликованными
+func (r *Repository) MarkOutboxPublished(ctx context.Context, ids []int64, at time.Time) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.outbox {
+		if slices.Contains(ids, e.ID) {
+			published := at
+			e.PublishedAt = &published
+			e.Attempts++
+			e.LastError = ""
+		}
+	}
+
+	return nil
+}
+
+// RecordOutboxFailure сохраняет неудачную попытку публикации: число попыток, ошибку,
+// срок следующей попытки и отметку об исчерпании попыток
+func (r *Repository) RecordOutboxFailure(ctx context.Context, event *entity.OutboxEvent) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.outbox {
+		if e.ID == event.ID {
+			e.Attempts = event.Attempts
+			e.LastError = event.LastError
+			e.NextAttemptAt = event.NextAttemptAt
+			e.FailedAt = copyTime(event.FailedAt)
+			break
+		}
+	}
+
+	return nil
+}
+
+// CountPendingOutboxEvents число событий, ожидающих публикации, без исчерпавших попытки
+func (r *Repository) CountPendingOutboxEvents(ctx context.Context) (int, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for _, e := range r.outbox {
+		if e.PublishedAt == nil && e.FailedAt == nil {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func copyOutboxEvent(e *entity.OutboxEvent) *entity.OutboxEvent {
+	c := *e
+	c.Payload = append([]byte(nil), e.Payload...)
+	c.PublishedAt = copyTime(e.PublishedAt)
+	c.FailedAt = copyTime(e.FailedAt)
+	return &c
+}
+
+// GetCommittedOutboxEvents получает до limit событий после позиции after. Транзакции
+// хранилища в памяти выполняются по одной, поэтому ID совпадает с порядком фиксации; как и
+// любое чтение вне транзакции, ждет завершения текущей, чтобы не вернуть откатываемое событие
+func (r *Repository) GetCommittedOutboxEvents(ctx context.Context, after entity.OutboxPosition, limit int) ([]*entity.OutboxEvent, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := make([]*entity.OutboxEvent, 0)
+	for _, e := range r.outbox {
+		if len(events) == limit {
+			break
+		}
+
+		if after.Before(e.Position()) {
+			events = append(events, copyOutboxEvent(e))
+		}
+	}
+
+	return events, nil
+}
+
+// GetOutboxEventPosition позиция события id в порядке чтения outbox
+func (r *Repository) GetOutboxEventPosition(ctx context.Context, id int64) (entity.OutboxPosition, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, e := range r.outbox {
+		if e.ID == id {
+			return e.Position(), nil
+		}
+	}
+
+	return entity.OutboxPosition{}, entity.ErrOutboxEventNotFound
+}
+
+// GetOutboxEnd позиция последнего записанного события
+func (r *Repository) GetOutboxEnd(ctx context.Context) (entity.OutboxPosition, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return entity.OutboxPosition{TxID: r.nextOutboxID, ID: r.nextOutboxID}, nil
+}
+
+// DeleteOutboxEvents удаляет события, опубликованные раньше publishedBefore и уже прочитанные
+// всеми потребителями outbox; возвращает число удаленных
+func (r *Repository) DeleteOutboxEvents(ctx context.Context, publishedBefore time.Time) (int, error) {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.outbox[:0]
+	for _, e := range r.outbox {
+		if e.PublishedAt == nil || !e.PublishedAt.Before(publishedBefore) || !r.consumedByAll(e) {
+			kept = append(kept, e)
+		}
+	}
+
+	deleted := len(r.outbox) - len(kept)
+	clear(r.outbox[len(kept):])
+	r.outbox = kept
+	return deleted, nil
+}
+
+// consumedByAll проверяет, что все потребители outbox прочитали событие
+func (r *Repository) consumedByAll(e *entity.OutboxEvent) bool {
+	for _, position := range r.consumerOffsets {
+		if position.Before(e.Position()) {
+			return false
+		}
+	}
+
+	return true
+}
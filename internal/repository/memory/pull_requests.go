@@ -0,0 +1,276 @@
+package memory
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"sort"
+	"time"
+)
+
+// CreatePR создает pull request
+func (r *Repository) CreatePR(ctx context.Context, pr *entity.PullRequest) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.prs[pr.ID]; ok {
+		return entity.NewError(entity.PRExists, "PR id already exists")
+	}
+
+	if _, ok := r.users[pr.AuthorID]; !ok {
+		return entity.ErrUserNotFound
+	}
+
+	now := time.Now()
+	createdAt := now
+	if pr.CreatedAt != nil {
+		createdAt = *pr.CreatedAt
+	}
+
+	stored := &pullRequest{
+		id:        pr.ID,
+		name:      pr.Name,
+		authorID:  pr.AuthorID,
+		status:    pr.Status,
+		createdAt: createdAt,
+		mergedAt:  copyTime(pr.MergedAt),
+	}
+
+	for _, reviewerID := range pr.AssignReviewers {
+		if _, ok := r.users[reviewerID]; !ok {
+			return entity.ErrUserNotFound
+		}
+
+		stored.reviewers = append(stored.reviewers, reviewer{userID: reviewerID, assignedAt: now})
+	}
+
+	r.prs[pr.ID] = stored
+	return nil
+}
+
+// MergePR помечает PR как merged
+func (r *Repository) MergePR(ctx context.Context, prID string) (*entity.PullRequest, error) {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pr, ok := r.prs[prID]
+	if !ok {
+		return nil, entity.ErrPRNotFound
+	}
+
+	if pr.status != entity.OPEN {
+		return nil, entity.NewError(entity.InvalidTransition, "PR is no longer %s", entity.OPEN)
+	}
+
+	now := time.Now()
+	pr.status = entity.MERGED
+	pr.mergedAt = &now
+	return pr.toEntity(), nil
+}
+
+// TransitionPR переводит PR из статуса from в статус to и назначает ревьюеров, если они переданы
+func (r *Repository) TransitionPR(ctx context.Context, prID, from, to string, reviewers []string) (*entity.PullRequest, error) {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pr, ok := r.prs[prID]
+	if !ok {
+		return nil, entity.ErrPRNotFound
+	}
+
+	if pr.status != from {
+		return nil, entity.NewError(entity.InvalidTransition, "PR is no longer %s", from)
+	}
+
+	for _, reviewerID := range reviewers {
+		if _, ok := r.users[reviewerID]; !ok {
+			return nil, entity.ErrUserNotFound
+		}
+	}
+
+	now := time.Now()
+	pr.status = to
+	pr.closedAt = nil
+	if to == entity.CLOSED {
+		pr.closedAt = &now
+	}
+
+	for _, reviewerID := range reviewers {
+		if !pr.hasReviewer(reviewerID) {
+			pr.reviewers = append(pr.reviewers, reviewer{userID: reviewerID, assignedAt: now})
+		}
+	}
+
+	return pr.toEntity(), nil
+}
+
+// ReassignReviewer заменяет ревьюера в PR
+func (r *Repository) ReassignReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) (*entity.PullRequest, error) {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pr, ok := r.prs[prID]
+	if !ok {
+		return nil, entity.ErrPRNotFound
+	}
+
+	if pr.status == entity.MERGED {
+		return nil, entity.NewError(entity.PRMerged, "cannot reassign on merged PR")
+	}
+
+	if pr.status != entity.OPEN {
+		return nil, entity.NewError(entity.PRNotOpen, "cannot reassign on %s PR", pr.status)
+	}
+
+	if !pr.hasReviewer(oldReviewerID) {
+		return nil, entity.NewError(entity.NotAssigned, "reviewer is not assigned to this PR")
+	}
+
+	if _, ok := r.users[newReviewerID]; !ok {
+		return nil, entity.ErrUserNotFound
+	}
+
+	now := time.Now()
+	for i := range pr.reviewers {
+		if pr.reviewers[i].userID == oldReviewerID {
+			pr.reviewers[i] = reviewer{userID: newReviewerID, assignedAt: now}
+			break
+		}
+	}
+
+	return pr.toEntity(), nil
+}
+
+// GetPR получает pull request с ревьюерами и решениями
+func (r *Repository) GetPR(ctx context.Context, prID string) (*entity.PullRequest, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pr, ok := r.prs[prID]
+	if !ok {
+		return nil, entity.ErrPRNotFound
+	}
+
+	return pr.toEntity(), nil
+}
+
+// AddReview сохраняет решение ревьюера по pull request
+func (r *Repository) AddReview(ctx context.Context, prID string, review *entity.Review) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pr, ok := r.prs[prID]
+	if !ok {
+		return entity.ErrPRNotFound
+	}
+
+	now := time.Now()
+	review.CreatedAt = &now
+
+	stored := *review
+	stored.CreatedAt = copyTime(review.CreatedAt)
+	pr.reviews = append(pr.reviews, stored)
+	return nil
+}
+
+// PRExists проверяет pull request на существование
+func (r *Repository) PRExists(ctx context.Context, prID string) (bool, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.prs[prID]
+	return ok, nil
+}
+
+// GetReviewersWorkload возвращает количество открытых PR для каждого ревьювера
+func (r *Repository) GetReviewersWorkload(ctx context.Context, userIDs []string) (map[string]int, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	workload := make(map[string]int, len(userIDs))
+	for _, id := range userIDs {
+		workload[id] = 0
+	}
+
+	for _, pr := range r.prs {
+		if pr.status != entity.OPEN {
+			continue
+		}
+
+		for _, rev := range pr.reviewers {
+			if _, ok := workload[rev.userID]; ok {
+				workload[rev.userID]++
+			}
+		}
+	}
+
+	return workload, nil
+}
+
+// GetPRReviewers получает назначенных ревьюеров PR в порядке назначения
+func (r *Repository) GetPRReviewers(ctx context.Context, prID string) ([]*entity.AssignedReviewer, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pr, ok := r.prs[prID]
+	if !ok {
+		return nil, entity.ErrPRNotFound
+	}
+
+	reviewers := make([]*entity.AssignedReviewer, 0, len(pr.reviewers))
+	for _, rv := range pr.reviewers {
+		reviewers = append(reviewers, &entity.AssignedReviewer{
+			UserID:     rv.userID,
+			AssignedAt: rv.assignedAt,
+		})
+	}
+
+	sort.SliceStable(reviewers, func(i, j int) bool {
+		if !reviewers[i].AssignedAt.Equal(reviewers[j].AssignedAt) {
+			return reviewers[i].AssignedAt.Before(reviewers[j].AssignedAt)
+		}
+
+		return reviewers[i].UserID < reviewers[j].UserID
+	})
+
+	return reviewers, nil
+}
+
+// GetPRReassignments получает переназначения ревьюеров PR в хронологическом порядке
+// из истории назначений
+func (r *Repository) GetPRReassignments(ctx context.Context, prID string) ([]*entity.Reassignment, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reassignments := make([]*entity.Reassignment, 0)
+	for _, h := range r.assignmentHistory {
+		if h.PullRequestID == prID && h.ReplacedUserID != "" {
+			reassignments = append(reassignments, &entity.Reassignment{
+				OldReviewerID: h.ReplacedUserID,
+				NewReviewerID: h.UserID,
+				ReassignedAt:  h.CreatedAt,
+			})
+		}
+	}
+
+	return reassignments, nil
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"time"
+)
+
+// AddRoleBinding выдает роль пользователю глобально или в команде binding.TeamName
+func (r *Repository) AddRoleBinding(ctx context.Context, binding *entity.RoleBinding) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.teams[binding.TeamName]; binding.TeamName != "" && !ok {
+		return entity.ErrTeamNotFound
+	}
+
+	for _, b := range r.roleBindings {
+		if b.UserID == binding.UserID && b.Role == binding.Role && b.TeamName == binding.TeamName {
+			return entity.NewError(entity.BindingExists, "role %s is already bound to user %s", binding.Role, binding.UserID)
+		}
+	}
+
+	r.nextBindingID++
+	binding.ID = r.nextBindingID
+	binding.CreatedAt = time.Now()
+	r.roleBindings = append(r.roleBindings, *binding)
+	return nil
+}
+
+// DeleteRoleBinding отзывает привязку роли и возвращает ее
+func (r *Repository) DeleteRoleBinding(ctx context.Context, id int64) (*entity.RoleBinding, error) {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, b := range r.roleBindings {
+		if b.ID == id {
+			r.roleBindings = append(r.roleBindings[:i:i], r.roleBindings[i+1:]...)
+			return &b, nil
+		}
+	}
+
+	return nil, entity.ErrBindingNotFound
+}
+
+// GetRoleBindings получает привязки ролей по фильтру, отсортированные по ID
+func (r *Repository) GetRoleBindings(ctx context.Context, filter entity.RoleBindingFilter) ([]*entity.RoleBinding, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	bindings := make([]*entity.RoleBinding, 0)
+	for _, b := range r.roleBindings {
+		if filter.UserID != "" && b.UserID != filter.UserID {
+			continue
+		}
+
+		if filter.TeamName != "" && b.TeamName != filter.TeamName {
+			continue
+		}
+
+		bindings = append(bindings, &b)
+	}
+
+	return bindings, nil
+}
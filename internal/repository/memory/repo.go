@@ -0,0 +1,262 @@
+package memory
+
+import (
+	"avito_test_task/internal/entity"
+	"sync"
+	"time"
+)
+
+// Repository потокобезопасное хранилище в памяти, повторяющее поведение pg.Repository.
+// Изменения сериализуются через txMu, чтения вне транзакции ждут ее фиксации или отката
+// на txMu; mu защищает состояние от одновременного чтения
+type Repository struct {
+	mu   sync.RWMutex
+	txMu sync.RWMutex
+	state
+
+	// leaderLocks блокировки фоновых задач по имени, аналог advisory-блокировок pg
+	leaderLocks sync.Map
+}
+
+type state struct {
+	teams map[string]*team
+	users map[string]*user
+	prs   map[string]*pullRequest
+
+	absences      map[int64]*entity.Absence
+	nextAbsenceID int64
+
+	membershipChanges []entity.MembershipChange
+	assignmentHistory []entity.AssignmentHistory
+	auditLog          []entity.AuditEntry
+
+	roleBindings  []entity.RoleBinding
+	nextBindingID int64
+
+	apiKeys      map[int64]*entity.APIKey
+	nextAPIKeyID int64
+
+	webhooks         map[int64]*entity.Webhook
+	nextWebhookID    int64
+	deliveries       []*entity.WebhookDelivery
+	nextDeliveryID   int64
+	deadLetters      []*entity.DeadLetter
+	nextDeadLetterID int64
+
+	outbox       []*entity.OutboxEvent
+	nextOutboxID int64
+
+	notificationPrefs map[string]*entity.NotificationPreferences
+	notifications     []*entity.Notification
+	consumerOffsets   map[string]entity.OutboxPosition
+}
+
+type team struct {
+	name      string
+	strategy  entity.ReviewerStrategy
+	settings  *entity.TeamSettings
+	createdAt time.Time
+}
+
+type user struct {
+	id        string
+	name      string
+	teamName  string
+	isActive  bool
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+type reviewer struct {
+	userID      string
+	assignedAt  time.Time
+	remindedAt  *time.Time
+	escalatedAt *time.Time
+}
+
+type pullRequest struct {
+	id        string
+	name      string
+	authorID  string
+	status    string
+	reviewers []reviewer
+	reviews   []entity.Review
+	createdAt time.Time
+	mergedAt  *time.Time
+	closedAt  *time.Time
+}
+
+func New() *Repository {
+	return &Repository{
+		state: state{
+			teams: make(map[string]*team),
+			users: make(map[string]*user),
+			prs:   make(map[string]*pullRequest),
+
+			absences: make(map[int64]*entity.Absence),
+			apiKeys:  make(map[int64]*entity.APIKey),
+			webhooks: make(map[int64]*entity.Webhook),
+
+			notificationPrefs: make(map[string]*entity.NotificationPreferences),
+			consumerOffsets:   make(map[string]entity.OutboxPosition),
+		},
+	}
+}
+
+// clone возвращает глубокую копию состояния для отката транзакции
+func (s *state) clone() state {
+	c := state{
+		teams:         make(map[string]*team, len(s.teams)),
+		users:         make(map[string]*user, len(s.users)),
+		prs:           make(map[string]*pullRequest, len(s.prs)),
+		absences:      make(map[int64]*entity.Absence, len(s.absences)),
+		nextAbsenceID: s.nextAbsenceID,
+
+		membershipChanges: append([]entity.MembershipChange(nil), s.membershipChanges...),
+		assignmentHistory: append([]entity.AssignmentHistory(nil), s.assignmentHistory...),
+		auditLog:          append([]entity.AuditEntry(nil), s.auditLog...),
+		roleBindings:      append([]entity.RoleBinding(nil), s.roleBindings...),
+		nextBindingID:     s.nextBindingID,
+		apiKeys:           make(map[int64]*entity.APIKey, len(s.apiKeys)),
+		nextAPIKeyID:      s.nextAPIKeyID,
+		webhooks:          make(map[int64]*entity.Webhook, len(s.webhooks)),
+		nextWebhookID:     s.nextWebhookID,
+		deliveries:        make([]*entity.WebhookDelivery, 0, len(s.deliveries)),
+		nextDeliveryID:    s.nextDeliveryID,
+		deadLetters:       make([]*entity.DeadLetter, 0, len(s.deadLetters)),
+		nextDeadLetterID:  s.nextDeadLetterID,
+		nextOutboxID:      s.nextOutboxID,
+	}
+
+	c.notificationPrefs = make(map[string]*entity.NotificationPreferences, len(s.notificationPrefs))
+	for id, p := range s.notificationPrefs {
+		c.notificationPrefs[id] = copyPreferences(p)
+	}
+
+	c.notifications = make([]*entity.Notification, 0, len(s.notifications))
+	for _, n := range s.notifications {
+		c.notifications = append(c.notifications, copyNotification(n))
+	}
+
+	c.consumerOffsets = make(map[string]entity.OutboxPosition, len(s.consumerOffsets))
+	for name, position := range s.consumerOffsets {
+		c.consumerOffsets[name] = position
+	}
+
+	c.outbox = make([]*entity.OutboxEvent, 0, len(s.outbox))
+	for _, e := range s.outbox {
+		c.outbox = append(c.outbox, copyOutboxEvent(e))
+	}
+
+	for id, hook := range s.webhooks {
+		c.webhooks[id] = copyWebhook(hook)
+	}
+
+	for _, d := range s.deliveries {
+		c.deliveries = append(c.deliveries, copyDelivery(d))
+	}
+
+	for _, dl := range s.deadLetters {
+		dc := *dl
+		c.deadLetters = append(c.deadLetters, &dc)
+	}
+
+	for id, k := range s.apiKeys {
+		c.apiKeys[id] = copyAPIKey(k)
+	}
+
+	for id, a := range s.absences {
+		c.absences[id] = copyAbsence(a)
+	}
+
+	for name, t := range s.teams {
+		tc := *t
+		if t.settings != nil {
+			settings := *t.settings
+			settings.UpdatedAt = copyTime(t.settings.UpdatedAt)
+			tc.settings = &settings
+		}
+		c.teams[name] = &tc
+	}
+
+	for id, u := range s.users {
+		uc := *u
+		c.users[id] = &uc
+	}
+
+	for id, pr := range s.prs {
+		pc := *pr
+		pc.reviewers = append([]reviewer(nil), pr.reviewers...)
+		pc.reviews = append([]entity.Review(nil), pr.reviews...)
+		pc.mergedAt = copyTime(pr.mergedAt)
+		pc.closedAt = copyTime(pr.closedAt)
+		c.prs[id] = &pc
+	}
+
+	return c
+}
+
+func (u *user) toEntity() *entity.User {
+	createdAt, updatedAt := u.createdAt, u.updatedAt
+	return &entity.User{
+		UserID:    u.id,
+		Name:      u.name,
+		TeamName:  u.teamName,
+		IsActive:  u.isActive,
+		CreatedAt: &createdAt,
+		UpdatedAt: &updatedAt,
+	}
+}
+
+func (pr *pullRequest) toEntity() *entity.PullRequest {
+	createdAt := pr.createdAt
+	result := &entity.PullRequest{
+		ID:              pr.id,
+		Name:            pr.name,
+		AuthorID:        pr.authorID,
+		Status:          pr.status,
+		AssignReviewers: make([]string, 0, len(pr.reviewers)),
+		Reviews:         make([]*entity.Review, 0, len(pr.reviews)),
+		CreatedAt:       &createdAt,
+		MergedAt:        copyTime(pr.mergedAt),
+		ClosedAt:        copyTime(pr.closedAt),
+	}
+
+	for _, r := range pr.reviewers {
+		result.AssignReviewers = append(result.AssignReviewers, r.userID)
+	}
+
+	for _, r := range pr.reviews {
+		review := r
+		review.CreatedAt = copyTime(r.CreatedAt)
+		result.Reviews = append(result.Reviews, &review)
+	}
+
+	return result
+}
+
+func (pr *pullRequest) hasReviewer(userID string) bool {
+	for _, r := range pr.reviewers {
+		if r.userID == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func copyAbsence(a *entity.Absence) *entity.Absence {
+	c := *a
+	c.AppliedAt = copyTime(a.AppliedAt)
+	c.ReleasedAt = copyTime(a.ReleasedAt)
+	return &c
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	c := *t
+	return &c
+}
@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"sort"
+	"time"
+)
+
+// GetStaleReviews получает до limit назначений на открытые PR, по которым пора напомнить
+// ревьюеру или заменить его по SLA команды автора, от самых долгих простоев
+func (r *Repository) GetStaleReviews(ctx context.Context, now time.Time, limit int) ([]*entity.StaleReview, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reviews := make([]*entity.StaleReview, 0)
+	for _, pr := range r.prs {
+		if pr.status != entity.OPEN {
+			continue
+		}
+
+		author, ok := r.users[pr.authorID]
+		if !ok || author.teamName == "" {
+			continue
+		}
+
+		t, ok := r.teams[author.teamName]
+		if !ok {
+			continue
+		}
+
+		sla, escalation := entity.DefaultReviewSLAMinutes, entity.DefaultEscalationMinutes
+		if t.settings != nil {
+			sla, escalation = t.settings.ReviewSLAMinutes, t.settings.EscalationMinutes
+		}
+
+		for _, rv := range pr.reviewers {
+			idleSince, decided := rv.assignedAt, false
+			for _, review := range pr.reviews {
+				if review.ReviewerID != rv.userID {
+					continue
+				}
+
+				if review.Decision != entity.Commented {
+					decided = true
+				}
+
+				if review.CreatedAt != nil && review.CreatedAt.After(idleSince) {
+					idleSince = *review.CreatedAt
+				}
+			}
+
+			if decided {
+				continue
+			}
+
+			s := &entity.StaleReview{
+				PullRequestID: pr.id,
+				ReviewerID:    rv.userID,
+				TeamName:      t.name,
+				IdleSince:     idleSince,
+				ReviewSLA:     time.Duration(sla) * time.Minute,
+				Escalation:    time.Duration(escalation) * time.Minute,
+				RemindedAt:    copyTime(rv.remindedAt),
+				EscalatedAt:   copyTime(rv.escalatedAt),
+			}
+
+			if s.ReminderDue(now) || s.EscalationDue(now) {
+				reviews = append(reviews, s)
+			}
+		}
+	}
+
+	sort.Slice(reviews, func(i, j int) bool {
+		if !reviews[i].IdleSince.Equal(reviews[j].IdleSince) {
+			return reviews[i].IdleSince.Before(reviews[j].IdleSince)
+		}
+
+		if reviews[i].PullRequestID != reviews[j].PullRequestID {
+			return reviews[i].PullRequestID < reviews[j].PullRequestID
+		}
+
+		return reviews[i].ReviewerID < reviews[j].ReviewerID
+	})
+
+	if len(reviews) > limit {
+		reviews = reviews[:limit]
+	}
+
+	return reviews, nil
+}
+
+// MarkReviewReminded сохраняет время напоминания ревьюеру
+func (r *Repository) MarkReviewReminded(ctx context.Context, prID, userID string, at time.Time) error {
+	return r.markReview(ctx, prID, userID, func(rv *reviewer) {
+		rv.remindedAt = &at
+	})
+}
+
+// MarkReviewEscalated сохраняет время попытки заменить ревьюера
+func (r *Repository) MarkReviewEscalated(ctx context.Context, prID, userID string, at time.Time) error {
+	return r.markReview(ctx, prID, userID, func(rv *reviewer) {
+		rv.escalatedAt = &at
+	})
+}
+
+func (r *Repository) markReview(ctx context.Context, prID, userID string, mark func(rv *reviewer)) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pr, ok := r.prs[prID]
+	if !ok {
+		return nil
+	}
+
+	for i := range pr.reviewers {
+		if pr.reviewers[i].userID == userID {
+			mark(&pr.reviewers[i])
+		}
+	}
+
+	return nil
+}
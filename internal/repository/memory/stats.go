@@ -0,0 +1,213 @@
+package memory
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"sort"
+)
+
+// GetReviewerStats считает статистику ревью по пользователям; назначения берутся из истории
+// назначений, включая снятые, и вместе с открытыми и смерженными ревью учитываются
+// по дате создания PR, переназначения по дате переназначения
+func (r *Repository) GetReviewerStats(ctx context.Context, filter entity.StatsFilter) ([]*entity.ReviewerStats, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	byUser := make(map[string]*entity.ReviewerStats)
+	durations := make(map[string][]float64)
+	stats := make([]*entity.ReviewerStats, 0)
+	for _, u := range r.users {
+		if filter.TeamName != "" && u.teamName != filter.TeamName {
+			continue
+		}
+
+		s := &entity.ReviewerStats{
+			UserID:   u.id,
+			Username: u.name,
+			TeamName: u.teamName,
+			IsActive: u.isActive,
+		}
+		byUser[u.id] = s
+		stats = append(stats, s)
+	}
+
+	for _, pr := range r.prs {
+		if !filter.Contains(pr.createdAt) {
+			continue
+		}
+
+		for _, rev := range pr.reviewers {
+			s, ok := byUser[rev.userID]
+			if !ok {
+				continue
+			}
+
+			switch pr.status {
+			case entity.OPEN:
+				s.Open++
+			case entity.MERGED:
+				s.MergedReviewed++
+				durations[rev.userID] = append(durations[rev.userID], pr.mergedAt.Sub(pr.createdAt).Seconds())
+			}
+		}
+	}
+
+	for userID, prs := range r.assignedReviews(filter) {
+		if s, ok := byUser[userID]; ok {
+			s.Assigned = len(prs)
+		}
+	}
+
+	for _, h := range r.assignmentHistory {
+		if h.ReplacedUserID == "" || !filter.Contains(h.CreatedAt) {
+			continue
+		}
+
+		if s, ok := byUser[h.UserID]; ok {
+			s.ReassignedIn++
+		}
+
+		if s, ok := byUser[h.ReplacedUserID]; ok {
+			s.ReassignedOut++
+		}
+	}
+
+	for userID, d := range durations {
+		byUser[userID].MedianTimeToMergeSeconds = median(d)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TeamName != stats[j].TeamName {
+			return stats[i].TeamName < stats[j].TeamName
+		}
+
+		return stats[i].UserID < stats[j].UserID
+	})
+
+	return stats, nil
+}
+
+// GetTeamStats считает статистику по командам; PR относится к команде автора
+func (r *Repository) GetTeamStats(ctx context.Context, filter entity.StatsFilter) ([]*entity.TeamStats, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	byTeam := make(map[string]*entity.TeamStats)
+	durations := make(map[string][]float64)
+	stats := make([]*entity.TeamStats, 0)
+	for name := range r.teams {
+		if filter.TeamName != "" && name != filter.TeamName {
+			continue
+		}
+
+		s := &entity.TeamStats{TeamName: name}
+		byTeam[name] = s
+		stats = append(stats, s)
+	}
+
+	teamOf := func(userID string) *entity.TeamStats {
+		if u, ok := r.users[userID]; ok {
+			return byTeam[u.teamName]
+		}
+
+		return nil
+	}
+
+	for _, u := range r.users {
+		if s, ok := byTeam[u.teamName]; ok {
+			s.Members++
+			if u.isActive {
+				s.ActiveMembers++
+			}
+		}
+	}
+
+	for _, pr := range r.prs {
+		if !filter.Contains(pr.createdAt) {
+			continue
+		}
+
+		if s := teamOf(pr.authorID); s != nil {
+			s.PRsCreated++
+			switch pr.status {
+			case entity.OPEN:
+				s.PRsOpen++
+			case entity.MERGED:
+				s.PRsMerged++
+				durations[s.TeamName] = append(durations[s.TeamName], pr.mergedAt.Sub(pr.createdAt).Seconds())
+			}
+		}
+	}
+
+	for userID, prs := range r.assignedReviews(filter) {
+		if s := teamOf(userID); s != nil {
+			s.ReviewsAssigned += len(prs)
+		}
+	}
+
+	for _, h := range r.assignmentHistory {
+		if h.ReplacedUserID == "" || !filter.Contains(h.CreatedAt) {
+			continue
+		}
+
+		if s := teamOf(h.ReplacedUserID); s != nil {
+			s.Reassignments++
+		}
+	}
+
+	for teamName, d := range durations {
+		byTeam[teamName].MedianTimeToMergeSeconds = median(d)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].TeamName < stats[j].TeamName
+	})
+
+	return stats, nil
+}
+
+// assignedReviews PR из фильтра, на которые пользователь когда-либо назначался, по истории
+// назначений; вызывается под r.mu
+func (r *Repository) assignedReviews(filter entity.StatsFilter) map[string]map[string]struct{} {
+	assigned := make(map[string]map[string]struct{})
+	for _, h := range r.assignmentHistory {
+		if h.Event != entity.ReviewerAssigned {
+			continue
+		}
+
+		pr, ok := r.prs[h.PullRequestID]
+		if !ok || !filter.Contains(pr.createdAt) {
+			continue
+		}
+
+		if assigned[h.UserID] == nil {
+			assigned[h.UserID] = make(map[string]struct{})
+		}
+
+		assigned[h.UserID][h.PullRequestID] = struct{}{}
+	}
+
+	return assigned
+}
+
+func median(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	m := sorted[mid]
+	if len(sorted)%2 == 0 {
+		m = (sorted[mid-1] + sorted[mid]) / 2
+	}
+
+	return &m
+}
@@ -0,0 +1,363 @@
+package memory
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"sort"
+	"time"
+)
+
+// CreateTeam создает команду и добавляет в нее участников; существующие пользователи без команды
+// сохраняют свою активность
+func (r *Repository) CreateTeam(ctx context.Context, t *entity.Team) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.teams[t.Name]; ok {
+		return entity.NewError(entity.TeamExists, "team with name %s already exists", t.Name)
+	}
+
+	now := time.Now()
+	r.teams[t.Name] = &team{
+		name:      t.Name,
+		strategy:  t.ReviewerStrategy,
+		createdAt: now,
+	}
+
+	for _, member := range t.Members {
+		if u, ok := r.users[member.UserID]; ok {
+			u.name = member.Name
+			u.teamName = t.Name
+			u.updatedAt = now
+			continue
+		}
+
+		r.users[member.UserID] = &user{
+			id:        member.UserID,
+			name:      member.Name,
+			teamName:  t.Name,
+			isActive:  member.IsActive,
+			createdAt: now,
+			updatedAt: now,
+		}
+	}
+
+	return nil
+}
+
+// GetTeam получает команду с участниками
+func (r *Repository) GetTeam(ctx context.Context, teamName string) (*entity.Team, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.teams[teamName]
+	if !ok {
+		return nil, entity.ErrTeamNotFound
+	}
+
+	createdAt := t.createdAt
+	result := &entity.Team{
+		Name:             t.name,
+		ReviewerStrategy: t.strategy,
+		CreatedAt:        &createdAt,
+	}
+
+	for _, u := range r.teamMembers(teamName) {
+		result.Members = append(result.Members, &entity.TeamMember{
+			UserID:   u.id,
+			Name:     u.name,
+			IsActive: u.isActive,
+		})
+	}
+
+	return result, nil
+}
+
+// TeamExists проверяет существует ли команда с определенным именем
+func (r *Repository) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.teams[teamName]
+	return ok, nil
+}
+
+// GetTeamReviewerStrategy получает стратегию выбора ревьюеров команды
+func (r *Repository) GetTeamReviewerStrategy(ctx context.Context, teamName string) (entity.ReviewerStrategy, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.teams[teamName]
+	if !ok {
+		return "", entity.ErrTeamNotFound
+	}
+
+	return t.strategy, nil
+}
+
+// SetTeamReviewerStrategy обновляет стратегию выбора ревьюеров команды
+func (r *Repository) SetTeamReviewerStrategy(ctx context.Context, teamName string, strategy entity.ReviewerStrategy) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.teams[teamName]
+	if !ok {
+		return entity.ErrTeamNotFound
+	}
+
+	t.strategy = strategy
+	return nil
+}
+
+// GetTeamSettings получает политику ревью команды, для команд без настроек возвращает значения по умолчанию
+func (r *Repository) GetTeamSettings(ctx context.Context, teamName string) (*entity.TeamSettings, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.teams[teamName]
+	if !ok {
+		return nil, entity.ErrTeamNotFound
+	}
+
+	if t.settings == nil {
+		return &entity.TeamSettings{
+			TeamName:          teamName,
+			MinReviewers:      entity.DefaultMinReviewers,
+			MaxReviewers:      entity.DefaultMaxReviewers,
+			RequiredApprovals: entity.DefaultRequiredApprovals,
+			ReviewSLAMinutes:  entity.DefaultReviewSLAMinutes,
+			EscalationMinutes: entity.DefaultEscalationMinutes,
+		}, nil
+	}
+
+	settings := *t.settings
+	settings.UpdatedAt = copyTime(t.settings.UpdatedAt)
+	return &settings, nil
+}
+
+// UpsertTeamSettings сохраняет политику ревью команды
+func (r *Repository) UpsertTeamSettings(ctx context.Context, settings *entity.TeamSettings) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.teams[settings.TeamName]
+	if !ok {
+		return entity.ErrTeamNotFound
+	}
+
+	now := time.Now()
+	settings.UpdatedAt = &now
+
+	stored := *settings
+	stored.UpdatedAt = copyTime(settings.UpdatedAt)
+	t.settings = &stored
+	return nil
+}
+
+// teamMembers возвращает участников команды, отсортированных по имени; вызывается под блокировкой
+func (r *Repository) teamMembers(teamName string) []*user {
+	members := make([]*user, 0)
+	for _, u := range r.users {
+		if u.teamName == teamName {
+			members = append(members, u)
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].name < members[j].name
+	})
+
+	return members
+}
+
+// AddTeamMember добавляет пользователя в команду; существующий пользователь без команды
+// привязывается к ней с обновлением имени, его активность не меняется
+func (r *Repository) AddTeamMember(ctx context.Context, teamName string, member *entity.TeamMember) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.teams[teamName]; !ok {
+		return entity.ErrTeamNotFound
+	}
+
+	now := time.Now()
+	if u, ok := r.users[member.UserID]; ok {
+		if u.teamName != "" {
+			return entity.NewError(entity.MemberExists, "user %s already belongs to a team", member.UserID)
+		}
+
+		u.name = member.Name
+		u.teamName = teamName
+		u.updatedAt = now
+		return nil
+	}
+
+	r.users[member.UserID] = &user{
+		id:        member.UserID,
+		name:      member.Name,
+		teamName:  teamName,
+		isActive:  member.IsActive,
+		createdAt: now,
+		updatedAt: now,
+	}
+
+	return nil
+}
+
+// SetUserTeam переводит пользователя в команду teamName, при пустом teamName убирает из команды
+func (r *Repository) SetUserTeam(ctx context.Context, userID, teamName string) (*entity.User, error) {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.teams[teamName]; teamName != "" && !ok {
+		return nil, entity.ErrTeamNotFound
+	}
+
+	u, ok := r.users[userID]
+	if !ok {
+		return nil, entity.ErrUserNotFound
+	}
+
+	u.teamName = teamName
+	u.updatedAt = time.Now()
+	return u.toEntity(), nil
+}
+
+// RenameTeam переименовывает команду, ее записи в журнале состава и привязки ролей
+func (r *Repository) RenameTeam(ctx context.Context, teamName, newTeamName string) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.teams[teamName]
+	if !ok {
+		return entity.ErrTeamNotFound
+	}
+
+	if _, ok := r.teams[newTeamName]; ok {
+		return entity.NewError(entity.TeamExists, "team with name %s already exists", newTeamName)
+	}
+
+	delete(r.teams, teamName)
+	t.name = newTeamName
+	if t.settings != nil {
+		t.settings.TeamName = newTeamName
+	}
+	r.teams[newTeamName] = t
+
+	for _, u := range r.users {
+		if u.teamName == teamName {
+			u.teamName = newTeamName
+		}
+	}
+
+	for i := range r.membershipChanges {
+		change := &r.membershipChanges[i]
+		if change.FromTeam == teamName {
+			change.FromTeam = newTeamName
+		}
+
+		if change.ToTeam == teamName {
+			change.ToTeam = newTeamName
+		}
+	}
+
+	for i := range r.roleBindings {
+		if r.roleBindings[i].TeamName == teamName {
+			r.roleBindings[i].TeamName = newTeamName
+		}
+	}
+
+	for _, hook := range r.webhooks {
+		if hook.TeamName == teamName {
+			hook.TeamName = newTeamName
+		}
+	}
+
+	return nil
+}
+
+// DeleteTeam удаляет команду без участников вместе с ее настройками, привязками ролей и вебхуками
+func (r *Repository) DeleteTeam(ctx context.Context, teamName string) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.teams[teamName]; !ok {
+		return entity.ErrTeamNotFound
+	}
+
+	if len(r.teamMembers(teamName)) > 0 {
+		return entity.NewError(entity.TeamNotEmpty, "team %s still has members", teamName)
+	}
+
+	delete(r.teams, teamName)
+
+	bindings := r.roleBindings[:0]
+	for _, b := range r.roleBindings {
+		if b.TeamName != teamName {
+			bindings = append(bindings, b)
+		}
+	}
+
+	r.roleBindings = bindings
+
+	for id, hook := range r.webhooks {
+		if hook.TeamName == teamName {
+			r.deleteWebhook(id)
+		}
+	}
+
+	return nil
+}
+
+// AddMembershipChange записывает изменение состава команды в журнал
+func (r *Repository) AddMembershipChange(ctx context.Context, change *entity.MembershipChange) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	change.ID = int64(len(r.membershipChanges) + 1)
+	change.ChangedAt = time.Now()
+	r.membershipChanges = append(r.membershipChanges, *change)
+	return nil
+}
+
+// GetMembershipChanges получает журнал изменений состава команды, новые записи первыми
+func (r *Repository) GetMembershipChanges(ctx context.Context, teamName string) ([]*entity.MembershipChange, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	changes := make([]*entity.MembershipChange, 0)
+	for i := len(r.membershipChanges) - 1; i >= 0; i-- {
+		change := r.membershipChanges[i]
+		if change.FromTeam == teamName || change.ToTeam == teamName {
+			changes = append(changes, &change)
+		}
+	}
+
+	return changes, nil
+}
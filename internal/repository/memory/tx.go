@@ -0,0 +1,52 @@
+package memory
+
+import "context"
+
+type txKey struct{}
+
+// WithinTx выполняет fn атомарно: изменения других запросов ждут ее завершения,
+// при ошибке состояние откатывается к снимку на момент начала
+func (r *Repository) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
+
+	r.txMu.Lock()
+	defer r.txMu.Unlock()
+
+	r.mu.RLock()
+	snapshot := r.state.clone()
+	r.mu.RUnlock()
+
+	if err := fn(context.WithValue(ctx, txKey{}, true)); err != nil {
+		r.mu.Lock()
+		r.state = snapshot
+		r.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// write захватывает блокировку изменений, если вызов не внутри WithinTx;
+// возвращает функцию освобождения
+func (r *Repository) write(ctx context.Context) func() {
+	if ctx.Value(txKey{}) != nil {
+		return func() {}
+	}
+
+	r.txMu.Lock()
+	return r.txMu.Unlock
+}
+
+// read ждет завершения текущей транзакции, если вызов не внутри WithinTx: чтение вне
+// транзакции видит только зафиксированное состояние, как в pg. Чтения друг друга не блокируют;
+// возвращает функцию освобождения
+func (r *Repository) read(ctx context.Context) func() {
+	if ctx.Value(txKey{}) != nil {
+		return func() {}
+	}
+
+	r.txMu.RLock()
+	return r.txMu.RUnlock
+}
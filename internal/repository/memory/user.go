@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"sort"
+	"time"
+)
+
+// SetIsActive обновляет активность пользователя
+func (r *Repository) SetIsActive(ctx context.Context, userID string, isActive bool) (*entity.User, error) {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[userID]
+	if !ok {
+		return nil, entity.ErrUserNotFound
+	}
+
+	u.isActive = isActive
+	u.updatedAt = time.Now()
+	return u.toEntity(), nil
+}
+
+// GetUser получает пользователя с именем команды
+func (r *Repository) GetUser(ctx context.Context, userID string) (*entity.User, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.users[userID]
+	if !ok {
+		return nil, entity.ErrUserNotFound
+	}
+
+	return u.toEntity(), nil
+}
+
+// GetActiveCandidates получает активных пользователей для назначения ревьюверов,
+// исключая тех, у кого сейчас идет отсутствие
+func (r *Repository) GetActiveCandidates(ctx context.Context, teamName string, excludeUserIDs []string) ([]*entity.User, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	excluded := make(map[string]bool, len(excludeUserIDs))
+	for _, id := range excludeUserIDs {
+		excluded[id] = true
+	}
+
+	var users []*entity.User
+	for _, u := range r.teamMembers(teamName) {
+		if u.isActive && !excluded[u.id] && !r.isAway(u.id, now) {
+			users = append(users, u.toEntity())
+		}
+	}
+
+	return users, nil
+}
+
+// GetActiveUsers получает активных и не отсутствующих сейчас пользователей всех команд
+func (r *Repository) GetActiveUsers(ctx context.Context, excludeUserIDs []string) ([]*entity.User, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	excluded := make(map[string]bool, len(excludeUserIDs))
+	for _, id := range excludeUserIDs {
+		excluded[id] = true
+	}
+
+	var users []*entity.User
+	for _, u := range r.users {
+		if u.isActive && !excluded[u.id] && !r.isAway(u.id, now) {
+			users = append(users, u.toEntity())
+		}
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].UserID < users[j].UserID
+	})
+
+	return users, nil
+}
+
+// GetReview получает PR, где пользователь назначен ревьюером
+func (r *Repository) GetReview(ctx context.Context, userID string) ([]*entity.PullRequestShort, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := make([]*pullRequest, 0)
+	for _, pr := range r.prs {
+		if pr.hasReviewer(userID) {
+			matched = append(matched, pr)
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].createdAt.After(matched[j].createdAt)
+	})
+
+	prs := make([]*entity.PullRequestShort, 0, len(matched))
+	for _, pr := range matched {
+		prs = append(prs, &entity.PullRequestShort{
+			ID:       pr.id,
+			Name:     pr.name,
+			AuthorID: pr.authorID,
+			Status:   pr.status,
+		})
+	}
+
+	return prs, nil
+}
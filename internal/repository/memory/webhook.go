@@ -0,0 +1,307 @@
+package memory
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// CreateWebhook сохраняет подписку на события
+func (r *Repository) CreateWebhook(ctx context.Context, hook *entity.Webhook) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if hook.TeamName != "" {
+		if _, ok := r.teams[hook.TeamName]; !ok {
+			return entity.ErrTeamNotFound
+		}
+	}
+
+	r.nextWebhookID++
+	hook.ID = r.nextWebhookID
+	hook.CreatedAt = time.Now()
+	r.webhooks[hook.ID] = copyWebhook(hook)
+	return nil
+}
+
+// GetWebhooks получает подписки команды или все подписки при пустом teamName
+func (r *Repository) GetWebhooks(ctx context.Context, teamName string) ([]*entity.Webhook, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.findWebhooks(func(hook *entity.Webhook) bool {
+		return teamName == "" || hook.TeamName == teamName
+	}), nil
+}
+
+// GetSubscribedWebhooks получает подписки на событие eventType команды teamName, включая общие
+func (r *Repository) GetSubscribedWebhooks(ctx context.Context, teamName string, eventType entity.EventType) ([]*entity.Webhook, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.findWebhooks(func(hook *entity.Webhook) bool {
+		return (hook.TeamName == "" || hook.TeamName == teamName) && slices.Contains(hook.Events, eventType)
+	}), nil
+}
+
+func (r *Repository) findWebhooks(match func(hook *entity.Webhook) bool) []*entity.Webhook {
+	hooks := make([]*entity.Webhook, 0)
+	for _, hook := range r.webhooks {
+		if match(hook) {
+			hooks = append(hooks, copyWebhook(hook))
+		}
+	}
+
+	sort.Slice(hooks, func(i, j int) bool {
+		return hooks[i].ID < hooks[j].ID
+	})
+
+	return hooks
+}
+
+// DeleteWebhook удаляет подписку вместе с журналом ее доставок
+func (r *Repository) DeleteWebhook(ctx context.Context, id int64) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.webhooks[id]; !ok {
+		return entity.ErrWebhookNotFound
+	}
+
+	r.deleteWebhook(id)
+	return nil
+}
+
+// deleteWebhook удаляет подписку и ее доставки, у dead-letter записей обнуляет DeliveryID;
+// вызывается под r.mu
+func (r *Repository) deleteWebhook(id int64) {
+	delete(r.webhooks, id)
+
+	r.deliveries = slices.DeleteFunc(r.deliveries, func(d *entity.WebhookDelivery) bool {
+		return d.WebhookID == id
+	})
+
+	for _, dl := range r.deadLetters {
+		if dl.WebhookID == id {
+			dl.DeliveryID = nil
+		}
+	}
+}
+
+// AddWebhookDeliveries ставит доставки в очередь
+func (r *Repository) AddWebhookDeliveries(ctx context.Context, deliveries []*entity.WebhookDelivery) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, d := range deliveries {
+		exists := slices.ContainsFunc(r.deliveries, func(existing *entity.WebhookDelivery) bool {
+			return existing.WebhookID == d.WebhookID && existing.EventID == d.EventID
+		})
+		if exists {
+			continue
+		}
+
+		r.nextDeliveryID++
+		c := copyDelivery(d)
+		c.ID = r.nextDeliveryID
+		c.Status = entity.DeliveryPending
+		c.NextAttemptAt = &now
+		c.CreatedAt = now
+		r.deliveries = append(r.deliveries, c)
+	}
+
+	return nil
+}
+
+// ClaimWebhookDeliveries забирает до limit доставок, срок попытки которых наступил, и откладывает
+// их на lease, чтобы параллельные обработчики не отправили их повторно
+func (r *Repository) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error) {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	due := make([]*entity.WebhookDelivery, 0)
+	for _, d := range r.deliveries {
+		if d.Status != entity.DeliveryPending && d.Status != entity.DeliveryRetrying {
+			continue
+		}
+
+		if d.NextAttemptAt == nil || d.NextAttemptAt.After(now) {
+			continue
+		}
+
+		due = append(due, d)
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].NextAttemptAt.Before(*due[j].NextAttemptAt)
+	})
+
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	leased := now.Add(lease)
+	claimed := make([]*entity.WebhookDelivery, 0, len(due))
+	for _, d := range due {
+		d.NextAttemptAt = &leased
+
+		c := copyDelivery(d)
+		hook := r.webhooks[d.WebhookID]
+		c.URL, c.Secret = hook.URL, hook.Secret
+		claimed = append(claimed, c)
+	}
+
+	sort.Slice(claimed, func(i, j int) bool {
+		return claimed[i].ID < claimed[j].ID
+	})
+
+	return claimed, nil
+}
+
+// UpdateWebhookDelivery сохраняет результат попытки доставки
+func (r *Repository) UpdateWebhookDelivery(ctx context.Context, d *entity.WebhookDelivery) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.deliveries {
+		if existing.ID == d.ID {
+			existing.Status = d.Status
+			existing.Attempts = d.Attempts
+			existing.NextAttemptAt = copyTime(d.NextAttemptAt)
+			existing.LastError = d.LastError
+			existing.ResponseStatus = d.ResponseStatus
+			existing.DeliveredAt = copyTime(d.DeliveredAt)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// AddDeadLetter переносит доставку, исчерпавшую попытки, в dead-letter таблицу
+func (r *Repository) AddDeadLetter(ctx context.Context, dl *entity.DeadLetter) error {
+	defer r.write(ctx)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextDeadLetterID++
+	dl.ID = r.nextDeadLetterID
+	dl.FailedAt = time.Now()
+	c := *dl
+	if dl.DeliveryID != nil {
+		id := *dl.DeliveryID
+		c.DeliveryID = &id
+	}
+
+	r.deadLetters = append(r.deadLetters, &c)
+	return nil
+}
+
+// ListWebhookDeliveries получает журнал доставок от новых к старым, не больше filter.Limit
+func (r *Repository) ListWebhookDeliveries(ctx context.Context, filter entity.DeliveryFilter) ([]*entity.WebhookDelivery, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	before, err := cursorID(filter.After)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*entity.WebhookDelivery, 0)
+	for i := len(r.deliveries) - 1; i >= 0 && len(deliveries) < filter.Limit; i-- {
+		d := r.deliveries[i]
+		if before != 0 && d.ID >= before {
+			continue
+		}
+
+		if filter.WebhookID != 0 && d.WebhookID != filter.WebhookID {
+			continue
+		}
+
+		if filter.Status != "" && d.Status != filter.Status {
+			continue
+		}
+
+		deliveries = append(deliveries, copyDelivery(d))
+	}
+
+	return deliveries, nil
+}
+
+// ListDeadLetters получает dead-letter записи от новых к старым, не больше filter.Limit
+func (r *Repository) ListDeadLetters(ctx context.Context, filter entity.DeliveryFilter) ([]*entity.DeadLetter, error) {
+	defer r.read(ctx)()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	before, err := cursorID(filter.After)
+	if err != nil {
+		return nil, err
+	}
+
+	letters := make([]*entity.DeadLetter, 0)
+	for i := len(r.deadLetters) - 1; i >= 0 && len(letters) < filter.Limit; i-- {
+		dl := *r.deadLetters[i]
+		if before != 0 && dl.ID >= before {
+			continue
+		}
+
+		if filter.WebhookID != 0 && dl.WebhookID != filter.WebhookID {
+			continue
+		}
+
+		letters = append(letters, &dl)
+	}
+
+	return letters, nil
+}
+
+// cursorID ID из курсора списка, упорядоченного по числовому ID; 0 — первая страница
+func cursorID(after *entity.Cursor) (int64, error) {
+	if after == nil {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(after.ID, 10, 64)
+	if err != nil {
+		return 0, entity.NewError(entity.InvalidRequest, "invalid cursor")
+	}
+
+	return id, nil
+}
+
+func copyWebhook(hook *entity.Webhook) *entity.Webhook {
+	c := *hook
+	c.Events = append([]entity.EventType(nil), hook.Events...)
+	return &c
+}
+
+func copyDelivery(d *entity.WebhookDelivery) *entity.WebhookDelivery {
+	c := *d
+	c.Payload = append([]byte(nil), d.Payload...)
+	c.NextAttemptAt = copyTime(d.NextAttemptAt)
+	c.DeliveredAt = copyTime(d.DeliveredAt)
+	return &c
+}
@@ -0,0 +1,177 @@
+package pg
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"log/slog"
+	"time"
+)
+
+const absenceColumns = `id, user_id, starts_at, ends_at, reason, deactivated, applied_at, released_at, created_at`
+
+// CreateAbsence добавляет период отсутствия пользователя
+func (r *Repository) CreateAbsence(ctx context.Context, absence *entity.Absence) error {
+	err := r.db(ctx).QueryRow(ctx, `
+		INSERT INTO user_absences (user_id, starts_at, ends_at, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+		`,
+		absence.UserID,
+		absence.StartsAt,
+		absence.EndsAt,
+		absence.Reason,
+	).Scan(&absence.ID, &absence.CreatedAt)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error inserting absence: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// GetAbsence получает период отсутствия по идентификатору
+func (r *Repository) GetAbsence(ctx context.Context, id int64) (*entity.Absence, error) {
+	row := r.db(ctx).QueryRow(ctx, `SELECT `+absenceColumns+` FROM user_absences WHERE id = $1`, id)
+
+	absence, err := scanAbsence(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrAbsenceNotFound
+		}
+
+		slog.Error(fmt.Sprintf("error getting absence: %v", err))
+		return nil, err
+	}
+
+	return absence, nil
+}
+
+// GetAbsences получает периоды отсутствия пользователя, отсортированные по началу
+func (r *Repository) GetAbsences(ctx context.Context, userID string) ([]*entity.Absence, error) {
+	return r.queryAbsences(ctx, `
+		SELECT `+absenceColumns+`
+		FROM user_absences
+		WHERE user_id = $1
+		ORDER BY starts_at`, userID)
+}
+
+// GetDueAbsences получает отсутствия, у которых наступила необработанная граница:
+// начало без applied_at или конец без released_at
+func (r *Repository) GetDueAbsences(ctx context.Context, at time.Time) ([]*entity.Absence, error) {
+	return r.queryAbsences(ctx, `
+		SELECT `+absenceColumns+`
+		FROM user_absences
+		WHERE released_at IS NULL
+			AND (ends_at <= $1 OR (applied_at IS NULL AND starts_at <= $1))
+		ORDER BY CASE WHEN applied_at IS NULL THEN starts_at ELSE ends_at END, id`, at)
+}
+
+// GetCurrentAbsence получает незавершенное отсутствие пользователя, покрывающее момент at,
+// или nil, если его нет
+func (r *Repository) GetCurrentAbsence(ctx context.Context, userID string, at time.Time) (*entity.Absence, error) {
+	row := r.db(ctx).QueryRow(ctx, `
+		SELECT `+absenceColumns+`
+		FROM user_absences
+		WHERE user_id = $1
+			AND starts_at <= $2 AND ends_at > $2
+			AND released_at IS NULL
+		ORDER BY starts_at
+		LIMIT 1`, userID, at)
+
+	absence, err := scanAbsence(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		slog.Error(fmt.Sprintf("error getting current absence: %v", err))
+		return nil, err
+	}
+
+	return absence, nil
+}
+
+// UpdateAbsence сохраняет состояние синхронизации отсутствия
+func (r *Repository) UpdateAbsence(ctx context.Context, absence *entity.Absence) error {
+	tag, err := r.db(ctx).Exec(ctx, `
+		UPDATE user_absences
+		SET deactivated = $2, applied_at = $3, released_at = $4
+		WHERE id = $1`,
+		absence.ID, absence.Deactivated, absence.AppliedAt, absence.ReleasedAt)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error updating absence: %v", err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrAbsenceNotFound
+	}
+
+	return nil
+}
+
+// DeleteAbsence удаляет период отсутствия
+func (r *Repository) DeleteAbsence(ctx context.Context, id int64) error {
+	tag, err := r.db(ctx).Exec(ctx, `DELETE FROM user_absences WHERE id = $1`, id)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error deleting absence: %v", err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrAbsenceNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) queryAbsences(ctx context.Context, query string, args ...any) ([]*entity.Absence, error) {
+	rows, err := r.db(ctx).Query(ctx, query, args...)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error getting absences: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	absences := make([]*entity.Absence, 0)
+	for rows.Next() {
+		absence, err := scanAbsence(rows)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error scanning absence: %v", err))
+			return nil, err
+		}
+
+		absences = append(absences, absence)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error iterating rows", "error", err)
+		return nil, err
+	}
+
+	return absences, nil
+}
+
+func scanAbsence(row pgx.Row) (*entity.Absence, error) {
+	absence := &entity.Absence{}
+	err := row.Scan(
+		&absence.ID,
+		&absence.UserID,
+		&absence.StartsAt,
+		&absence.EndsAt,
+		&absence.Reason,
+		&absence.Deactivated,
+		&absence.AppliedAt,
+		&absence.ReleasedAt,
+		&absence.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return absence, nil
+}
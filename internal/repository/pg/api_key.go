@@ -0,0 +1,147 @@
+package pg
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"log/slog"
+	"time"
+)
+
+const apiKeyColumns = `id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_by, created_at`
+
+// apiKeyTouchInterval не чаще этого интервала обновляется last_used_at, чтобы не писать на каждый запрос
+const apiKeyTouchInterval = time.Minute
+
+// CreateAPIKey сохраняет API-ключ; имя должно быть уникально среди неотозванных ключей
+func (r *Repository) CreateAPIKey(ctx context.Context, key *entity.APIKey) error {
+	err := r.db(ctx).QueryRow(ctx, `
+		INSERT INTO api_keys (name, prefix, key_hash, scopes, expires_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+		`,
+		key.Name,
+		key.Prefix,
+		key.Hash,
+		scopeStrings(key.Scopes),
+		key.ExpiresAt,
+		key.CreatedBy,
+	).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entity.NewError(entity.APIKeyExists, "api key %s already exists", key.Name)
+		}
+
+		slog.Error(fmt.Sprintf("error inserting api key: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// GetAPIKeyByHash получает API-ключ по SHA-256 ключа
+func (r *Repository) GetAPIKeyByHash(ctx context.Context, hash string) (*entity.APIKey, error) {
+	row := r.db(ctx).QueryRow(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = $1`, hash)
+
+	key, err := scanAPIKey(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrAPIKeyNotFound
+		}
+
+		slog.Error(fmt.Sprintf("error getting api key: %v", err))
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// ListAPIKeys получает все API-ключи, новые первыми
+func (r *Repository) ListAPIKeys(ctx context.Context) ([]*entity.APIKey, error) {
+	rows, err := r.db(ctx).Query(ctx, `SELECT `+apiKeyColumns+` FROM api_keys ORDER BY id DESC`)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error listing api keys: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	keys := make([]*entity.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error scanning api key: %v", err))
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error iterating rows", "error", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey отзывает API-ключ; повторный отзыв сохраняет исходное время
+func (r *Repository) RevokeAPIKey(ctx context.Context, id int64, at time.Time) (*entity.APIKey, error) {
+	row := r.db(ctx).QueryRow(ctx, `
+		UPDATE api_keys SET revoked_at = COALESCE(revoked_at, $2)
+		WHERE id = $1
+		RETURNING `+apiKeyColumns, id, at)
+
+	key, err := scanAPIKey(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrAPIKeyNotFound
+		}
+
+		slog.Error(fmt.Sprintf("error revoking api key: %v", err))
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// TouchAPIKey отмечает использование API-ключа
+func (r *Repository) TouchAPIKey(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.db(ctx).Exec(ctx, `
+		UPDATE api_keys SET last_used_at = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $3)
+		`, id, at, at.Add(-apiKeyTouchInterval))
+	if err != nil {
+		slog.Error(fmt.Sprintf("error touching api key: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+func scanAPIKey(row pgx.Row) (*entity.APIKey, error) {
+	key := &entity.APIKey{}
+	var scopes []string
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Hash, &scopes, &key.ExpiresAt,
+		&key.LastUsedAt, &key.RevokedAt, &key.CreatedBy, &key.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scope := range scopes {
+		key.Scopes = append(key.Scopes, entity.Permission(scope))
+	}
+
+	return key, nil
+}
+
+func scopeStrings(scopes []entity.Permission) []string {
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		result = append(result, string(scope))
+	}
+
+	return result
+}
@@ -0,0 +1,96 @@
+package pg
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"log/slog"
+)
+
+// AddAssignmentHistory дописывает записи в историю назначений ревьюеров
+func (r *Repository) AddAssignmentHistory(ctx context.Context, entries []*entity.AssignmentHistory) error {
+	for _, e := range entries {
+		err := r.db(ctx).QueryRow(ctx, `
+			INSERT INTO reviewer_assignments_history (pr_id, user_id, event, reason, actor, replaced_user_id)
+			VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+			RETURNING id, created_at
+			`,
+			e.PullRequestID,
+			e.UserID,
+			e.Event,
+			e.Reason,
+			e.Actor,
+			e.ReplacedUserID,
+		).Scan(&e.ID, &e.CreatedAt)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error inserting assignment history: %v", err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetAssignmentHistory получает историю назначений в хронологическом порядке
+func (r *Repository) GetAssignmentHistory(ctx context.Context, filter entity.AssignmentHistoryFilter) ([]*entity.AssignmentHistory, error) {
+	rows, err := r.db(ctx).Query(ctx, `
+		SELECT id, pr_id, user_id, event, reason, actor, COALESCE(replaced_user_id, ''), created_at
+		FROM reviewer_assignments_history
+		WHERE ($1 = '' OR pr_id = $1)
+			AND ($2 = '' OR user_id = $2)
+		ORDER BY created_at, id
+	`, filter.PullRequestID, filter.UserID)
+
+	if err != nil {
+		slog.Error(fmt.Sprintf("error getting assignment history: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	history := make([]*entity.AssignmentHistory, 0)
+	for rows.Next() {
+		e := &entity.AssignmentHistory{}
+		if err := rows.Scan(&e.ID, &e.PullRequestID, &e.UserID, &e.Event, &e.Reason, &e.Actor, &e.ReplacedUserID, &e.CreatedAt); err != nil {
+			slog.Error(fmt.Sprintf("error scanning assignment history: %v", err))
+			return nil, err
+		}
+
+		history = append(history, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error iterating rows", "error", err)
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// GetLastAssignedReviewer возвращает последнего назначенного ревьюера среди участников команды
+// или пустую строку, если назначений не было
+func (r *Repository) GetLastAssignedReviewer(ctx context.Context, teamName string) (string, error) {
+	var userID string
+	err := r.db(ctx).QueryRow(ctx, `
+		SELECT ah.user_id
+		FROM reviewer_assignments_history ah
+		JOIN users u ON u.user_id = ah.user_id
+		JOIN teams t ON t.id = u.team_id
+		WHERE t.team_name = $1 AND ah.event = 'ASSIGNED'
+		ORDER BY ah.id DESC
+		LIMIT 1
+	`, teamName).Scan(&userID)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+
+		slog.Error(fmt.Sprintf("error getting last assigned reviewer: %v", err))
+		return "", err
+	}
+
+	return userID, nil
+}
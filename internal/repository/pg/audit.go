@@ -0,0 +1,103 @@
+package pg
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// AddAuditEntry записывает действие в журнал аудита
+func (r *Repository) AddAuditEntry(ctx context.Context, e *entity.AuditEntry) error {
+	err := r.db(ctx).QueryRow(ctx, `
+		INSERT INTO audit_log (actor, role, action, entity_type, entity_id, endpoint, payload_hash, outcome, error_code)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''))
+		RETURNING id, created_at
+		`,
+		e.Actor,
+		e.Role,
+		e.Action,
+		e.EntityType,
+		e.EntityID,
+		e.Endpoint,
+		e.PayloadHash,
+		e.Outcome,
+		e.ErrorCode,
+	).Scan(&e.ID, &e.CreatedAt)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error inserting audit entry: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// ListAuditEntries получает записи журнала аудита от новых к старым, не больше filter.Limit
+func (r *Repository) ListAuditEntries(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error) {
+	w := &where{}
+	if filter.Actor != "" {
+		w.add("actor = ?", filter.Actor)
+	}
+
+	if filter.EntityType != "" {
+		w.add("entity_type = ?", filter.EntityType)
+	}
+
+	if filter.EntityID != "" {
+		w.add("entity_id = ?", filter.EntityID)
+	}
+
+	if filter.From != nil {
+		w.add("created_at >= ?", *filter.From)
+	}
+
+	if filter.To != nil {
+		w.add("created_at < ?", *filter.To)
+	}
+
+	if filter.After != nil {
+		id, err := strconv.ParseInt(filter.After.ID, 10, 64)
+		if err != nil {
+			return nil, entity.NewError(entity.InvalidRequest, "invalid cursor")
+		}
+
+		w.add("id < ?", id)
+	}
+
+	query := `
+		SELECT id, actor, role, action, entity_type, entity_id, endpoint, payload_hash, outcome,
+			COALESCE(error_code, ''), created_at
+		FROM audit_log
+		` + w.String() + `
+		ORDER BY id DESC
+		` + w.limit(filter.Limit)
+
+	rows, err := r.db(ctx).Query(ctx, query, w.args...)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error listing audit entries: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	entries := make([]*entity.AuditEntry, 0)
+	for rows.Next() {
+		e := &entity.AuditEntry{}
+		err := rows.Scan(&e.ID, &e.Actor, &e.Role, &e.Action, &e.EntityType, &e.EntityID,
+			&e.Endpoint, &e.PayloadHash, &e.Outcome, &e.ErrorCode, &e.CreatedAt)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error scanning audit entry: %v", err))
+			return nil, err
+		}
+
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error iterating rows", "error", err)
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,41 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// WithLeaderLock выполняет fn, только если удалось взять advisory-блокировку name: из нескольких
+// экземпляров сервиса задачу в каждый момент выполняет один. Блокировка сессионная и держится
+// на выделенном соединении до конца fn; при разрыве соединения Postgres снимает ее сам.
+// Возвращает false, если блокировка занята другим экземпляром
+func (r *Repository) WithLeaderLock(ctx context.Context, name string, fn func(ctx context.Context) error) (bool, error) {
+	conn, err := r.pg.Acquire(ctx)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error acquiring connection for leader lock: %v", err))
+		return false, err
+	}
+
+	defer conn.Release()
+
+	var locked bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, name).Scan(&locked); err != nil {
+		slog.Error(fmt.Sprintf("error taking leader lock: %v", err))
+		return false, err
+	}
+
+	if !locked {
+		return false, nil
+	}
+
+	defer func() {
+		// снимаем блокировку и при отмене ctx, иначе она останется на соединении в пуле
+		if _, err := conn.Exec(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock(hashtext($1))`, name); err != nil {
+			slog.Error(fmt.Sprintf("error releasing leader lock: %v", err))
+			conn.Conn().Close(context.WithoutCancel(ctx))
+		}
+	}()
+
+	return true, fn(ctx)
+}
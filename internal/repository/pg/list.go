@@ -0,0 +1,235 @@
+package pg
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// where собирает условия запроса; плейсхолдеры ? в условии заменяются на $n по порядку аргументов
+type where struct {
+	conds []string
+	args  []any
+}
+
+func (w *where) add(cond string, args ...any) {
+	for _, arg := range args {
+		w.args = append(w.args, arg)
+		cond = strings.Replace(cond, "?", fmt.Sprintf("$%d", len(w.args)), 1)
+	}
+
+	w.conds = append(w.conds, cond)
+}
+
+func (w *where) String() string {
+	if len(w.conds) == 0 {
+		return ""
+	}
+
+	return "WHERE " + strings.Join(w.conds, " AND ")
+}
+
+// limit добавляет аргумент LIMIT и возвращает его плейсхолдер
+func (w *where) limit(n int) string {
+	w.args = append(w.args, n)
+	return fmt.Sprintf("LIMIT $%d", len(w.args))
+}
+
+// likePattern экранирует спецсимволы LIKE и оборачивает подстроку в %
+func likePattern(substr string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(substr) + "%"
+}
+
+// ListTeams получает команды по возрастанию имени, не больше filter.Limit
+func (r *Repository) ListTeams(ctx context.Context, filter entity.TeamFilter) ([]*entity.TeamSummary, error) {
+	w := &where{}
+	if filter.NameContains != "" {
+		w.add("t.team_name ILIKE ?", likePattern(filter.NameContains))
+	}
+
+	if filter.After != nil {
+		w.add("t.team_name > ?", filter.After.ID)
+	}
+
+	query := `
+		SELECT t.team_name, t.reviewer_strategy, t.created_at,
+			(SELECT COUNT(*) FROM users u WHERE u.team_id = t.id),
+			(SELECT COUNT(*) FROM users u WHERE u.team_id = t.id AND u.is_active)
+		FROM teams t
+		` + w.String() + `
+		ORDER BY t.team_name
+		` + w.limit(filter.Limit)
+
+	rows, err := r.db(ctx).Query(ctx, query, w.args...)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error listing teams: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	teams := make([]*entity.TeamSummary, 0)
+	for rows.Next() {
+		t := &entity.TeamSummary{}
+		if err := rows.Scan(&t.Name, &t.ReviewerStrategy, &t.CreatedAt, &t.Members, &t.ActiveMembers); err != nil {
+			slog.Error(fmt.Sprintf("error scanning team: %v", err))
+			return nil, err
+		}
+
+		teams = append(teams, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error iterating rows", "error", err)
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+// ListUsers получает пользователей по возрастанию user_id, не больше filter.Limit
+func (r *Repository) ListUsers(ctx context.Context, filter entity.UserFilter) ([]*entity.User, error) {
+	w := &where{}
+	if filter.TeamName != "" {
+		w.add("t.team_name = ?", filter.TeamName)
+	}
+
+	if filter.IsActive != nil {
+		w.add("u.is_active = ?", *filter.IsActive)
+	}
+
+	if filter.NameContains != "" {
+		pattern := likePattern(filter.NameContains)
+		w.add("(u.user_id ILIKE ? OR u.username ILIKE ?)", pattern, pattern)
+	}
+
+	if filter.After != nil {
+		w.add("u.user_id > ?", filter.After.ID)
+	}
+
+	query := `
+		SELECT u.user_id, u.username, COALESCE(t.team_name, ''), u.is_active, u.created_at, u.updated_at
+		FROM users u
+		LEFT JOIN teams t ON t.id = u.team_id
+		` + w.String() + `
+		ORDER BY u.user_id
+		` + w.limit(filter.Limit)
+
+	rows, err := r.db(ctx).Query(ctx, query, w.args...)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error listing users: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	users := make([]*entity.User, 0)
+	for rows.Next() {
+		u := &entity.User{}
+		if err := rows.Scan(&u.UserID, &u.Name, &u.TeamName, &u.IsActive, &u.CreatedAt, &u.UpdatedAt); err != nil {
+			slog.Error(fmt.Sprintf("error scanning user: %v", err))
+			return nil, err
+		}
+
+		users = append(users, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error iterating rows", "error", err)
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// ListPRs получает PR от новых к старым с назначенными ревьюерами, не больше filter.Limit;
+// решения ревьюеров не загружаются
+func (r *Repository) ListPRs(ctx context.Context, filter entity.PRFilter) ([]*entity.PullRequest, error) {
+	w := &where{}
+	if filter.TeamName != "" {
+		w.add(`pr.author_id IN (
+			SELECT u.user_id FROM users u JOIN teams t ON t.id = u.team_id WHERE t.team_name = ?)`, filter.TeamName)
+	}
+
+	if filter.Status != "" {
+		w.add("pr.status = ?", filter.Status)
+	}
+
+	if filter.AuthorID != "" {
+		w.add("pr.author_id = ?", filter.AuthorID)
+	}
+
+	if filter.ReviewerID != "" {
+		w.add(`EXISTS (
+			SELECT 1 FROM pull_request_reviewers prr
+			WHERE prr.pr_id = pr.pull_request_id AND prr.user_id = ?)`, filter.ReviewerID)
+	}
+
+	if filter.NameContains != "" {
+		w.add("pr.pull_request_name ILIKE ?", likePattern(filter.NameContains))
+	}
+
+	if filter.CreatedFrom != nil {
+		w.add("pr.created_at >= ?", *filter.CreatedFrom)
+	}
+
+	if filter.CreatedTo != nil {
+		w.add("pr.created_at < ?", *filter.CreatedTo)
+	}
+
+	if filter.MergedFrom != nil {
+		w.add("pr.merged_at >= ?", *filter.MergedFrom)
+	}
+
+	if filter.MergedTo != nil {
+		w.add("pr.merged_at < ?", *filter.MergedTo)
+	}
+
+	if filter.After != nil && filter.After.CreatedAt != nil {
+		w.add("(pr.created_at, pr.pull_request_id) < (?, ?)", *filter.After.CreatedAt, filter.After.ID)
+	}
+
+	query := `
+		SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status,
+			pr.created_at, pr.merged_at, pr.closed_at,
+			COALESCE((
+				SELECT array_agg(prr.user_id ORDER BY prr.assigned_at, prr.user_id)
+				FROM pull_request_reviewers prr
+				WHERE prr.pr_id = pr.pull_request_id
+			), '{}')
+		FROM pull_requests pr
+		` + w.String() + `
+		ORDER BY pr.created_at DESC, pr.pull_request_id DESC
+		` + w.limit(filter.Limit)
+
+	rows, err := r.db(ctx).Query(ctx, query, w.args...)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error listing pull requests: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	prs := make([]*entity.PullRequest, 0)
+	for rows.Next() {
+		pr := &entity.PullRequest{}
+		err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status,
+			&pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt, &pr.AssignReviewers)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error scanning pull request: %v", err))
+			return nil, err
+		}
+
+		prs = append(prs, pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error iterating rows", "error", err)
+		return nil, err
+	}
+
+	return prs, nil
+}
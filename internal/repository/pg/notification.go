@@ -0,0 +1,259 @@
+package pg
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+)
+
+const notificationColumns = `id, user_id, channel, address, event_key, event_type, payload, status, attempts,
+	COALESCE(last_error, ''), scheduled_at, created_at, sent_at`
+
+// GetNotificationPreferences получает настройки уведомлений пользователя
+func (r *Repository) GetNotificationPreferences(ctx context.Context, userID string) (*entity.NotificationPreferences, error) {
+	prefs := &entity.NotificationPreferences{}
+	var (
+		channels                      []string
+		quietStart, quietEnd, quietTZ *string
+		updatedAt                     time.Time
+	)
+
+	err := r.db(ctx).QueryRow(ctx, `
+		SELECT user_id, COALESCE(email, ''), COALESCE(chat_webhook_url, ''), channels,
+			quiet_start, quiet_end, quiet_timezone, updated_at
+		FROM notification_preferences
+		WHERE user_id = $1
+		`, userID,
+	).Scan(&prefs.UserID, &prefs.Email, &prefs.ChatWebhookURL, &channels, &quietStart, &quietEnd, &quietTZ, &updatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrPreferencesNotFound
+		}
+
+		slog.Error(fmt.Sprintf("error getting notification preferences: %v", err))
+		return nil, err
+	}
+
+	prefs.Channels = make([]entity.NotificationChannel, 0, len(channels))
+	for _, channel := range channels {
+		prefs.Channels = append(prefs.Channels, entity.NotificationChannel(channel))
+	}
+
+	if quietStart != nil && quietEnd != nil && quietTZ != nil {
+		prefs.QuietHours = &entity.QuietHours{Start: *quietStart, End: *quietEnd, Timezone: *quietTZ}
+	}
+
+	prefs.UpdatedAt = &updatedAt
+	return prefs, nil
+}
+
+// UpsertNotificationPreferences сохраняет настройки уведомлений пользователя
+func (r *Repository) UpsertNotificationPreferences(ctx context.Context, prefs *entity.NotificationPreferences) error {
+	channels := make([]string, 0, len(prefs.Channels))
+	for _, channel := range prefs.Channels {
+		channels = append(channels, string(channel))
+	}
+
+	var quietStart, quietEnd, quietTZ *string
+	if prefs.QuietHours != nil {
+		quietStart, quietEnd, quietTZ = &prefs.QuietHours.Start, &prefs.QuietHours.End, &prefs.QuietHours.Timezone
+	}
+
+	var updatedAt time.Time
+	err := r.db(ctx).QueryRow(ctx, `
+		INSERT INTO notification_preferences (user_id, email, chat_webhook_url, channels, quiet_start, quiet_end, quiet_timezone)
+		SELECT u.user_id, NULLIF($2, ''), NULLIF($3, ''), $4, $5, $6, $7
+		FROM users u
+		WHERE u.user_id = $1
+		ON CONFLICT (user_id) DO UPDATE SET
+			email = EXCLUDED.email,
+			chat_webhook_url = EXCLUDED.chat_webhook_url,
+			channels = EXCLUDED.channels,
+			quiet_start = EXCLUDED.quiet_start,
+			quiet_end = EXCLUDED.quiet_end,
+			quiet_timezone = EXCLUDED.quiet_timezone,
+			updated_at = now()
+		RETURNING updated_at
+		`,
+		prefs.UserID,
+		prefs.Email,
+		prefs.ChatWebhookURL,
+		channels,
+		quietStart,
+		quietEnd,
+		quietTZ,
+	).Scan(&updatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.ErrUserNotFound
+		}
+
+		slog.Error(fmt.Sprintf("error upserting notification preferences: %v", err))
+		return err
+	}
+
+	prefs.UpdatedAt = &updatedAt
+	return nil
+}
+
+// LockConsumerOffset возвращает позицию потребителя outbox и блокирует ее до конца транзакции.
+// Новый потребитель начинает с конца outbox, не разбирая события, записанные до его появления
+func (r *Repository) LockConsumerOffset(ctx context.Context, name string) (entity.OutboxPosition, error) {
+	_, err := r.db(ctx).Exec(ctx, `
+		INSERT INTO event_consumers (name, last_id, last_txid)
+		SELECT $1, $2, (pg_snapshot_xmin(pg_current_snapshot())::text::bigint - 1)::text::xid8
+		ON CONFLICT (name) DO NOTHING
+		`, name, int64(math.MaxInt64))
+	if err != nil {
+		slog.Error(fmt.Sprintf("error initializing event consumer: %v", err))
+		return entity.OutboxPosition{}, err
+	}
+
+	var position entity.OutboxPosition
+	err = r.db(ctx).QueryRow(ctx, `
+		SELECT last_txid::text::bigint, last_id FROM event_consumers WHERE name = $1 FOR UPDATE
+		`, name).Scan(&position.TxID, &position.ID)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error locking event consumer: %v", err))
+		return entity.OutboxPosition{}, err
+	}
+
+	return position, nil
+}
+
+// SetConsumerOffset сохраняет позицию потребителя outbox
+func (r *Repository) SetConsumerOffset(ctx context.Context, name string, position entity.OutboxPosition) error {
+	_, err := r.db(ctx).Exec(ctx, `
+		UPDATE event_consumers SET last_txid = $2::bigint::text::xid8, last_id = $3, updated_at = now()
+		WHERE name = $1
+		`, name, position.TxID, position.ID)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error updating event consumer: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// AddNotifications ставит уведомления в очередь; повтор события не создает дубликатов
+func (r *Repository) AddNotifications(ctx context.Context, notifications []*entity.Notification) error {
+	batch := &pgx.Batch{}
+	for _, n := range notifications {
+		batch.Queue(`
+			INSERT INTO notifications (user_id, channel, address, event_key, event_type, payload, scheduled_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (user_id, channel, event_key) DO NOTHING
+			`, n.UserID, n.Channel, n.Address, n.EventKey, n.EventType, n.Payload, n.ScheduledAt)
+	}
+
+	if err := r.db(ctx).SendBatch(ctx, batch).Close(); err != nil {
+		slog.Error(fmt.Sprintf("error inserting notifications: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// ClaimNotifications забирает до limit уведомлений, время отправки которых наступило, и откладывает
+// их на lease, чтобы параллельные обработчики не отправили их повторно
+func (r *Repository) ClaimNotifications(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.Notification, error) {
+	rows, err := r.db(ctx).Query(ctx, `
+		WITH due AS (
+			SELECT id FROM notifications
+			WHERE status IN ('PENDING', 'RETRYING') AND scheduled_at <= $1
+			ORDER BY scheduled_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE notifications n SET scheduled_at = $2
+		FROM due
+		WHERE n.id = due.id
+		RETURNING n.id, n.user_id, n.channel, n.address, n.event_key, n.event_type, n.payload, n.status,
+			n.attempts, COALESCE(n.last_error, ''), n.scheduled_at, n.created_at, n.sent_at
+		`, now, now.Add(lease), limit)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error claiming notifications: %v", err))
+		return nil, err
+	}
+
+	return scanNotifications(rows)
+}
+
+// UpdateNotification сохраняет результат попытки отправки
+func (r *Repository) UpdateNotification(ctx context.Context, n *entity.Notification) error {
+	_, err := r.db(ctx).Exec(ctx, `
+		UPDATE notifications
+		SET status = $2, attempts = $3, last_error = NULLIF($4, ''), scheduled_at = $5, sent_at = $6
+		WHERE id = $1
+		`, n.ID, n.Status, n.Attempts, n.LastError, n.ScheduledAt, n.SentAt)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error updating notification: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// ListNotifications получает журнал уведомлений от новых к старым, не больше filter.Limit
+func (r *Repository) ListNotifications(ctx context.Context, filter entity.NotificationFilter) ([]*entity.Notification, error) {
+	w := &where{}
+	if filter.UserID != "" {
+		w.add("user_id = ?", filter.UserID)
+	}
+
+	if filter.Status != "" {
+		w.add("status = ?", filter.Status)
+	}
+
+	if filter.After != nil {
+		id, err := strconv.ParseInt(filter.After.ID, 10, 64)
+		if err != nil {
+			return nil, entity.NewError(entity.InvalidRequest, "invalid cursor")
+		}
+
+		w.add("id < ?", id)
+	}
+
+	rows, err := r.db(ctx).Query(ctx, `
+		SELECT `+notificationColumns+`
+		FROM notifications
+		`+w.String()+`
+		ORDER BY id DESC
+		`+w.limit(filter.Limit), w.args...)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error listing notifications: %v", err))
+		return nil, err
+	}
+
+	return scanNotifications(rows)
+}
+
+func scanNotifications(rows pgx.Rows) ([]*entity.Notification, error) {
+	defer rows.Close()
+
+	notifications := make([]*entity.Notification, 0)
+	for rows.Next() {
+		n := &entity.Notification{}
+		err := rows.Scan(&n.ID, &n.UserID, &n.Channel, &n.Address, &n.EventKey, &n.EventType, &n.Payload,
+			&n.Status, &n.Attempts, &n.LastError, &n.ScheduledAt, &n.CreatedAt, &n.SentAt)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error scanning notification: %v", err))
+			return nil, err
+		}
+
+		notifications = append(notifications, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error iterating rows", "error", err)
+		return nil, err
+	}
+
+	return notifications, nil
+}
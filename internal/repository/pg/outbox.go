@@ -0,0 +1,214 @@
+package pg
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"log/slog"
+	"math"
+	"time"
+)
+
+const outboxColumns = `o.id, o.event_key, o.event_type, o.team_name, o.payload, o.attempts, COALESCE(o.last_error, ''),
+	o.next_attempt_at, o.created_at, o.published_at, o.failed_at, o.txid::text::bigint`
+
+// AddOutboxEvent записывает событие в outbox; вызывается в транзакции изменения
+func (r *Repository) AddOutboxEvent(ctx context.Context, event *entity.OutboxEvent) error {
+	err := r.db(ctx).QueryRow(ctx, `
+		INSERT INTO outbox (event_key, event_type, team_name, payload)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, next_attempt_at, txid::text::bigint
+		`, event.Key, event.Type, event.TeamName, event.Payload,
+	).Scan(&event.ID, &event.CreatedAt, &event.NextAttemptAt, &event.TxID)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error inserting outbox event: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// ClaimOutboxEvents забирает до limit первых неопубликованных событий завершенных транзакций
+// в порядке (txid, id), останавливаясь на первом, срок попытки которого не наступил, и
+// откладывает их на lease. Релей другого экземпляра ждет блокировки строк и затем видит их
+// отложенными, поэтому не публикует ни их, ни следующие за ними. Блокировка держится только
+// на время запроса, публикация идет вне транзакции
+func (r *Repository) ClaimOutboxEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.OutboxEvent, error) {
+	rows, err := r.db(ctx).Query(ctx, `
+		WITH head AS (
+			SELECT id, txid, next_attempt_at FROM outbox
+			WHERE published_at IS NULL AND failed_at IS NULL
+			  AND txid < pg_snapshot_xmin(pg_current_snapshot())
+			ORDER BY txid, id
+			LIMIT $3
+			FOR UPDATE
+		), due AS (
+			SELECT h.id FROM head h
+			WHERE NOT EXISTS (
+				SELECT 1 FROM head b WHERE b.next_attempt_at > $1 AND (b.txid, b.id) <= (h.txid, h.id)
+			)
+		), o AS (
+			UPDATE outbox o SET next_attempt_at = $2
+			FROM due
+			WHERE o.id = due.id
+			RETURNING o.*
+		)
+		SELECT `+outboxColumns+`
+		FROM o
+		ORDER BY o.txid, o.id
+		`, now, now.Add(lease), limit)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error claiming outbox events: %v", err))
+		return nil, err
+	}
+
+	return scanOutboxEvents(rows)
+}
+
+// MarkOutboxPublished отмечает события опубликованными
+func (r *Repository) MarkOutboxPublished(ctx context.Context, ids []int64, at time.Time) error {
+	_, err := r.db(ctx).Exec(ctx, `
+		UPDATE outbox SET published_at = $2, attempts = attempts + 1, last_error = NULL
+		WHERE id = ANY($1)
+		`, ids, at)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error marking outbox events published: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// ReleaseOutboxEvents возвращает забранные, но не опубликованные события в очередь к сроку at
+func (r *Repository) ReleaseOutboxEvents(ctx context.Context, ids []int64, at time.Time) error {
+	_, err := r.db(ctx).Exec(ctx, `
+		UPDATE outbox SET next_attempt_at = $2
+		WHERE id = ANY($1) AND published_at IS NULL
+		`, ids, at)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error releasing outbox events: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// RecordOutboxFailure сохраняет неудачную попытку публикации: число попыток, ошибку,
+// срок следующей попытки и отметку об исчерпании попыток
+func (r *Repository) RecordOutboxFailure(ctx context.Context, event *entity.OutboxEvent) error {
+	_, err := r.db(ctx).Exec(ctx, `
+		UPDATE outbox SET attempts = $2, last_error = $3, next_attempt_at = $4, failed_at = $5
+		WHERE id = $1
+		`, event.ID, event.Attempts, event.LastError, event.NextAttemptAt, event.FailedAt)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error recording outbox failure: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// CountPendingOutboxEvents число событий, ожидающих публикации, без исчерпавших попытки
+func (r *Repository) CountPendingOutboxEvents(ctx context.Context) (int, error) {
+	var count int
+	err := r.db(ctx).QueryRow(ctx, `SELECT count(*) FROM outbox WHERE published_at IS NULL AND failed_at IS NULL`).Scan(&count)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error counting pending outbox events: %v", err))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// DeleteOutboxEvents удаляет события, опубликованные раньше publishedBefore и уже прочитанные
+// всеми потребителями outbox; возвращает число удаленных
+func (r *Repository) DeleteOutboxEvents(ctx context.Context, publishedBefore time.Time) (int, error) {
+	tag, err := r.db(ctx).Exec(ctx, `
+		DELETE FROM outbox o
+		WHERE o.published_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM event_consumers c WHERE (c.last_txid, c.last_id) < (o.txid, o.id))
+		`, publishedBefore)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error deleting outbox events: %v", err))
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// GetCommittedOutboxEvents получает до limit событий после позиции after в порядке (txid, id).
+// Возвращаются только события транзакций старше самой старой незавершенной: транзакция,
+// которая еще может зафиксироваться, задерживает чтение, но не пропускается
+func (r *Repository) GetCommittedOutboxEvents(ctx context.Context, after entity.OutboxPosition, limit int) ([]*entity.OutboxEvent, error) {
+	rows, err := r.db(ctx).Query(ctx, `
+		SELECT `+outboxColumns+`
+		FROM outbox o
+		WHERE o.txid < pg_snapshot_xmin(pg_current_snapshot())
+		  AND (o.txid, o.id) > ($1::bigint::text::xid8, $2)
+		ORDER BY o.txid, o.id
+		LIMIT $3
+		`, after.TxID, after.ID, limit)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error getting committed outbox events: %v", err))
+		return nil, err
+	}
+
+	return scanOutboxEvents(rows)
+}
+
+// GetOutboxEventPosition позиция события id в порядке чтения outbox
+func (r *Repository) GetOutboxEventPosition(ctx context.Context, id int64) (entity.OutboxPosition, error) {
+	position := entity.OutboxPosition{ID: id}
+	err := r.db(ctx).QueryRow(ctx, `SELECT txid::text::bigint FROM outbox WHERE id = $1`, id).Scan(&position.TxID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.OutboxPosition{}, entity.ErrOutboxEventNotFound
+		}
+
+		slog.Error(fmt.Sprintf("error getting outbox event position: %v", err))
+		return entity.OutboxPosition{}, err
+	}
+
+	return position, nil
+}
+
+// GetOutboxEnd позиция, после которой идут события незавершенных и будущих транзакций.
+// События транзакций, зафиксированных незадолго до вызова, тоже могут оказаться после нее
+func (r *Repository) GetOutboxEnd(ctx context.Context) (entity.OutboxPosition, error) {
+	position := entity.OutboxPosition{ID: math.MaxInt64}
+	err := r.db(ctx).QueryRow(ctx,
+		`SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint - 1`,
+	).Scan(&position.TxID)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error getting outbox end: %v", err))
+		return entity.OutboxPosition{}, err
+	}
+
+	return position, nil
+}
+
+func scanOutboxEvents(rows pgx.Rows) ([]*entity.OutboxEvent, error) {
+	defer rows.Close()
+
+	events := make([]*entity.OutboxEvent, 0)
+	for rows.Next() {
+		e := &entity.OutboxEvent{}
+		err := rows.Scan(&e.ID, &e.Key, &e.Type, &e.TeamName, &e.Payload, &e.Attempts, &e.LastError,
+			&e.NextAttemptAt, &e.CreatedAt, &e.PublishedAt, &e.FailedAt, &e.TxID)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error scanning outbox event: %v", err))
+			return nil, err
+		}
+
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error iterating rows", "error", err)
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -11,7 +11,7 @@ import (
 
 // CreatePR создает pull request
 func (r *Repository) CreatePR(ctx context.Context, pr *entity.PullRequest) error {
-	tx, err := r.pg.Begin(ctx)
+	tx, err := r.db(ctx).Begin(ctx)
 	if err != nil {
 		slog.Error(fmt.Sprintf("error "))
 		return err
@@ -31,6 +31,11 @@ func (r *Repository) CreatePR(ctx context.Context, pr *entity.PullRequest) error
 	`, pr.ID, pr.Name, pr.AuthorID, pr.Status, pr.CreatedAt)
 
 	if err != nil {
+		if isUniqueViolation(err) {
+			slog.Error("PR already exists", "pr_id", pr.ID)
+			return entity.NewError(entity.PRExists, "PR id already exists")
+		}
+
 		slog.Error(fmt.Sprintf("error insert pull request: %v", err))
 		return err
 	}
@@ -79,24 +84,24 @@ func (r *Repository) CreatePR(ctx context.Context, pr *entity.PullRequest) error
 func (r *Repository) MergePR(ctx context.Context, prID string) (*entity.PullRequest, error) {
 	pr := &entity.PullRequest{}
 
-	err := r.pg.QueryRow(ctx, `
+	err := r.db(ctx).QueryRow(ctx, `
 		UPDATE pull_requests
 		SET status = 'MERGED', merged_at = now()
-		WHERE pull_request_id = $1
-		RETURNING pull_request_id, pull_request_name, author_id, status, created_at, merged_at
-	`, prID).Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt)
+		WHERE pull_request_id = $1 AND status = 'OPEN'
+		RETURNING pull_request_id, pull_request_name, author_id, status, created_at, merged_at, closed_at
+	`, prID).Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			slog.Error(fmt.Sprintf("PR not found: %v", err))
-			return nil, errors.New("PR not found")
+			slog.Error(fmt.Sprintf("open PR not found: %v", err))
+			return nil, entity.NewError(entity.InvalidTransition, "PR is no longer %s", entity.OPEN)
 		}
 
 		slog.Error(fmt.Sprintf("error merge pull request: %v", err))
 		return nil, err
 	}
 
-	rows, err := r.pg.Query(ctx, `
+	rows, err := r.db(ctx).Query(ctx, `
 		SELECT user_id FROM pull_request_reviewers 
 		WHERE pr_id = $1
 		ORDER BY assigned_at DESC
@@ -123,28 +128,97 @@ func (r *Repository) MergePR(ctx context.Context, prID string) (*entity.PullRequ
 		return nil, err
 	}
 
+	pr.Reviews, err = r.getReviews(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
 	return pr, nil
 }
 
+// TransitionPR переводит PR из статуса from в статус to и назначает ревьюеров, если они переданы
+func (r *Repository) TransitionPR(ctx context.Context, prID, from, to string, reviewers []string) (*entity.PullRequest, error) {
+	tx, err := r.db(ctx).Begin(ctx)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error starting transaction: %v", err))
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE pull_requests
+		SET status = $3,
+			closed_at = CASE WHEN $3 = 'CLOSED' THEN now() END
+		WHERE pull_request_id = $1 AND status = $2
+	`, prID, from, to)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error updating PR status: %v", err))
+		return nil, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		slog.Error("PR status changed concurrently", "prID", prID, "from", from, "to", to)
+		return nil, entity.NewError(entity.InvalidTransition, "PR is no longer %s", from)
+	}
+
+	for _, reviewerID := range reviewers {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO pull_request_reviewers (pr_id, user_id, assigned_at)
+			VALUES ($1, $2, now())
+			ON CONFLICT (pr_id, user_id) DO NOTHING
+		`, prID, reviewerID)
+		if err != nil {
+			slog.Error("error inserting reviewer", "error", err, "pr_id", prID, "reviewer_id", reviewerID)
+			return nil, fmt.Errorf("insert reviewer: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error(fmt.Sprintf("error committing PR transition: %v", err))
+		return nil, err
+	}
+
+	return r.GetPR(ctx, prID)
+}
+
+// ReassignReviewer заменяет ревьюера в PR. Строка PR блокируется до конца транзакции, поэтому
+// параллельные замены того же ревьюера выполняются по очереди и вторая получает NOT_ASSIGNED
 func (r *Repository) ReassignReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) (*entity.PullRequest, error) {
-	pr, err := r.GetPR(ctx, prID)
+	tx, err := r.db(ctx).Begin(ctx)
 	if err != nil {
+		slog.Error(fmt.Sprintf("error starting transaction: %v", err))
 		return nil, err
 	}
 
-	if pr.Status == entity.MERGED {
+	defer tx.Rollback(ctx)
+
+	var status string
+	err = tx.QueryRow(ctx, `
+		SELECT status FROM pull_requests WHERE pull_request_id = $1 FOR UPDATE
+	`, prID).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrPRNotFound
+		}
+
+		slog.Error(fmt.Sprintf("error locking PR: %v", err))
+		return nil, err
+	}
+
+	if status == entity.MERGED {
 		slog.Error("PR is already merged", "prID", prID)
-		return nil, fmt.Errorf("PR_MERGED: cannot reassign on merged PR")
+		return nil, entity.NewError(entity.PRMerged, "cannot reassign on merged PR")
 	}
 
-	if !contains(pr.AssignReviewers, oldReviewerID) {
-		slog.Error("Reviewer is not assigned to PR", "prID", prID, "reviewerID", oldReviewerID)
-		return nil, fmt.Errorf("NOT_ASSIGNED: reviewer is not assigned to this PR")
+	if status != entity.OPEN {
+		slog.Error("PR is not open", "prID", prID, "status", status)
+		return nil, entity.NewError(entity.PRNotOpen, "cannot reassign on %s PR", status)
 	}
 
-	_, err = r.pg.Exec(ctx, `
+	tag, err := tx.Exec(ctx, `
 		UPDATE pull_request_reviewers
-		SET user_id = $1, assigned_at = now()
+		SET user_id = $1, assigned_at = now(), reminded_at = NULL, escalated_at = NULL
 		WHERE pr_id = $2 AND user_id = $3
 	`, newReviewerID, prID, oldReviewerID)
 
@@ -153,37 +227,40 @@ func (r *Repository) ReassignReviewer(ctx context.Context, prID, oldReviewerID,
 		return nil, err
 	}
 
-	for i, reviewerID := range pr.AssignReviewers {
-		if reviewerID == oldReviewerID {
-			pr.AssignReviewers[i] = newReviewerID
-			break
-		}
+	if tag.RowsAffected() == 0 {
+		slog.Error("Reviewer is not assigned to PR", "prID", prID, "reviewerID", oldReviewerID)
+		return nil, entity.NewError(entity.NotAssigned, "reviewer is not assigned to this PR")
 	}
 
-	return pr, nil
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error(fmt.Sprintf("error committing reassignment: %v", err))
+		return nil, err
+	}
+
+	return r.GetPR(ctx, prID)
 }
 
 // GetPR вспомогательная функция получения pull request
 func (r *Repository) GetPR(ctx context.Context, prID string) (*entity.PullRequest, error) {
 	pr := &entity.PullRequest{}
 
-	err := r.pg.QueryRow(ctx, `
-		SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at
+	err := r.db(ctx).QueryRow(ctx, `
+		SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, closed_at
 		FROM pull_requests
 		WHERE pull_request_id = $1
-	`, prID).Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt)
+	`, prID).Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			slog.Error(fmt.Sprintf("PR not found: %v", err))
-			return nil, errors.New("PR not found")
+			return nil, entity.ErrPRNotFound
 		}
 
 		slog.Error(fmt.Sprintf("error getting pull request: %v", err))
 		return nil, err
 	}
 
-	rows, err := r.pg.Query(ctx, `
+	rows, err := r.db(ctx).Query(ctx, `
 		SELECT user_id FROM pull_request_reviewers
 		WHERE pr_id = $1
 	`, prID)
@@ -211,9 +288,65 @@ func (r *Repository) GetPR(ctx context.Context, prID string) (*entity.PullReques
 		return nil, err
 	}
 
+	pr.Reviews, err = r.getReviews(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
 	return pr, nil
 }
 
+// AddReview сохраняет решение ревьюера по pull request
+func (r *Repository) AddReview(ctx context.Context, prID string, review *entity.Review) error {
+	err := r.db(ctx).QueryRow(ctx, `
+		INSERT INTO pull_request_reviews (pr_id, reviewer_id, decision, comment, created_at)
+		VALUES ($1, $2, $3, $4, now())
+		RETURNING created_at
+	`, prID, review.ReviewerID, review.Decision, review.Comment).Scan(&review.CreatedAt)
+
+	if err != nil {
+		slog.Error(fmt.Sprintf("error inserting review: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// getReviews получает решения ревьюеров по PR в хронологическом порядке
+func (r *Repository) getReviews(ctx context.Context, prID string) ([]*entity.Review, error) {
+	rows, err := r.db(ctx).Query(ctx, `
+		SELECT reviewer_id, decision, comment, created_at
+		FROM pull_request_reviews
+		WHERE pr_id = $1
+		ORDER BY created_at, id
+	`, prID)
+
+	if err != nil {
+		slog.Error(fmt.Sprintf("error getting reviews: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	reviews := make([]*entity.Review, 0)
+	for rows.Next() {
+		review := &entity.Review{}
+		if err := rows.Scan(&review.ReviewerID, &review.Decision, &review.Comment, &review.CreatedAt); err != nil {
+			slog.Error(fmt.Sprintf("error scanning review: %v", err))
+			return nil, err
+		}
+
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error(fmt.Sprintf("error rows iteration: %v", err))
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
 // contains проверяет, содержит ли слайс строку
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -227,7 +360,7 @@ func contains(slice []string, item string) bool {
 // PRExists проверяет pull request на существование
 func (r *Repository) PRExists(ctx context.Context, prID string) (bool, error) {
 	var exists bool
-	err := r.pg.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM pull_requests WHERE pull_request_id = $1)", prID).Scan(&exists)
+	err := r.db(ctx).QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM pull_requests WHERE pull_request_id = $1)", prID).Scan(&exists)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			slog.Error(fmt.Sprintf("PR not found: %v", err))
@@ -247,13 +380,13 @@ func (r *Repository) GetReviewersWorkload(ctx context.Context, userIDs []string)
 		return map[string]int{}, nil
 	}
 
-	rows, err := r.pg.Query(ctx, `
-		SELECT prr.pr_id, COUNT(*) 
+	rows, err := r.db(ctx).Query(ctx, `
+		SELECT prr.user_id, COUNT(*)
 		FROM pull_request_reviewers prr
 		JOIN pull_requests pr ON pr.pull_request_id = prr.pr_id
 		WHERE prr.user_id = ANY($1)
 		AND pr.status = 'OPEN'
-		GROUP BY prr.pr_id
+		GROUP BY prr.user_id
 	`, userIDs)
 
 	if err != nil {
@@ -279,5 +412,81 @@ func (r *Repository) GetReviewersWorkload(ctx context.Context, userIDs []string)
 		workload[reviewerID] = count
 	}
 
+	if err := rows.Err(); err != nil {
+		slog.Error(fmt.Sprintf("error rows iteration: %v", err))
+		return nil, err
+	}
+
 	return workload, nil
 }
+
+// GetPRReviewers получает назначенных ревьюеров PR в порядке назначения
+func (r *Repository) GetPRReviewers(ctx context.Context, prID string) ([]*entity.AssignedReviewer, error) {
+	rows, err := r.db(ctx).Query(ctx, `
+		SELECT user_id, assigned_at
+		FROM pull_request_reviewers
+		WHERE pr_id = $1
+		ORDER BY assigned_at, user_id
+	`, prID)
+
+	if err != nil {
+		slog.Error(fmt.Sprintf("error getting reviewers: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	reviewers := make([]*entity.AssignedReviewer, 0)
+	for rows.Next() {
+		reviewer := &entity.AssignedReviewer{}
+		if err := rows.Scan(&reviewer.UserID, &reviewer.AssignedAt); err != nil {
+			slog.Error(fmt.Sprintf("error scanning reviewer: %v", err))
+			return nil, err
+		}
+
+		reviewers = append(reviewers, reviewer)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error(fmt.Sprintf("error rows iteration: %v", err))
+		return nil, err
+	}
+
+	return reviewers, nil
+}
+
+// GetPRReassignments получает переназначения ревьюеров PR в хронологическом порядке
+// из истории назначений
+func (r *Repository) GetPRReassignments(ctx context.Context, prID string) ([]*entity.Reassignment, error) {
+	rows, err := r.db(ctx).Query(ctx, `
+		SELECT replaced_user_id, user_id, created_at
+		FROM reviewer_assignments_history
+		WHERE pr_id = $1 AND replaced_user_id IS NOT NULL
+		ORDER BY created_at, id
+	`, prID)
+
+	if err != nil {
+		slog.Error(fmt.Sprintf("error getting reassignments: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	reassignments := make([]*entity.Reassignment, 0)
+	for rows.Next() {
+		reassignment := &entity.Reassignment{}
+		if err := rows.Scan(&reassignment.OldReviewerID, &reassignment.NewReviewerID, &reassignment.ReassignedAt); err != nil {
+			slog.Error(fmt.Sprintf("error scanning reassignment: %v", err))
+			return nil, err
+		}
+
+		reassignments = append(reassignments, reassignment)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error(fmt.Sprintf("error rows iteration: %v", err))
+		return nil, err
+	}
+
+	return reassignments, nil
+}
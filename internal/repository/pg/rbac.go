@@ -0,0 +1,109 @@
+package pg
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"log/slog"
+)
+
+// AddRoleBinding выдает роль пользователю глобально или в команде binding.TeamName
+func (r *Repository) AddRoleBinding(ctx context.Context, binding *entity.RoleBinding) error {
+	err := r.db(ctx).QueryRow(ctx, `
+		INSERT INTO role_bindings (user_id, role, team_id, created_by)
+		SELECT $1, $2, t.id, $4
+		FROM (SELECT NULLIF($3, '') AS team_name) AS b
+		LEFT JOIN teams t ON t.team_name = b.team_name
+		WHERE b.team_name IS NULL OR t.id IS NOT NULL
+		RETURNING id, created_at
+		`,
+		binding.UserID,
+		binding.Role,
+		binding.TeamName,
+		binding.CreatedBy,
+	).Scan(&binding.ID, &binding.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.ErrTeamNotFound
+		}
+
+		if isUniqueViolation(err) {
+			return entity.NewError(entity.BindingExists, "role %s is already bound to user %s", binding.Role, binding.UserID)
+		}
+
+		slog.Error(fmt.Sprintf("error inserting role binding: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// DeleteRoleBinding отзывает привязку роли и возвращает ее
+func (r *Repository) DeleteRoleBinding(ctx context.Context, id int64) (*entity.RoleBinding, error) {
+	b := &entity.RoleBinding{}
+	err := r.db(ctx).QueryRow(ctx, `
+		WITH deleted AS (
+			DELETE FROM role_bindings WHERE id = $1
+			RETURNING id, user_id, role, team_id, created_by, created_at
+		)
+		SELECT d.id, d.user_id, d.role, COALESCE(t.team_name, ''), d.created_by, d.created_at
+		FROM deleted d
+		LEFT JOIN teams t ON t.id = d.team_id
+		`, id,
+	).Scan(&b.ID, &b.UserID, &b.Role, &b.TeamName, &b.CreatedBy, &b.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrBindingNotFound
+		}
+
+		slog.Error(fmt.Sprintf("error deleting role binding: %v", err))
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// GetRoleBindings получает привязки ролей по фильтру, отсортированные по ID
+func (r *Repository) GetRoleBindings(ctx context.Context, filter entity.RoleBindingFilter) ([]*entity.RoleBinding, error) {
+	w := &where{}
+	if filter.UserID != "" {
+		w.add("b.user_id = ?", filter.UserID)
+	}
+
+	if filter.TeamName != "" {
+		w.add("t.team_name = ?", filter.TeamName)
+	}
+
+	rows, err := r.db(ctx).Query(ctx, `
+		SELECT b.id, b.user_id, b.role, COALESCE(t.team_name, ''), b.created_by, b.created_at
+		FROM role_bindings b
+		LEFT JOIN teams t ON t.id = b.team_id
+		`+w.String()+`
+		ORDER BY b.id`, w.args...)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error getting role bindings: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	bindings := make([]*entity.RoleBinding, 0)
+	for rows.Next() {
+		b := &entity.RoleBinding{}
+		if err := rows.Scan(&b.ID, &b.UserID, &b.Role, &b.TeamName, &b.CreatedBy, &b.CreatedAt); err != nil {
+			slog.Error(fmt.Sprintf("error scanning role binding: %v", err))
+			return nil, err
+		}
+
+		bindings = append(bindings, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error iterating rows", "error", err)
+		return nil, err
+	}
+
+	return bindings, nil
+}
@@ -2,6 +2,8 @@ package pg
 
 import (
 	"context"
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"log/slog"
 	"time"
@@ -46,3 +48,9 @@ func New(ctx context.Context, cfg *Config) *Repository {
 func (r *Repository) Close() {
 	r.pg.Close()
 }
+
+// isUniqueViolation проверяет, что ошибка вызвана нарушением уникальности
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
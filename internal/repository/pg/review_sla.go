@@ -0,0 +1,109 @@
+package pg
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// GetStaleReviews получает до limit назначений на открытые PR, по которым пора напомнить
+// ревьюеру или заменить его по SLA команды автора, от самых долгих простоев.
+// Простой отсчитывается от назначения или последнего комментария ревьюера; ревьюеры,
+// уже одобрившие PR или запросившие изменения, не простаивают. Срок замены — как в
+// entity.StaleReview.EscalatesAt: после напоминания за текущий простой, если напоминания включены
+func (r *Repository) GetStaleReviews(ctx context.Context, now time.Time, limit int) ([]*entity.StaleReview, error) {
+	rows, err := r.db(ctx).Query(ctx, `
+		WITH idle AS (
+			SELECT prr.pr_id, prr.user_id, t.team_name,
+				GREATEST(prr.assigned_at, COALESCE(rv.last_review_at, prr.assigned_at)) AS idle_since,
+				COALESCE(ts.review_sla_minutes, $2) AS sla_minutes,
+				COALESCE(ts.escalation_minutes, $3) AS escalation_minutes,
+				prr.reminded_at, prr.escalated_at
+			FROM pull_request_reviewers prr
+			JOIN pull_requests pr ON pr.pull_request_id = prr.pr_id AND pr.status = 'OPEN'
+			JOIN users a ON a.user_id = pr.author_id
+			JOIN teams t ON t.id = a.team_id
+			LEFT JOIN team_settings ts ON ts.team_id = t.id
+			LEFT JOIN LATERAL (
+				SELECT max(created_at) AS last_review_at, bool_or(decision <> 'COMMENTED') AS decided
+				FROM pull_request_reviews
+				WHERE pr_id = prr.pr_id AND reviewer_id = prr.user_id
+			) rv ON true
+			WHERE NOT COALESCE(rv.decided, false)
+		)
+		SELECT pr_id, user_id, team_name, idle_since, sla_minutes, escalation_minutes, reminded_at, escalated_at
+		FROM idle
+		WHERE (sla_minutes > 0 AND idle_since <= $1 - make_interval(mins => sla_minutes)
+				AND (reminded_at IS NULL OR reminded_at < idle_since))
+			OR (escalation_minutes > 0 AND (escalated_at IS NULL OR escalated_at < idle_since)
+				AND CASE WHEN sla_minutes > 0
+					THEN reminded_at >= idle_since
+						AND reminded_at <= $1 - make_interval(mins => escalation_minutes - sla_minutes)
+					ELSE idle_since <= $1 - make_interval(mins => escalation_minutes)
+				END)
+		ORDER BY idle_since, pr_id, user_id
+		LIMIT $4
+		`, now, entity.DefaultReviewSLAMinutes, entity.DefaultEscalationMinutes, limit)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error getting stale reviews: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	reviews := make([]*entity.StaleReview, 0)
+	for rows.Next() {
+		var (
+			s                    entity.StaleReview
+			slaMinutes, escalate int
+		)
+
+		err := rows.Scan(&s.PullRequestID, &s.ReviewerID, &s.TeamName, &s.IdleSince, &slaMinutes, &escalate,
+			&s.RemindedAt, &s.EscalatedAt)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error scanning stale review: %v", err))
+			return nil, err
+		}
+
+		s.ReviewSLA = time.Duration(slaMinutes) * time.Minute
+		s.Escalation = time.Duration(escalate) * time.Minute
+		reviews = append(reviews, &s)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error(fmt.Sprintf("error iterating stale reviews: %v", err))
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// MarkReviewReminded сохраняет время напоминания ревьюеру
+func (r *Repository) MarkReviewReminded(ctx context.Context, prID, userID string, at time.Time) error {
+	_, err := r.db(ctx).Exec(ctx, `
+		UPDATE pull_request_reviewers SET reminded_at = $3
+		WHERE pr_id = $1 AND user_id = $2
+		`, prID, userID, at)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error marking review reminded: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// MarkReviewEscalated сохраняет время попытки заменить ревьюера
+func (r *Repository) MarkReviewEscalated(ctx context.Context, prID, userID string, at time.Time) error {
+	_, err := r.db(ctx).Exec(ctx, `
+		UPDATE pull_request_reviewers SET escalated_at = $3
+		WHERE pr_id = $1 AND user_id = $2
+		`, prID, userID, at)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error marking review escalated: %v", err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package pg
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// GetReviewerStats считает статистику ревью по пользователям; назначения берутся из истории
+// назначений, включая снятые, и вместе с открытыми и смерженными ревью учитываются
+// по дате создания PR, переназначения по дате переназначения
+func (r *Repository) GetReviewerStats(ctx context.Context, filter entity.StatsFilter) ([]*entity.ReviewerStats, error) {
+	rows, err := r.db(ctx).Query(ctx, `
+		SELECT u.user_id, u.username, COALESCE(t.team_name, ''), u.is_active,
+			COALESCE(h.assigned, 0),
+			COALESCE(a.open, 0),
+			COALESCE(a.merged, 0),
+			COALESCE(m_in.cnt, 0),
+			COALESCE(m_out.cnt, 0),
+			a.median_ttm
+		FROM users u
+		LEFT JOIN teams t ON t.id = u.team_id
+		LEFT JOIN (
+			SELECT prr.user_id,
+				COUNT(*) FILTER (WHERE pr.status = 'OPEN') AS open,
+				COUNT(*) FILTER (WHERE pr.status = 'MERGED') AS merged,
+				percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM pr.merged_at - pr.created_at))
+					FILTER (WHERE pr.status = 'MERGED') AS median_ttm
+			FROM pull_request_reviewers prr
+			JOIN pull_requests pr ON pr.pull_request_id = prr.pr_id
+			WHERE ($2::timestamptz IS NULL OR pr.created_at >= $2)
+				AND ($3::timestamptz IS NULL OR pr.created_at < $3)
+			GROUP BY prr.user_id
+		) a ON a.user_id = u.user_id
+		LEFT JOIN (
+			SELECT ah.user_id, COUNT(DISTINCT ah.pr_id) AS assigned
+			FROM reviewer_assignments_history ah
+			JOIN pull_requests pr ON pr.pull_request_id = ah.pr_id
+			WHERE ah.event = 'ASSIGNED'
+				AND ($2::timestamptz IS NULL OR pr.created_at >= $2)
+				AND ($3::timestamptz IS NULL OR pr.created_at < $3)
+			GROUP BY ah.user_id
+		) h ON h.user_id = u.user_id
+		LEFT JOIN (
+			SELECT user_id, COUNT(*) AS cnt
+			FROM reviewer_assignments_history
+			WHERE replaced_user_id IS NOT NULL
+				AND ($2::timestamptz IS NULL OR created_at >= $2)
+				AND ($3::timestamptz IS NULL OR created_at < $3)
+			GROUP BY user_id
+		) m_in ON m_in.user_id = u.user_id
+		LEFT JOIN (
+			SELECT replaced_user_id AS user_id, COUNT(*) AS cnt
+			FROM reviewer_assignments_history
+			WHERE replaced_user_id IS NOT NULL
+				AND ($2::timestamptz IS NULL OR created_at >= $2)
+				AND ($3::timestamptz IS NULL OR created_at < $3)
+			GROUP BY replaced_user_id
+		) m_out ON m_out.user_id = u.user_id
+		WHERE ($1 = '' OR t.team_name = $1)
+		ORDER BY t.team_name, u.user_id
+	`, filter.TeamName, filter.From, filter.To)
+
+	if err != nil {
+		slog.Error(fmt.Sprintf("error getting reviewer stats: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	stats := make([]*entity.ReviewerStats, 0)
+	for rows.Next() {
+		s := &entity.ReviewerStats{}
+		if err := rows.Scan(
+			&s.UserID,
+			&s.Username,
+			&s.TeamName,
+			&s.IsActive,
+			&s.Assigned,
+			&s.Open,
+			&s.MergedReviewed,
+			&s.ReassignedIn,
+			&s.ReassignedOut,
+			&s.MedianTimeToMergeSeconds,
+		); err != nil {
+			slog.Error(fmt.Sprintf("error scanning reviewer stats: %v", err))
+			return nil, err
+		}
+
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error(fmt.Sprintf("error rows iteration: %v", err))
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// GetTeamStats считает статистику по командам; PR относится к команде автора
+func (r *Repository) GetTeamStats(ctx context.Context, filter entity.StatsFilter) ([]*entity.TeamStats, error) {
+	rows, err := r.db(ctx).Query(ctx, `
+		SELECT t.team_name,
+			(SELECT COUNT(*) FROM users u WHERE u.team_id = t.id),
+			(SELECT COUNT(*) FROM users u WHERE u.team_id = t.id AND u.is_active),
+			COALESCE(p.created, 0),
+			COALESCE(p.open, 0),
+			COALESCE(p.merged, 0),
+			COALESCE(rv.assigned, 0),
+			COALESCE(mv.cnt, 0),
+			p.median_ttm
+		FROM teams t
+		LEFT JOIN (
+			SELECT u.team_id,
+				COUNT(*) AS created,
+				COUNT(*) FILTER (WHERE pr.status = 'OPEN') AS open,
+				COUNT(*) FILTER (WHERE pr.status = 'MERGED') AS merged,
+				percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM pr.merged_at - pr.created_at))
+					FILTER (WHERE pr.status = 'MERGED') AS median_ttm
+			FROM pull_requests pr
+			JOIN users u ON u.user_id = pr.author_id
+			WHERE ($2::timestamptz IS NULL OR pr.created_at >= $2)
+				AND ($3::timestamptz IS NULL OR pr.created_at < $3)
+			GROUP BY u.team_id
+		) p ON p.team_id = t.id
+		LEFT JOIN (
+			SELECT u.team_id, COUNT(DISTINCT (ah.pr_id, ah.user_id)) AS assigned
+			FROM reviewer_assignments_history ah
+			JOIN pull_requests pr ON pr.pull_request_id = ah.pr_id
+			JOIN users u ON u.user_id = ah.user_id
+			WHERE ah.event = 'ASSIGNED'
+				AND ($2::timestamptz IS NULL OR pr.created_at >= $2)
+				AND ($3::timestamptz IS NULL OR pr.created_at < $3)
+			GROUP BY u.team_id
+		) rv ON rv.team_id = t.id
+		LEFT JOIN (
+			SELECT u.team_id, COUNT(*) AS cnt
+			FROM reviewer_assignments_history ah
+			JOIN users u ON u.user_id = ah.replaced_user_id
+			WHERE ($2::timestamptz IS NULL OR ah.created_at >= $2)
+				AND ($3::timestamptz IS NULL OR ah.created_at < $3)
+			GROUP BY u.team_id
+		) mv ON mv.team_id = t.id
+		WHERE ($1 = '' OR t.team_name = $1)
+		ORDER BY t.team_name
+	`, filter.TeamName, filter.From, filter.To)
+
+	if err != nil {
+		slog.Error(fmt.Sprintf("error getting team stats: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	stats := make([]*entity.TeamStats, 0)
+	for rows.Next() {
+		s := &entity.TeamStats{}
+		if err := rows.Scan(
+			&s.TeamName,
+			&s.Members,
+			&s.ActiveMembers,
+			&s.PRsCreated,
+			&s.PRsOpen,
+			&s.PRsMerged,
+			&s.ReviewsAssigned,
+			&s.Reassignments,
+			&s.MedianTimeToMergeSeconds,
+		); err != nil {
+			slog.Error(fmt.Sprintf("error scanning team stats: %v", err))
+			return nil, err
+		}
+
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error(fmt.Sprintf("error rows iteration: %v", err))
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -3,16 +3,19 @@ package pg
 import (
 	"avito_test_task/internal/entity"
 	"context"
+	"errors"
 	"fmt"
+	"github.com/jackc/pgx/v5"
 	"log/slog"
 	"time"
 )
 
-// CreateTeam создает команду и добавляет в нее участников
+// CreateTeam создает команду и добавляет в нее участников; существующие пользователи без команды
+// сохраняют свою активность
 func (r *Repository) CreateTeam(ctx context.Context, team *entity.Team) error {
 	var teamID int
 
-	tx, err := r.pg.Begin(ctx)
+	tx, err := r.db(ctx).Begin(ctx)
 	if err != nil {
 		slog.Error(fmt.Sprintf("error starting transaction: %v", err))
 		return err
@@ -20,11 +23,16 @@ func (r *Repository) CreateTeam(ctx context.Context, team *entity.Team) error {
 
 	defer tx.Rollback(ctx)
 	err = tx.QueryRow(ctx, `
-		INSERT INTO teams (team_name, created_at)
-		VALUES($1, now())
+		INSERT INTO teams (team_name, reviewer_strategy, created_at)
+		VALUES($1, $2, now())
 		RETURNING id
-		`, team.Name).Scan(&teamID)
+		`, team.Name, team.ReviewerStrategy).Scan(&teamID)
 	if err != nil {
+		if isUniqueViolation(err) {
+			slog.Error("team already exists", "team", team.Name)
+			return entity.NewError(entity.TeamExists, "team with name %s already exists", team.Name)
+		}
+
 		slog.Error(fmt.Sprintf("error inserting team: %v", err))
 		return err
 	}
@@ -36,13 +44,12 @@ func (r *Repository) CreateTeam(ctx context.Context, team *entity.Team) error {
 			ON CONFLICT (user_id) DO UPDATE
 				SET username = EXCLUDED.username,
 					team_id = EXCLUDED.team_id,
-					is_active = EXCLUDED.is_active,
 					updated_at = now()
 			`,
 			member.UserID,
 			member.Name,
 			teamID,
-			true,
+			member.IsActive,
 		)
 		if err != nil {
 			slog.Error("error inserting user",
@@ -58,8 +65,8 @@ func (r *Repository) CreateTeam(ctx context.Context, team *entity.Team) error {
 
 // GetTeam получает команду с участниками
 func (r *Repository) GetTeam(ctx context.Context, teamName string) (*entity.Team, error) {
-	rows, err := r.pg.Query(ctx, `
-		SELECT t.team_name, t.created_at,
+	rows, err := r.db(ctx).Query(ctx, `
+		SELECT t.team_name, t.reviewer_strategy, t.created_at,
 			u.user_id, u.username, u.is_active
 		FROM teams t
 		LEFT JOIN users u ON u.team_id = t.id
@@ -78,13 +85,14 @@ func (r *Repository) GetTeam(ctx context.Context, teamName string) (*entity.Team
 	for rows.Next() {
 		var (
 			teamName  string
+			strategy  string
 			createdAt time.Time
 			userID    *string
 			username  *string
 			isActive  *bool
 		)
 
-		err = rows.Scan(&teamName, &createdAt, &userID, &username, &isActive)
+		err = rows.Scan(&teamName, &strategy, &createdAt, &userID, &username, &isActive)
 		if err != nil {
 			slog.Error(fmt.Sprintf("error scanning row: %v", err))
 			return nil, err
@@ -92,9 +100,10 @@ func (r *Repository) GetTeam(ctx context.Context, teamName string) (*entity.Team
 
 		if team == nil {
 			team = &entity.Team{
-				Name:      teamName,
-				CreatedAt: &createdAt,
-				Members:   members,
+				Name:             teamName,
+				ReviewerStrategy: entity.ReviewerStrategy(strategy),
+				CreatedAt:        &createdAt,
+				Members:          members,
 			}
 		}
 
@@ -113,8 +122,8 @@ func (r *Repository) GetTeam(ctx context.Context, teamName string) (*entity.Team
 	}
 
 	if team == nil {
-		slog.Error("team not found")
-		return nil, err
+		slog.Error("team not found", "team", teamName)
+		return nil, entity.ErrTeamNotFound
 	}
 
 	team.Members = members
@@ -125,7 +134,7 @@ func (r *Repository) GetTeam(ctx context.Context, teamName string) (*entity.Team
 func (r *Repository) TeamExists(ctx context.Context, teamName string) (bool, error) {
 	var exists bool
 
-	err := r.pg.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", teamName).Scan(&exists)
+	err := r.db(ctx).QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", teamName).Scan(&exists)
 	if err != nil {
 		slog.Error(fmt.Sprintf("error checking existence of team: %v", err))
 		return false, err
@@ -133,3 +142,312 @@ func (r *Repository) TeamExists(ctx context.Context, teamName string) (bool, err
 
 	return exists, nil
 }
+
+// GetTeamReviewerStrategy получает стратегию выбора ревьюеров команды
+func (r *Repository) GetTeamReviewerStrategy(ctx context.Context, teamName string) (entity.ReviewerStrategy, error) {
+	var strategy string
+
+	err := r.db(ctx).QueryRow(ctx, "SELECT reviewer_strategy FROM teams WHERE team_name = $1", teamName).Scan(&strategy)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Error("team not found", "team", teamName)
+			return "", entity.ErrTeamNotFound
+		}
+
+		slog.Error(fmt.Sprintf("error getting reviewer strategy: %v", err))
+		return "", err
+	}
+
+	return entity.ReviewerStrategy(strategy), nil
+}
+
+// SetTeamReviewerStrategy обновляет стратегию выбора ревьюеров команды
+func (r *Repository) SetTeamReviewerStrategy(ctx context.Context, teamName string, strategy entity.ReviewerStrategy) error {
+	tag, err := r.db(ctx).Exec(ctx, "UPDATE teams SET reviewer_strategy = $1 WHERE team_name = $2", strategy, teamName)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error updating reviewer strategy: %v", err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		slog.Error("team not found", "team", teamName)
+		return entity.ErrTeamNotFound
+	}
+
+	return nil
+}
+
+// GetTeamSettings получает политику ревью команды, для команд без настроек возвращает значения по умолчанию
+func (r *Repository) GetTeamSettings(ctx context.Context, teamName string) (*entity.TeamSettings, error) {
+	settings := &entity.TeamSettings{}
+	var teamLeadID *string
+
+	err := r.db(ctx).QueryRow(ctx, `
+		SELECT t.team_name,
+			COALESCE(ts.min_reviewers, $2),
+			COALESCE(ts.max_reviewers, $3),
+			COALESCE(ts.required_approvals, $4),
+			COALESCE(ts.require_team_lead, false),
+			ts.team_lead_id,
+			COALESCE(ts.review_sla_minutes, $5),
+			COALESCE(ts.escalation_minutes, $6),
+			ts.updated_at
+		FROM teams t
+		LEFT JOIN team_settings ts ON ts.team_id = t.id
+		WHERE t.team_name = $1
+	`, teamName, entity.DefaultMinReviewers, entity.DefaultMaxReviewers, entity.DefaultRequiredApprovals,
+		entity.DefaultReviewSLAMinutes, entity.DefaultEscalationMinutes).Scan(
+		&settings.TeamName,
+		&settings.MinReviewers,
+		&settings.MaxReviewers,
+		&settings.RequiredApprovals,
+		&settings.RequireTeamLead,
+		&teamLeadID,
+		&settings.ReviewSLAMinutes,
+		&settings.EscalationMinutes,
+		&settings.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Error("team not found", "team", teamName)
+			return nil, entity.ErrTeamNotFound
+		}
+
+		slog.Error(fmt.Sprintf("error getting team settings: %v", err))
+		return nil, err
+	}
+
+	if teamLeadID != nil {
+		settings.TeamLeadID = *teamLeadID
+	}
+
+	return settings, nil
+}
+
+// UpsertTeamSettings сохраняет политику ревью команды
+func (r *Repository) UpsertTeamSettings(ctx context.Context, settings *entity.TeamSettings) error {
+	var teamLeadID *string
+	if settings.TeamLeadID != "" {
+		teamLeadID = &settings.TeamLeadID
+	}
+
+	err := r.db(ctx).QueryRow(ctx, `
+		INSERT INTO team_settings (team_id, min_reviewers, max_reviewers, required_approvals, require_team_lead, team_lead_id,
+			review_sla_minutes, escalation_minutes, updated_at)
+		SELECT id, $2, $3, $4, $5, $6, $7, $8, now()
+		FROM teams
+		WHERE team_name = $1
+		ON CONFLICT (team_id) DO UPDATE
+			SET min_reviewers = EXCLUDED.min_reviewers,
+				max_reviewers = EXCLUDED.max_reviewers,
+				required_approvals = EXCLUDED.required_approvals,
+				require_team_lead = EXCLUDED.require_team_lead,
+				team_lead_id = EXCLUDED.team_lead_id,
+				review_sla_minutes = EXCLUDED.review_sla_minutes,
+				escalation_minutes = EXCLUDED.escalation_minutes,
+				updated_at = now()
+		RETURNING updated_at
+	`,
+		settings.TeamName,
+		settings.MinReviewers,
+		settings.MaxReviewers,
+		settings.RequiredApprovals,
+		settings.RequireTeamLead,
+		teamLeadID,
+		settings.ReviewSLAMinutes,
+		settings.EscalationMinutes,
+	).Scan(&settings.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Error("team not found", "team", settings.TeamName)
+			return entity.ErrTeamNotFound
+		}
+
+		slog.Error(fmt.Sprintf("error upserting team settings: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// AddTeamMember добавляет пользователя в команду; существующий пользователь без команды
+// привязывается к ней с обновлением имени, его активность не меняется
+func (r *Repository) AddTeamMember(ctx context.Context, teamName string, member *entity.TeamMember) error {
+	tag, err := r.db(ctx).Exec(ctx, `
+		INSERT INTO users (user_id, username, team_id, is_active)
+		SELECT $1, $2, t.id, $4 FROM teams t WHERE t.team_name = $3
+		ON CONFLICT (user_id) DO UPDATE
+			SET username = EXCLUDED.username,
+				team_id = EXCLUDED.team_id,
+				updated_at = now()
+			WHERE users.team_id IS NULL
+		`,
+		member.UserID,
+		member.Name,
+		teamName,
+		member.IsActive,
+	)
+	if err != nil {
+		slog.Error("error inserting user", "error", err, "user_id", member.UserID)
+		return fmt.Errorf("insert user %s: %w", member.UserID, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		exists, err := r.TeamExists(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return entity.ErrTeamNotFound
+		}
+
+		return entity.NewError(entity.MemberExists, "user %s already belongs to a team", member.UserID)
+	}
+
+	return nil
+}
+
+// SetUserTeam переводит пользователя в команду teamName, при пустом teamName убирает из команды
+func (r *Repository) SetUserTeam(ctx context.Context, userID, teamName string) (*entity.User, error) {
+	var teamID *int
+	if teamName != "" {
+		var id int
+		err := r.db(ctx).QueryRow(ctx, "SELECT id FROM teams WHERE team_name = $1", teamName).Scan(&id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, entity.ErrTeamNotFound
+			}
+
+			slog.Error(fmt.Sprintf("error getting team: %v", err))
+			return nil, err
+		}
+
+		teamID = &id
+	}
+
+	tag, err := r.db(ctx).Exec(ctx, "UPDATE users SET team_id = $1, updated_at = now() WHERE user_id = $2", teamID, userID)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error updating user team: %v", err))
+		return nil, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return nil, entity.ErrUserNotFound
+	}
+
+	return r.GetUser(ctx, userID)
+}
+
+// RenameTeam переименовывает команду и ее записи в журнале состава; вызывается внутри WithinTx
+func (r *Repository) RenameTeam(ctx context.Context, teamName, newTeamName string) error {
+	tag, err := r.db(ctx).Exec(ctx, "UPDATE teams SET team_name = $1 WHERE team_name = $2", newTeamName, teamName)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entity.NewError(entity.TeamExists, "team with name %s already exists", newTeamName)
+		}
+
+		slog.Error(fmt.Sprintf("error renaming team: %v", err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrTeamNotFound
+	}
+
+	_, err = r.db(ctx).Exec(ctx, `
+		UPDATE team_membership_changes
+		SET from_team = CASE WHEN from_team = $2 THEN $1 ELSE from_team END,
+			to_team = CASE WHEN to_team = $2 THEN $1 ELSE to_team END
+		WHERE from_team = $2 OR to_team = $2
+		`, newTeamName, teamName)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error renaming team in membership changes: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// DeleteTeam удаляет команду без участников вместе с ее настройками
+func (r *Repository) DeleteTeam(ctx context.Context, teamName string) error {
+	tag, err := r.db(ctx).Exec(ctx, `
+		DELETE FROM teams t
+		WHERE t.team_name = $1
+			AND NOT EXISTS (SELECT 1 FROM users u WHERE u.team_id = t.id)
+		`, teamName)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error deleting team: %v", err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		exists, err := r.TeamExists(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return entity.ErrTeamNotFound
+		}
+
+		return entity.NewError(entity.TeamNotEmpty, "team %s still has members", teamName)
+	}
+
+	return nil
+}
+
+// AddMembershipChange записывает изменение состава команды в журнал
+func (r *Repository) AddMembershipChange(ctx context.Context, change *entity.MembershipChange) error {
+	err := r.db(ctx).QueryRow(ctx, `
+		INSERT INTO team_membership_changes (user_id, action, from_team, to_team)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''))
+		RETURNING id, changed_at
+		`,
+		change.UserID,
+		change.Action,
+		change.FromTeam,
+		change.ToTeam,
+	).Scan(&change.ID, &change.ChangedAt)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error inserting membership change: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// GetMembershipChanges получает журнал изменений состава команды, новые записи первыми
+func (r *Repository) GetMembershipChanges(ctx context.Context, teamName string) ([]*entity.MembershipChange, error) {
+	rows, err := r.db(ctx).Query(ctx, `
+		SELECT id, user_id, action, COALESCE(from_team, ''), COALESCE(to_team, ''), changed_at
+		FROM team_membership_changes
+		WHERE from_team = $1 OR to_team = $1
+		ORDER BY changed_at DESC, id DESC
+		`, teamName)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error getting membership changes: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	changes := make([]*entity.MembershipChange, 0)
+	for rows.Next() {
+		change := &entity.MembershipChange{}
+		if err := rows.Scan(&change.ID, &change.UserID, &change.Action, &change.FromTeam, &change.ToTeam, &change.ChangedAt); err != nil {
+			slog.Error(fmt.Sprintf("error scanning membership change: %v", err))
+			return nil, err
+		}
+
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error iterating rows", "error", err)
+		return nil, err
+	}
+
+	return changes, nil
+}
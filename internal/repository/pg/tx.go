@@ -0,0 +1,56 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"log/slog"
+)
+
+type txKey struct{}
+
+// querier общие методы пула и транзакции
+type querier interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// db возвращает транзакцию из контекста или пул соединений
+func (r *Repository) db(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+
+	return r.pg
+}
+
+// WithinTx выполняет fn в одной транзакции, вложенные вызовы используют внешнюю транзакцию.
+// Методы репозитория, открывающие собственную транзакцию, внутри fn работают через savepoint
+func (r *Repository) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := r.pg.Begin(ctx)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error starting transaction: %v", err))
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error(fmt.Sprintf("error committing transaction: %v", err))
+		return err
+	}
+
+	return nil
+}
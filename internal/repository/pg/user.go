@@ -12,11 +12,11 @@ import (
 // SetIsActive обновляет активность пользователя
 func (r *Repository) SetIsActive(ctx context.Context, userID string, isActive bool) (*entity.User, error) {
 	user := &entity.User{}
-	err := r.pg.QueryRow(ctx, `
+	err := r.db(ctx).QueryRow(ctx, `
 		UPDATE users SET is_active = $1, updated_at = now()
 		WHERE user_id = $2
 		RETURNING user_id, username, 
-			(SELECT team_name FROM teams WHERE id = users.team_id),
+			COALESCE((SELECT team_name FROM teams WHERE id = users.team_id), ''),
 			 is_active, created_at, updated_at
 		`,
 		isActive, userID).Scan(&user.UserID,
@@ -29,7 +29,7 @@ func (r *Repository) SetIsActive(ctx context.Context, userID string, isActive bo
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			slog.Error("user not found", "error", err, "user_id", userID)
-			return nil, fmt.Errorf("NOT_FOUND: user not found")
+			return nil, entity.ErrUserNotFound
 		}
 		slog.Error(fmt.Sprintf("error updating user: %v", err))
 		return nil, err
@@ -38,13 +38,13 @@ func (r *Repository) SetIsActive(ctx context.Context, userID string, isActive bo
 	return user, nil
 }
 
-// GetUser получает пользователя с именем команды
+// GetUser получает пользователя с именем команды; у пользователя вне команды оно пустое
 func (r *Repository) GetUser(ctx context.Context, userID string) (*entity.User, error) {
 	user := &entity.User{}
-	err := r.pg.QueryRow(ctx, `
-		SELECT u.user_id, u.username, t.team_name, u.is_active, u.created_at, u.updated_at
+	err := r.db(ctx).QueryRow(ctx, `
+		SELECT u.user_id, u.username, COALESCE(t.team_name, ''), u.is_active, u.created_at, u.updated_at
 		FROM users u 
-		JOIN teams t ON t.id = u.team_id
+		LEFT JOIN teams t ON t.id = u.team_id
         WHERE user_id = $1
 		`,
 		userID).Scan(&user.UserID,
@@ -54,6 +54,11 @@ func (r *Repository) GetUser(ctx context.Context, userID string) (*entity.User,
 		&user.CreatedAt,
 		&user.UpdatedAt)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Error("user not found", "user_id", userID)
+			return nil, entity.ErrUserNotFound
+		}
+
 		slog.Error(fmt.Sprintf("error getting user: %v", err))
 		return nil, err
 	}
@@ -61,15 +66,20 @@ func (r *Repository) GetUser(ctx context.Context, userID string) (*entity.User,
 	return user, nil
 }
 
-// GetActiveCandidates получает активных пользователей для назначения ревьюверов
+// GetActiveCandidates получает активных пользователей для назначения ревьюверов,
+// исключая тех, у кого сейчас идет отсутствие
 func (r *Repository) GetActiveCandidates(ctx context.Context, teamName string, excludeUserIDs []string) ([]*entity.User, error) {
-	rows, err := r.pg.Query(ctx, `
-			SELECT u.user_id, u.username, t.team_name, u.is_active 
+	rows, err := r.db(ctx).Query(ctx, `
+			SELECT u.user_id, u.username, t.team_name, u.is_active, u.created_at
 			FROM users u
 			JOIN teams t ON t.id = u.team_id
 			WHERE t.team_name = $1 
 				AND u.is_active = true
 				AND u.user_id != ALL($2)
+				AND NOT EXISTS (
+					SELECT 1 FROM user_absences a
+					WHERE a.user_id = u.user_id AND a.starts_at <= now() AND a.ends_at > now()
+				)
 		`,
 		teamName, excludeUserIDs)
 
@@ -83,7 +93,49 @@ func (r *Repository) GetActiveCandidates(ctx context.Context, teamName string, e
 	var users []*entity.User
 	for rows.Next() {
 		user := &entity.User{}
-		if err := rows.Scan(&user.UserID, &user.Name, &user.TeamName, &user.IsActive); err != nil {
+		if err := rows.Scan(&user.UserID, &user.Name, &user.TeamName, &user.IsActive, &user.CreatedAt); err != nil {
+			slog.Error(fmt.Sprintf("error scanning user row: %v", err))
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error(fmt.Sprintf("error scanning user rows: %v", err))
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// GetActiveUsers получает активных и не отсутствующих сейчас пользователей всех команд
+func (r *Repository) GetActiveUsers(ctx context.Context, excludeUserIDs []string) ([]*entity.User, error) {
+	rows, err := r.db(ctx).Query(ctx, `
+			SELECT u.user_id, u.username, t.team_name, u.is_active, u.created_at
+			FROM users u
+			JOIN teams t ON t.id = u.team_id
+			WHERE u.is_active = true
+				AND u.user_id != ALL($1)
+				AND NOT EXISTS (
+					SELECT 1 FROM user_absences a
+					WHERE a.user_id = u.user_id AND a.starts_at <= now() AND a.ends_at > now()
+				)
+			ORDER BY u.user_id
+		`,
+		excludeUserIDs)
+
+	if err != nil {
+		slog.Error(fmt.Sprintf("error getting active users: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var users []*entity.User
+	for rows.Next() {
+		user := &entity.User{}
+		if err := rows.Scan(&user.UserID, &user.Name, &user.TeamName, &user.IsActive, &user.CreatedAt); err != nil {
 			slog.Error(fmt.Sprintf("error scanning user row: %v", err))
 			return nil, err
 		}
@@ -101,7 +153,7 @@ func (r *Repository) GetActiveCandidates(ctx context.Context, teamName string, e
 
 // GetReview получает PR, где пользователь назначен ревьюером
 func (r *Repository) GetReview(ctx context.Context, userID string) ([]*entity.PullRequestShort, error) {
-	rows, err := r.pg.Query(ctx, `
+	rows, err := r.db(ctx).Query(ctx, `
 		SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status 
 		FROM pull_requests pr
 		JOIN pull_request_reviewers prr ON prr.pr_id = pr.pull_request_id
@@ -0,0 +1,330 @@
+package pg
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+const deliveryColumns = `d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+	d.next_attempt_at, COALESCE(d.last_error, ''), COALESCE(d.response_status, 0), d.created_at, d.delivered_at`
+
+// CreateWebhook сохраняет подписку на события
+func (r *Repository) CreateWebhook(ctx context.Context, hook *entity.Webhook) error {
+	err := r.db(ctx).QueryRow(ctx, `
+		INSERT INTO webhooks (url, team_id, events, secret, created_by)
+		SELECT $1, t.id, $3, $4, $5
+		FROM (SELECT NULLIF($2, '') AS team_name) AS h
+		LEFT JOIN teams t ON t.team_name = h.team_name
+		WHERE h.team_name IS NULL OR t.id IS NOT NULL
+		RETURNING id, created_at
+		`,
+		hook.URL,
+		hook.TeamName,
+		eventStrings(hook.Events),
+		hook.Secret,
+		hook.CreatedBy,
+	).Scan(&hook.ID, &hook.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.ErrTeamNotFound
+		}
+
+		slog.Error(fmt.Sprintf("error inserting webhook: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// GetWebhooks получает подписки команды или все подписки при пустом teamName
+func (r *Repository) GetWebhooks(ctx context.Context, teamName string) ([]*entity.Webhook, error) {
+	w := &where{}
+	if teamName != "" {
+		w.add("t.team_name = ?", teamName)
+	}
+
+	return r.queryWebhooks(ctx, w)
+}
+
+// GetSubscribedWebhooks получает подписки на событие eventType команды teamName, включая общие
+func (r *Repository) GetSubscribedWebhooks(ctx context.Context, teamName string, eventType entity.EventType) ([]*entity.Webhook, error) {
+	w := &where{}
+	w.add("? = ANY(h.events)", string(eventType))
+	w.add("(h.team_id IS NULL OR t.team_name = ?)", teamName)
+
+	return r.queryWebhooks(ctx, w)
+}
+
+func (r *Repository) queryWebhooks(ctx context.Context, w *where) ([]*entity.Webhook, error) {
+	rows, err := r.db(ctx).Query(ctx, `
+		SELECT h.id, h.url, COALESCE(t.team_name, ''), h.events, h.secret, h.created_by, h.created_at
+		FROM webhooks h
+		LEFT JOIN teams t ON t.id = h.team_id
+		`+w.String()+`
+		ORDER BY h.id`, w.args...)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error getting webhooks: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	hooks := make([]*entity.Webhook, 0)
+	for rows.Next() {
+		hook := &entity.Webhook{}
+		var events []string
+		if err := rows.Scan(&hook.ID, &hook.URL, &hook.TeamName, &events, &hook.Secret, &hook.CreatedBy, &hook.CreatedAt); err != nil {
+			slog.Error(fmt.Sprintf("error scanning webhook: %v", err))
+			return nil, err
+		}
+
+		for _, event := range events {
+			hook.Events = append(hook.Events, entity.EventType(event))
+		}
+
+		hooks = append(hooks, hook)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error iterating rows", "error", err)
+		return nil, err
+	}
+
+	return hooks, nil
+}
+
+// DeleteWebhook удаляет подписку вместе с журналом ее доставок; dead-letter записи остаются без delivery_id
+func (r *Repository) DeleteWebhook(ctx context.Context, id int64) error {
+	tag, err := r.db(ctx).Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error deleting webhook: %v", err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// AddWebhookDeliveries ставит доставки в очередь
+func (r *Repository) AddWebhookDeliveries(ctx context.Context, deliveries []*entity.WebhookDelivery) error {
+	batch := &pgx.Batch{}
+	for _, d := range deliveries {
+		batch.Queue(`
+			INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (webhook_id, event_id) DO NOTHING
+			`, d.WebhookID, d.EventID, d.EventType, d.Payload)
+	}
+
+	if err := r.db(ctx).SendBatch(ctx, batch).Close(); err != nil {
+		slog.Error(fmt.Sprintf("error inserting webhook deliveries: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// ClaimWebhookDeliveries забирает до limit доставок, срок попытки которых наступил, и откладывает
+// их на lease, чтобы параллельные обработчики не отправили их повторно
+func (r *Repository) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error) {
+	rows, err := r.db(ctx).Query(ctx, `
+		WITH due AS (
+			SELECT id FROM webhook_deliveries
+			WHERE status IN ('PENDING', 'RETRYING') AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		), d AS (
+			UPDATE webhook_deliveries w SET next_attempt_at = $2
+			FROM due
+			WHERE w.id = due.id
+			RETURNING w.*
+		)
+		SELECT `+deliveryColumns+`, h.url, h.secret
+		FROM d
+		JOIN webhooks h ON h.id = d.webhook_id
+		ORDER BY d.id
+		`, now, now.Add(lease), limit)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error claiming webhook deliveries: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	deliveries := make([]*entity.WebhookDelivery, 0)
+	for rows.Next() {
+		d := &entity.WebhookDelivery{}
+		if err := rows.Scan(append(deliveryFields(d), &d.URL, &d.Secret)...); err != nil {
+			slog.Error(fmt.Sprintf("error scanning webhook delivery: %v", err))
+			return nil, err
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error iterating rows", "error", err)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// UpdateWebhookDelivery сохраняет результат попытки доставки
+func (r *Repository) UpdateWebhookDelivery(ctx context.Context, d *entity.WebhookDelivery) error {
+	_, err := r.db(ctx).Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_error = NULLIF($5, ''),
+			response_status = NULLIF($6, 0), delivered_at = $7
+		WHERE id = $1
+		`, d.ID, d.Status, d.Attempts, d.NextAttemptAt, d.LastError, d.ResponseStatus, d.DeliveredAt)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error updating webhook delivery: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// AddDeadLetter переносит доставку, исчерпавшую попытки, в dead-letter таблицу
+func (r *Repository) AddDeadLetter(ctx context.Context, dl *entity.DeadLetter) error {
+	err := r.db(ctx).QueryRow(ctx, `
+		INSERT INTO webhook_dead_letters (delivery_id, webhook_id, event_id, event_type, payload, attempts, last_error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, failed_at
+		`, dl.DeliveryID, dl.WebhookID, dl.EventID, dl.EventType, dl.Payload, dl.Attempts, dl.LastError,
+	).Scan(&dl.ID, &dl.FailedAt)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error inserting dead letter: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// ListWebhookDeliveries получает журнал доставок от новых к старым, не больше filter.Limit
+func (r *Repository) ListWebhookDeliveries(ctx context.Context, filter entity.DeliveryFilter) ([]*entity.WebhookDelivery, error) {
+	w, err := deliveryWhere(filter, "d.")
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Status != "" {
+		w.add("d.status = ?", filter.Status)
+	}
+
+	rows, err := r.db(ctx).Query(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries d
+		`+w.String()+`
+		ORDER BY d.id DESC
+		`+w.limit(filter.Limit), w.args...)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error listing webhook deliveries: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	deliveries := make([]*entity.WebhookDelivery, 0)
+	for rows.Next() {
+		d := &entity.WebhookDelivery{}
+		if err := rows.Scan(deliveryFields(d)...); err != nil {
+			slog.Error(fmt.Sprintf("error scanning webhook delivery: %v", err))
+			return nil, err
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error iterating rows", "error", err)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// ListDeadLetters получает dead-letter записи от новых к старым, не больше filter.Limit
+func (r *Repository) ListDeadLetters(ctx context.Context, filter entity.DeliveryFilter) ([]*entity.DeadLetter, error) {
+	w, err := deliveryWhere(filter, "")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db(ctx).Query(ctx, `
+		SELECT id, delivery_id, webhook_id, event_id, event_type, payload, attempts, last_error, failed_at
+		FROM webhook_dead_letters
+		`+w.String()+`
+		ORDER BY id DESC
+		`+w.limit(filter.Limit), w.args...)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error listing dead letters: %v", err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	letters := make([]*entity.DeadLetter, 0)
+	for rows.Next() {
+		dl := &entity.DeadLetter{}
+		err := rows.Scan(&dl.ID, &dl.DeliveryID, &dl.WebhookID, &dl.EventID, &dl.EventType, &dl.Payload,
+			&dl.Attempts, &dl.LastError, &dl.FailedAt)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error scanning dead letter: %v", err))
+			return nil, err
+		}
+
+		letters = append(letters, dl)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error iterating rows", "error", err)
+		return nil, err
+	}
+
+	return letters, nil
+}
+
+// deliveryWhere условия по вебхуку и курсору (ID записи) с префиксом таблицы prefix
+func deliveryWhere(filter entity.DeliveryFilter, prefix string) (*where, error) {
+	w := &where{}
+	if filter.WebhookID != 0 {
+		w.add(prefix+"webhook_id = ?", filter.WebhookID)
+	}
+
+	if filter.After != nil {
+		id, err := strconv.ParseInt(filter.After.ID, 10, 64)
+		if err != nil {
+			return nil, entity.NewError(entity.InvalidRequest, "invalid cursor")
+		}
+
+		w.add(prefix+"id < ?", id)
+	}
+
+	return w, nil
+}
+
+func deliveryFields(d *entity.WebhookDelivery) []any {
+	return []any{&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.LastError, &d.ResponseStatus, &d.CreatedAt, &d.DeliveredAt}
+}
+
+func eventStrings(events []entity.EventType) []string {
+	result := make([]string, 0, len(events))
+	for _, event := range events {
+		result = append(result, string(event))
+	}
+
+	return result
+}
@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxAbsenceReasonLength = 255
+
+	// absenceSyncLock имя блокировки лидера синхронизации отсутствий
+	absenceSyncLock = "absence-sync"
+)
+
+// CreateAbsence добавляет период отсутствия с записью в журнал аудита
+func (uc *UseCase) CreateAbsence(ctx context.Context, absence *entity.Absence) (*entity.Absence, error) {
+	var created *entity.Absence
+	err := uc.audited(ctx, entity.AuditCreateAbsence, entity.AuditUser, absence.UserID, func(ctx context.Context) error {
+		var err error
+		created, err = uc.createAbsence(ctx, absence)
+		return err
+	})
+
+	return created, err
+}
+
+// createAbsence добавляет пользователю период отсутствия; пока он идет, пользователь
+// не выбирается ревьюером, даже если is_active еще не снят синхронизацией
+func (uc *UseCase) createAbsence(ctx context.Context, absence *entity.Absence) (*entity.Absence, error) {
+	absence.Reason = strings.TrimSpace(absence.Reason)
+	if err := validateAbsence(absence); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.repo.GetUser(ctx, absence.UserID); err != nil {
+		slog.Error("user not found", "error", err, "userID", absence.UserID)
+		return nil, err
+	}
+
+	if err := uc.repo.CreateAbsence(ctx, absence); err != nil {
+		slog.Error("failed to create absence", "error", err, "userID", absence.UserID)
+		return nil, err
+	}
+
+	slog.Info("absence created", "userID", absence.UserID, "absenceID", absence.ID)
+	return absence, nil
+}
+
+// GetAbsences получает периоды отсутствия пользователя
+func (uc *UseCase) GetAbsences(ctx context.Context, userID string) ([]*entity.Absence, error) {
+	if _, err := uc.repo.GetUser(ctx, userID); err != nil {
+		slog.Error("user not found", "error", err, "userID", userID)
+		return nil, err
+	}
+
+	absences, err := uc.repo.GetAbsences(ctx, userID)
+	if err != nil {
+		slog.Error("failed to get absences", "error", err, "userID", userID)
+		return nil, err
+	}
+
+	return absences, nil
+}
+
+// DeleteAbsence отменяет период отсутствия с записью в журнал аудита
+func (uc *UseCase) DeleteAbsence(ctx context.Context, id int64) error {
+	return uc.audited(ctx, entity.AuditDeleteAbsence, entity.AuditAbsence, strconv.FormatInt(id, 10), func(ctx context.Context) error {
+		return uc.deleteAbsence(ctx, id)
+	})
+}
+
+// deleteAbsence отменяет период отсутствия; если он уже начался, пользователь
+// возвращается в ротацию так же, как при его окончании
+func (uc *UseCase) deleteAbsence(ctx context.Context, id int64) error {
+	err := uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		absence, err := uc.repo.GetAbsence(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := uc.repo.DeleteAbsence(ctx, id); err != nil {
+			return err
+		}
+
+		if absence.AppliedAt == nil || absence.ReleasedAt != nil {
+			return nil
+		}
+
+		return uc.releaseUser(ctx, absence, time.Now())
+	})
+
+	if err != nil {
+		slog.Error("failed to delete absence", "error", err, "absenceID", id)
+		return err
+	}
+
+	slog.Info("absence deleted", "absenceID", id)
+	return nil
+}
+
+// SyncAbsences обрабатывает наступившие границы отсутствий: в начале снимает is_active
+// (и при reassign переназначает открытые ревью), в конце возвращает пользователя в ротацию.
+// Каждое отсутствие обрабатывается в своей транзакции; отсутствие, которое не удалось обработать,
+// пропускается и не останавливает проход. Проход выполняет только экземпляр, взявший блокировку
+// лидера; остальные получают пустой итог
+func (uc *UseCase) SyncAbsences(ctx context.Context, now time.Time, reassign bool) (entity.AbsenceSyncResult, error) {
+	var result entity.AbsenceSyncResult
+	leader, err := uc.repo.WithLeaderLock(ctx, absenceSyncLock, func(ctx context.Context) error {
+		due, err := uc.repo.GetDueAbsences(ctx, now)
+		if err != nil {
+			slog.Error("failed to get due absences", "error", err)
+			return err
+		}
+
+		for _, item := range due {
+			err := uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+				// перечитываем: обработка предыдущих отсутствий могла изменить Deactivated
+				absence, err := uc.repo.GetAbsence(ctx, item.ID)
+				if err != nil {
+					return err
+				}
+
+				if absence.Covers(now) {
+					return uc.applyAbsence(ctx, absence, now, reassign)
+				}
+
+				return uc.finishAbsence(ctx, absence, now)
+			})
+
+			if err != nil {
+				result.Failed++
+				slog.Error("failed to sync absence", "error", err, "absenceID", item.ID, "userID", item.UserID)
+				continue
+			}
+
+			result.Processed++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	if !leader {
+		slog.Debug("absences are synced by another instance")
+		return result, nil
+	}
+
+	if result.Processed > 0 || result.Failed > 0 {
+		slog.Info("absences synced", "processed", result.Processed, "failed", result.Failed)
+	}
+
+	return result, nil
+}
+
+// applyAbsence выводит пользователя из ротации в начале отсутствия
+func (uc *UseCase) applyAbsence(ctx context.Context, absence *entity.Absence, now time.Time, reassign bool) error {
+	user, err := uc.repo.GetUser(ctx, absence.UserID)
+	if err != nil {
+		return err
+	}
+
+	if user.IsActive {
+		if user, err = uc.repo.SetIsActive(ctx, user.UserID, false); err != nil {
+			return err
+		}
+
+		if err := uc.emitUser(ctx, entity.EventUserDeactivated, user, entity.ReasonAbsence); err != nil {
+			return err
+		}
+
+		absence.Deactivated = true
+	}
+
+	if reassign {
+		reassignments, err := uc.reassignOpenReviews(ctx, user, false, entity.ReasonAbsence)
+		if err != nil {
+			return err
+		}
+
+		slog.Info("reviews reassigned for absent user", "userID", user.UserID, "reassignments", len(reassignments))
+	}
+
+	absence.AppliedAt = &now
+	return uc.repo.UpdateAbsence(ctx, absence)
+}
+
+// finishAbsence возвращает пользователя в ротацию после окончания отсутствия
+func (uc *UseCase) finishAbsence(ctx context.Context, absence *entity.Absence, now time.Time) error {
+	if absence.AppliedAt != nil {
+		if err := uc.releaseUser(ctx, absence, now); err != nil {
+			return err
+		}
+	}
+
+	absence.ReleasedAt = &now
+	return uc.repo.UpdateAbsence(ctx, absence)
+}
+
+// releaseUser снова активирует пользователя, если is_active сняла синхронизация.
+// Если уже идет другое отсутствие, активация откладывается до его окончания
+func (uc *UseCase) releaseUser(ctx context.Context, absence *entity.Absence, now time.Time) error {
+	if !absence.Deactivated {
+		return nil
+	}
+
+	next, err := uc.repo.GetCurrentAbsence(ctx, absence.UserID, now)
+	if err != nil {
+		return err
+	}
+
+	if next != nil && next.ID != absence.ID {
+		next.Deactivated = true
+		return uc.repo.UpdateAbsence(ctx, next)
+	}
+
+	user, err := uc.repo.SetIsActive(ctx, absence.UserID, true)
+	if err != nil {
+		return err
+	}
+
+	return uc.emitUser(ctx, entity.EventUserActivated, user, entity.ReasonAbsence)
+}
+
+func validateAbsence(absence *entity.Absence) error {
+	if absence.UserID == "" {
+		return entity.NewError(entity.InvalidRequest, "user_id is required")
+	}
+
+	if absence.StartsAt.IsZero() || absence.EndsAt.IsZero() {
+		return entity.NewError(entity.InvalidRequest, "starts_at and ends_at are required")
+	}
+
+	if !absence.EndsAt.After(absence.StartsAt) {
+		return entity.NewError(entity.InvalidRequest, "ends_at must be after starts_at")
+	}
+
+	if !absence.EndsAt.After(time.Now()) {
+		return entity.NewError(entity.InvalidRequest, "ends_at must be in the future")
+	}
+
+	if len(absence.Reason) > maxAbsenceReasonLength {
+		return entity.NewError(entity.InvalidRequest, "reason must be at most %d characters", maxAbsenceReasonLength)
+	}
+
+	return nil
+}
@@ -0,0 +1,236 @@
+package usecase_test
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// userActive возвращает is_active участника команды backend
+func userActive(t *testing.T, ctx context.Context, uc *usecase.UseCase, userID string) bool {
+	t.Helper()
+
+	team, err := uc.GetTeam(ctx, "backend")
+	if err != nil {
+		t.Fatalf("GetTeam: %v", err)
+	}
+
+	for _, m := range team.Members {
+		if m.UserID == userID {
+			return m.IsActive
+		}
+	}
+
+	t.Fatalf("user %s is not a member of backend", userID)
+	return false
+}
+
+func TestCreateAbsenceValidation(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 2)
+
+	now := time.Now()
+	tests := []struct {
+		name    string
+		absence entity.Absence
+		want    entity.ErrorCode
+	}{
+		{"no user", entity.Absence{StartsAt: now, EndsAt: now.Add(time.Hour)}, entity.InvalidRequest},
+		{"no period", entity.Absence{UserID: "u1"}, entity.InvalidRequest},
+		{"ends before start", entity.Absence{UserID: "u1", StartsAt: now.Add(time.Hour), EndsAt: now}, entity.InvalidRequest},
+		{"already over", entity.Absence{UserID: "u1", StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)}, entity.InvalidRequest},
+		{"long reason", entity.Absence{UserID: "u1", StartsAt: now, EndsAt: now.Add(time.Hour), Reason: strings.Repeat("x", 256)}, entity.InvalidRequest},
+		{"unknown user", entity.Absence{UserID: "nobody", StartsAt: now, EndsAt: now.Add(time.Hour)}, entity.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.CreateAbsence(ctx, &tt.absence); entity.CodeOf(err) != tt.want {
+				t.Errorf("CreateAbsence error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+
+	created, err := uc.CreateAbsence(ctx, &entity.Absence{UserID: "u1", StartsAt: now, EndsAt: now.Add(time.Hour), Reason: "  vacation "})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	absences, err := uc.GetAbsences(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(absences) != 1 || absences[0].ID != created.ID || absences[0].Reason != "vacation" {
+		t.Errorf("absences = %+v, want the created one with trimmed reason", absences)
+	}
+}
+
+func TestSyncAbsencesAppliesAndReleases(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 4)
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reviewer := pr.AssignReviewers[0]
+	start := time.Now().Add(time.Minute)
+	if _, err := uc.CreateAbsence(ctx, &entity.Absence{UserID: reviewer, StartsAt: start, EndsAt: start.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if result, err := uc.SyncAbsences(ctx, time.Now(), true); err != nil || result.Processed != 0 {
+		t.Fatalf("sync before start = %+v, %v, want nothing to do", result, err)
+	}
+
+	if result, err := uc.SyncAbsences(ctx, start, true); err != nil || result.Processed != 1 {
+		t.Fatalf("sync at start = %+v, %v, want 1", result, err)
+	}
+
+	if userActive(t, ctx, uc, reviewer) {
+		t.Error("absent reviewer is still active")
+	}
+
+	if reviews, err := uc.GetUserReviews(ctx, reviewer); err != nil || len(reviews) != 0 {
+		t.Errorf("absent reviewer reviews = %+v, %v, want reassigned", reviews, err)
+	}
+
+	// повторная синхронизация не обрабатывает отсутствие второй раз
+	if result, err := uc.SyncAbsences(ctx, start.Add(time.Minute), true); err != nil || result.Processed != 0 {
+		t.Fatalf("repeated sync = %+v, %v, want nothing to do", result, err)
+	}
+
+	if result, err := uc.SyncAbsences(ctx, start.Add(time.Hour), true); err != nil || result.Processed != 1 {
+		t.Fatalf("sync at end = %+v, %v, want 1", result, err)
+	}
+
+	if !userActive(t, ctx, uc, reviewer) {
+		t.Error("reviewer was not returned to rotation")
+	}
+}
+
+func TestDeleteStartedAbsenceReturnsUserToRotation(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 2)
+
+	now := time.Now()
+	absence, err := uc.CreateAbsence(ctx, &entity.Absence{UserID: "u2", StartsAt: now, EndsAt: now.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.SyncAbsences(ctx, now, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if userActive(t, ctx, uc, "u2") {
+		t.Fatal("absent user is still active")
+	}
+
+	if err := uc.DeleteAbsence(ctx, absence.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if !userActive(t, ctx, uc, "u2") {
+		t.Error("user is not active after the absence was cancelled")
+	}
+
+	if err := uc.DeleteAbsence(ctx, absence.ID); entity.CodeOf(err) != entity.NotFound {
+		t.Errorf("second delete error = %v, want %s", err, entity.NotFound)
+	}
+}
+
+func TestManuallyDeactivatedUserStaysInactiveAfterAbsence(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 2)
+
+	if _, _, err := uc.SetIsActive(ctx, "u2", false); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	if _, err := uc.CreateAbsence(ctx, &entity.Absence{UserID: "u2", StartsAt: now, EndsAt: now.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, at := range []time.Time{now, now.Add(time.Hour)} {
+		if _, err := uc.SyncAbsences(ctx, at, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if userActive(t, ctx, uc, "u2") {
+		t.Error("absence sync activated a manually deactivated user")
+	}
+}
+
+// failingAbsenceRepo хранилище, в котором не удается сохранить отсутствие failID
+type failingAbsenceRepo struct {
+	*memory.Repository
+	failID int64
+}
+
+func (r *failingAbsenceRepo) UpdateAbsence(ctx context.Context, absence *entity.Absence) error {
+	if absence.ID == r.failID {
+		return errors.New("update absence: connection reset")
+	}
+
+	return r.Repository.UpdateAbsence(ctx, absence)
+}
+
+func TestSyncAbsencesContinuesAfterFailure(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	repo := &failingAbsenceRepo{Repository: memory.New()}
+	uc := usecase.New(repo)
+	newTeam(t, ctx, uc, 3)
+
+	now := time.Now()
+	first, err := uc.CreateAbsence(ctx, &entity.Absence{UserID: "u2", StartsAt: now, EndsAt: now.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.CreateAbsence(ctx, &entity.Absence{UserID: "u3", StartsAt: now, EndsAt: now.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	repo.failID = first.ID
+	result, err := uc.SyncAbsences(ctx, now, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Processed != 1 || result.Failed != 1 {
+		t.Errorf("result = %+v, want 1 processed and 1 failed", result)
+	}
+
+	// неудачное отсутствие откатывается целиком, следующее применяется
+	if !userActive(t, ctx, uc, "u2") {
+		t.Error("user of the failed absence was deactivated")
+	}
+
+	if userActive(t, ctx, uc, "u3") {
+		t.Error("absence after the failed one was not applied")
+	}
+
+	// неудачное отсутствие повторяется следующим проходом
+	repo.failID = 0
+	if result, err := uc.SyncAbsences(ctx, now.Add(time.Minute), false); err != nil || result.Processed != 1 || result.Failed != 0 {
+		t.Errorf("retry = %+v, %v, want the failed absence processed", result, err)
+	}
+
+	if userActive(t, ctx, uc, "u2") {
+		t.Error("failed absence was not applied on retry")
+	}
+}
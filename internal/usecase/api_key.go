@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	apiKeySecretBytes   = 32
+	apiKeyPrefixLength  = 12
+	maxAPIKeyNameLength = 100
+)
+
+// CreateAPIKey создает API-ключ с правами scopes и возвращает его вместе с самим ключом,
+// который больше нигде не отдается: в хранилище остается только его хэш
+func (uc *UseCase) CreateAPIKey(ctx context.Context, name string, scopes []entity.Permission, expiresAt *time.Time) (*entity.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if err := validateAPIKey(name, scopes, expiresAt); err != nil {
+		return nil, "", err
+	}
+
+	raw, err := generateAPIKey()
+	if err != nil {
+		slog.Error("failed to generate api key", "error", err)
+		return nil, "", err
+	}
+
+	key := &entity.APIKey{
+		Name:      name,
+		Prefix:    raw[:apiKeyPrefixLength],
+		Hash:      hashAPIKey(raw),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedBy: entity.ActorFromContext(ctx).ID,
+	}
+
+	err = uc.audited(ctx, entity.AuditCreateAPIKey, entity.AuditAPIKey, name, func(ctx context.Context) error {
+		return uc.repo.CreateAPIKey(ctx, key)
+	})
+
+	if err != nil {
+		slog.Error("failed to create api key", "error", err, "name", name)
+		return nil, "", err
+	}
+
+	slog.Info("api key created", "name", name, "prefix", key.Prefix, "scopes", scopes)
+	return key, raw, nil
+}
+
+// ListAPIKeys получает все API-ключи без их значений
+func (uc *UseCase) ListAPIKeys(ctx context.Context) ([]*entity.APIKey, error) {
+	keys, err := uc.repo.ListAPIKeys(ctx)
+	if err != nil {
+		slog.Error("failed to list api keys", "error", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey отзывает API-ключ с записью в журнал аудита
+func (uc *UseCase) RevokeAPIKey(ctx context.Context, id int64) (*entity.APIKey, error) {
+	var key *entity.APIKey
+	err := uc.audited(ctx, entity.AuditRevokeAPIKey, entity.AuditAPIKey, strconv.FormatInt(id, 10), func(ctx context.Context) error {
+		var err error
+		key, err = uc.repo.RevokeAPIKey(ctx, id, time.Now())
+		return err
+	})
+
+	if err != nil {
+		slog.Error("failed to revoke api key", "error", err, "apiKeyID", id)
+		return nil, err
+	}
+
+	slog.Info("api key revoked", "name", key.Name)
+	return key, nil
+}
+
+// AuthenticateAPIKey находит действующий ключ и возвращает инициатора с его правами
+func (uc *UseCase) AuthenticateAPIKey(ctx context.Context, raw string) (entity.Actor, error) {
+	key, err := uc.repo.GetAPIKeyByHash(ctx, hashAPIKey(raw))
+	if err != nil {
+		if errors.Is(err, entity.NotFound) {
+			return entity.Actor{}, entity.NewError(entity.Unauthorized, "invalid api key")
+		}
+
+		return entity.Actor{}, err
+	}
+
+	now := time.Now()
+	if !key.IsUsable(now) {
+		return entity.Actor{}, entity.NewError(entity.Unauthorized, "api key is revoked or expired")
+	}
+
+	if err := uc.repo.TouchAPIKey(ctx, key.ID, now); err != nil {
+		slog.Error("failed to update api key last use", "error", err, "name", key.Name)
+	}
+
+	return entity.Actor{
+		ID:     key.ActorID(),
+		Roles:  []string{entity.RoleAPIKey},
+		Scopes: key.Scopes,
+	}, nil
+}
+
+func validateAPIKey(name string, scopes []entity.Permission, expiresAt *time.Time) error {
+	if name == "" || len(name) > maxAPIKeyNameLength {
+		return entity.NewError(entity.InvalidRequest, "name is required and must be at most %d characters", maxAPIKeyNameLength)
+	}
+
+	if len(scopes) == 0 {
+		return entity.NewError(entity.InvalidRequest, "at least one scope is required")
+	}
+
+	for i, scope := range scopes {
+		if !slices.Contains(entity.Permissions, scope) {
+			return entity.NewError(entity.InvalidRequest, "unknown scope %s", scope)
+		}
+
+		if slices.Contains(scopes[:i], scope) {
+			return entity.NewError(entity.InvalidRequest, "duplicate scope %s", scope)
+		}
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return entity.NewError(entity.InvalidRequest, "expires_at must be in the future")
+	}
+
+	return nil
+}
+
+func generateAPIKey() (string, error) {
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("read random: %w", err)
+	}
+
+	return entity.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// hashAPIKey SHA-256 ключа; ключи случайные и длинные, поэтому медленный KDF не нужен
+func hashAPIKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
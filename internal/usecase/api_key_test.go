@@ -0,0 +1,231 @@
+package usecase_test
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+
+	past := time.Now().Add(-time.Hour)
+	invalid := []struct {
+		name      string
+		keyName   string
+		scopes    []entity.Permission
+		expiresAt *time.Time
+	}{
+		{"no name", " ", []entity.Permission{entity.PermPRRead}, nil},
+		{"long name", strings.Repeat("k", 101), []entity.Permission{entity.PermPRRead}, nil},
+		{"no scopes", "ci", nil, nil},
+		{"unknown scope", "ci", []entity.Permission{"pr:delete"}, nil},
+		{"duplicate scope", "ci", []entity.Permission{entity.PermPRRead, entity.PermPRRead}, nil},
+		{"expired", "ci", []entity.Permission{entity.PermPRRead}, &past},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := uc.CreateAPIKey(ctx, tt.keyName, tt.scopes, tt.expiresAt); entity.CodeOf(err) != entity.InvalidRequest {
+				t.Errorf("CreateAPIKey error = %v, want %s", err, entity.InvalidRequest)
+			}
+		})
+	}
+
+	key, raw, err := uc.CreateAPIKey(ctx, " ci ", []entity.Permission{entity.PermPRRead, entity.PermPRCreate}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(raw, entity.APIKeyPrefix) || key.Prefix != raw[:len(key.Prefix)] || key.Name != "ci" {
+		t.Errorf("key = %+v, raw = %q", key, raw)
+	}
+
+	if strings.Contains(key.Hash, raw) {
+		t.Error("key hash contains the raw key")
+	}
+
+	keys, err := uc.ListAPIKeys(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != 1 || keys[0].ID != key.ID {
+		t.Errorf("keys = %+v, want the created one", keys)
+	}
+
+	actor, err := uc.AuthenticateAPIKey(ctx, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !actor.IsAPIKey() || !slices.Equal(actor.Scopes, key.Scopes) {
+		t.Errorf("actor = %+v, want api key with scopes %v", actor, key.Scopes)
+	}
+
+	if _, err := uc.AuthenticateAPIKey(ctx, raw+"x"); entity.CodeOf(err) != entity.Unauthorized {
+		t.Errorf("unknown key error = %v, want %s", err, entity.Unauthorized)
+	}
+}
+
+func TestAPIKeyScopesAreEnforced(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 2)
+
+	_, raw, err := uc.CreateAPIKey(ctx, "ci", []entity.Permission{entity.PermPRCreate}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actor, err := uc.AuthenticateAPIKey(ctx, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := uc.Authorize(ctx, actor, entity.PermPRCreate, entity.AuthTarget{Teams: []string{"backend"}}); err != nil {
+		t.Errorf("scoped permission: %v", err)
+	}
+
+	// привязки ролей на ID ключа не действуют: ключу доступны только его scopes
+	if _, err := uc.BindRole(ctx, &entity.RoleBinding{UserID: actor.ID, Role: entity.RoleAdmin}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, perm := range []entity.Permission{entity.PermPRMerge, entity.PermTeamManage, entity.PermAPIKeyManage} {
+		if err := uc.Authorize(ctx, actor, perm, entity.AuthTarget{Teams: []string{"backend"}}); entity.CodeOf(err) != entity.Forbidden {
+			t.Errorf("unscoped %s error = %v, want %s", perm, err, entity.Forbidden)
+		}
+	}
+}
+
+func TestRevokedAPIKey(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+
+	key, raw, err := uc.CreateAPIKey(ctx, "ci", []entity.Permission{entity.PermPRRead}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := uc.CreateAPIKey(ctx, "ci", []entity.Permission{entity.PermPRRead}, nil); entity.CodeOf(err) != entity.APIKeyExists {
+		t.Errorf("duplicate active name error = %v, want %s", err, entity.APIKeyExists)
+	}
+
+	revoked, err := uc.RevokeAPIKey(ctx, key.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if revoked.RevokedAt == nil {
+		t.Fatalf("revoked key = %+v, want revoked_at", revoked)
+	}
+
+	if _, err := uc.AuthenticateAPIKey(ctx, raw); entity.CodeOf(err) != entity.Unauthorized {
+		t.Errorf("revoked key error = %v, want %s", err, entity.Unauthorized)
+	}
+
+	again, err := uc.RevokeAPIKey(ctx, key.ID)
+	if err != nil || !again.RevokedAt.Equal(*revoked.RevokedAt) {
+		t.Errorf("repeated revoke = %+v, %v, want the original revoked_at", again, err)
+	}
+
+	if _, err := uc.RevokeAPIKey(ctx, key.ID+100); entity.CodeOf(err) != entity.NotFound {
+		t.Errorf("unknown key revoke error = %v, want %s", err, entity.NotFound)
+	}
+
+	// имя отозванного ключа можно занять, а инициатор в журналах различает ключи по ID
+	reissued, reissuedRaw, err := uc.CreateAPIKey(ctx, "ci", []entity.Permission{entity.PermPRRead}, nil)
+	if err != nil {
+		t.Fatalf("reissue after revoke: %v", err)
+	}
+
+	actor, err := uc.AuthenticateAPIKey(ctx, reissuedRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actor.ID != reissued.ActorID() || actor.ID == key.ActorID() {
+		t.Errorf("actor = %s, want %s distinct from revoked %s", actor.ID, reissued.ActorID(), key.ActorID())
+	}
+}
+
+func TestAPIKeyExpires(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+
+	expiresAt := time.Now().Add(50 * time.Millisecond)
+	_, raw, err := uc.CreateAPIKey(ctx, "ci", []entity.Permission{entity.PermPRRead}, &expiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.AuthenticateAPIKey(ctx, raw); err != nil {
+		t.Fatalf("key before expiry: %v", err)
+	}
+
+	time.Sleep(time.Until(expiresAt))
+	if _, err := uc.AuthenticateAPIKey(ctx, raw); entity.CodeOf(err) != entity.Unauthorized {
+		t.Errorf("expired key error = %v, want %s", err, entity.Unauthorized)
+	}
+}
+
+func TestAPIKeyLastUseIsThrottled(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	repo := memory.New()
+	uc := usecase.New(repo)
+
+	key, raw, err := uc.CreateAPIKey(ctx, "ci", []entity.Permission{entity.PermPRRead}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.AuthenticateAPIKey(ctx, raw); err != nil {
+		t.Fatal(err)
+	}
+
+	lastUsed := func() time.Time {
+		t.Helper()
+
+		keys, err := uc.ListAPIKeys(ctx)
+		if err != nil || len(keys) != 1 || keys[0].LastUsedAt == nil {
+			t.Fatalf("keys = %+v, %v, want one used key", keys, err)
+		}
+
+		return *keys[0].LastUsedAt
+	}
+
+	first := lastUsed()
+
+	// повторное использование в пределах минуты last_used_at не меняет
+	if _, err := uc.AuthenticateAPIKey(ctx, raw); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := lastUsed(); !got.Equal(first) {
+		t.Errorf("last_used_at = %s after a second use, want %s", got, first)
+	}
+
+	if err := repo.TouchAPIKey(ctx, key.ID, first.Add(30*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := lastUsed(); !got.Equal(first) {
+		t.Errorf("last_used_at = %s after 30s, want %s", got, first)
+	}
+
+	later := first.Add(2 * time.Minute)
+	if err := repo.TouchAPIKey(ctx, key.ID, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := lastUsed(); !got.Equal(later) {
+		t.Errorf("last_used_at = %s after 2m, want %s", got, later)
+	}
+}
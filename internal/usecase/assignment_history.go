@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"log/slog"
+)
+
+// GetAssignmentHistory получает историю назначений ревьюеров по PR и/или пользователю
+func (uc *UseCase) GetAssignmentHistory(ctx context.Context, filter entity.AssignmentHistoryFilter) ([]*entity.AssignmentHistory, error) {
+	if filter.PullRequestID == "" && filter.UserID == "" {
+		return nil, entity.NewError(entity.InvalidRequest, "pull_request_id or user_id is required")
+	}
+
+	history, err := uc.repo.GetAssignmentHistory(ctx, filter)
+	if err != nil {
+		slog.Error("failed to get assignment history", "error", err)
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// recordAssignments пишет в историю снятие ревьюеров unassigned и назначение assigned
+// с общей причиной и публикует их для вебхуков; инициатор берется из контекста
+func (uc *UseCase) recordAssignments(ctx context.Context, prID string, reason entity.AssignmentReason, assigned, unassigned []string) error {
+	actor := entity.ActorFromContext(ctx)
+	entries := make([]*entity.AssignmentHistory, 0, len(assigned)+len(unassigned))
+
+	for _, userID := range unassigned {
+		entries = append(entries, &entity.AssignmentHistory{
+			PullRequestID: prID,
+			UserID:        userID,
+			Event:         entity.ReviewerUnassigned,
+			Reason:        reason,
+			Actor:         actor.ID,
+		})
+	}
+
+	for _, userID := range assigned {
+		entries = append(entries, &entity.AssignmentHistory{
+			PullRequestID: prID,
+			UserID:        userID,
+			Event:         entity.ReviewerAssigned,
+			Reason:        reason,
+			Actor:         actor.ID,
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if err := uc.repo.AddAssignmentHistory(ctx, entries); err != nil {
+		slog.Error("failed to record assignment history", "error", err, "prID", prID)
+		return err
+	}
+
+	return uc.emitAssignments(ctx, prID, reason, assigned, unassigned)
+}
+
+// recordReplacement пишет в историю замену ревьюера oldID на newID; запись о назначении
+// ссылается на снятого ревьюера, по этим записям строятся переназначения PR и статистика
+func (uc *UseCase) recordReplacement(ctx context.Context, prID string, reason entity.AssignmentReason, oldID, newID string) error {
+	actor := entity.ActorFromContext(ctx)
+	entries := []*entity.AssignmentHistory{
+		{
+			PullRequestID: prID,
+			UserID:        oldID,
+			Event:         entity.ReviewerUnassigned,
+			Reason:        reason,
+			Actor:         actor.ID,
+		},
+		{
+			PullRequestID:  prID,
+			UserID:         newID,
+			Event:          entity.ReviewerAssigned,
+			Reason:         reason,
+			Actor:          actor.ID,
+			ReplacedUserID: oldID,
+		},
+	}
+
+	if err := uc.repo.AddAssignmentHistory(ctx, entries); err != nil {
+		slog.Error("failed to record reviewer replacement", "error", err, "prID", prID)
+		return err
+	}
+
+	return uc.emitAssignments(ctx, prID, reason, []string{newID}, []string{oldID})
+}
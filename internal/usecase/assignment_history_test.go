@@ -0,0 +1,89 @@
+package usecase_test
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"testing"
+)
+
+func TestReassignmentsAreDerivedFromAssignmentHistory(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 5)
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := pr.AssignReviewers[0]
+	_, replacedBy, err := uc.ReassignReviewer(ctx, "pr1", old, entity.ReasonManualReassign)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := uc.GetAssignmentHistory(ctx, entity.AssignmentHistoryFilter{PullRequestID: "pr1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var replacements []*entity.AssignmentHistory
+	for _, h := range history {
+		if h.ReplacedUserID != "" {
+			replacements = append(replacements, h)
+		}
+	}
+
+	if len(replacements) != 1 {
+		t.Fatalf("history has %d replacements, want 1: %+v", len(replacements), history)
+	}
+
+	if r := replacements[0]; r.Event != entity.ReviewerAssigned || r.UserID != replacedBy || r.ReplacedUserID != old {
+		t.Errorf("replacement entry = %+v, want %s assigned instead of %s", r, replacedBy, old)
+	}
+
+	details, err := uc.GetPRDetails(ctx, "pr1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(details.Reassignments) != 1 {
+		t.Fatalf("reassignments = %+v, want one", details.Reassignments)
+	}
+
+	if ra := details.Reassignments[0]; ra.OldReviewerID != old || ra.NewReviewerID != replacedBy ||
+		!ra.ReassignedAt.Equal(replacements[0].CreatedAt) {
+		t.Errorf("reassignment = %+v, want history entry %+v", ra, replacements[0])
+	}
+
+	stats, err := uc.GetReviewerStats(ctx, entity.StatsFilter{TeamName: "backend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range stats {
+		wantIn, wantOut := 0, 0
+		switch s.UserID {
+		case replacedBy:
+			wantIn = 1
+		case old:
+			wantOut = 1
+		}
+
+		if s.ReassignedIn != wantIn || s.ReassignedOut != wantOut {
+			t.Errorf("stats of %s: reassigned in %d, out %d, want %d, %d",
+				s.UserID, s.ReassignedIn, s.ReassignedOut, wantIn, wantOut)
+		}
+	}
+
+	teams, err := uc.GetTeamStats(ctx, entity.StatsFilter{TeamName: "backend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(teams) != 1 || teams[0].Reassignments != 1 {
+		t.Errorf("team stats = %+v, want 1 reassignment", teams)
+	}
+}
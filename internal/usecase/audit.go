@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// ListAuditEntries получает журнал аудита по фильтру, от новых записей к старым
+func (uc *UseCase) ListAuditEntries(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, string, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, "", entity.NewError(entity.InvalidRequest, "from must be before to")
+	}
+
+	if filter.EntityID != "" && filter.EntityType == "" {
+		return nil, "", entity.NewError(entity.InvalidRequest, "entity_type is required with entity_id")
+	}
+
+	limit, err := pageLimit(&filter.Page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	entries, err := uc.repo.ListAuditEntries(ctx, filter)
+	if err != nil {
+		slog.Error("failed to list audit entries", "error", err)
+		return nil, "", err
+	}
+
+	if len(entries) <= limit {
+		return entries, "", nil
+	}
+
+	entries = entries[:limit]
+	return entries, entity.Cursor{ID: strconv.FormatInt(entries[limit-1].ID, 10)}.Encode(), nil
+}
+
+// audited выполняет действие в транзакции и в ней же записывает его в журнал аудита.
+// Неудачное действие записывается уже после отката, вместе с кодом ошибки
+func (uc *UseCase) audited(ctx context.Context, action entity.AuditAction, entityType entity.AuditEntityType, entityID string, fn func(ctx context.Context) error) error {
+	err := uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+
+		return uc.repo.AddAuditEntry(ctx, newAuditEntry(ctx, action, entityType, entityID, nil))
+	})
+
+	if err != nil {
+		entry := newAuditEntry(ctx, action, entityType, entityID, err)
+		if auditErr := uc.repo.AddAuditEntry(ctx, entry); auditErr != nil {
+			slog.Error("failed to record audit entry", "error", auditErr, "action", action)
+		}
+	}
+
+	return err
+}
+
+func newAuditEntry(ctx context.Context, action entity.AuditAction, entityType entity.AuditEntityType, entityID string, err error) *entity.AuditEntry {
+	actor := entity.ActorFromContext(ctx)
+	info := entity.RequestInfoFromContext(ctx)
+	entry := &entity.AuditEntry{
+		Actor:       actor.ID,
+		Role:        actor.Role(),
+		Action:      action,
+		EntityType:  entityType,
+		EntityID:    entityID,
+		Endpoint:    info.Endpoint,
+		PayloadHash: info.PayloadHash,
+		Outcome:     entity.AuditSuccess,
+		CreatedAt:   time.Now(),
+	}
+
+	if err != nil {
+		entry.Outcome = entity.AuditFailure
+		entry.ErrorCode = entity.CodeOf(err)
+	}
+
+	return entry
+}
+
+// auditedPR выполняет действие над PR, возвращающее его новое состояние, с записью в журнал аудита
+func (uc *UseCase) auditedPR(ctx context.Context, action entity.AuditAction, prID string, fn func(ctx context.Context, prID string) (*entity.PullRequest, error)) (*entity.PullRequest, error) {
+	var pr *entity.PullRequest
+	err := uc.audited(ctx, action, entity.AuditPullRequest, prID, func(ctx context.Context) error {
+		var err error
+		pr, err = fn(ctx, prID)
+		return err
+	})
+
+	return pr, err
+}
@@ -0,0 +1,171 @@
+package usecase_test
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAuditRecordsOutcomeAndRequest(t *testing.T) {
+	ctx := entity.WithRequestInfo(
+		entity.WithActor(context.Background(), entity.Actor{ID: "admin", Roles: []string{entity.RoleAdmin}}),
+		entity.RequestInfo{Endpoint: "POST /team/add", PayloadHash: "abc"},
+	)
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 2)
+
+	if _, err := uc.CreateTeam(ctx, &entity.Team{Name: "backend", Members: []*entity.TeamMember{{UserID: "u9", Name: "Zed", IsActive: true}}}); err == nil {
+		t.Fatal("duplicate team was created")
+	}
+
+	entries, _, err := uc.ListAuditEntries(ctx, entity.AuditFilter{EntityType: entity.AuditTeam, EntityID: "backend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("audit entries = %+v, want success and failure", entries)
+	}
+
+	// от новых к старым
+	failure, success := entries[0], entries[1]
+	if failure.Outcome != entity.AuditFailure || failure.ErrorCode != entity.TeamExists {
+		t.Errorf("failed action entry = %+v", failure)
+	}
+
+	if success.Outcome != entity.AuditSuccess || success.Action != entity.AuditCreateTeam || success.Actor != "admin" ||
+		success.Role != entity.RoleAdmin || success.Endpoint != "POST /team/add" || success.PayloadHash != "abc" {
+		t.Errorf("successful action entry = %+v", success)
+	}
+
+	// пробный запуск в журнал не попадает
+	if _, err := uc.DeactivateUsers(ctx, "backend", nil, true); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _, err = uc.ListAuditEntries(ctx, entity.AuditFilter{Actor: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range entries {
+		if e.Action == entity.AuditDeactivateUsers {
+			t.Errorf("dry run was audited: %+v", e)
+		}
+	}
+
+	if entries, _, err := uc.ListAuditEntries(ctx, entity.AuditFilter{Actor: "someone"}); err != nil || len(entries) != 0 {
+		t.Errorf("entries of another actor = %+v, %v, want none", entries, err)
+	}
+}
+
+func TestListAuditEntriesValidation(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+
+	now := time.Now()
+	invalid := []struct {
+		name   string
+		filter entity.AuditFilter
+	}{
+		{"empty range", entity.AuditFilter{From: &now, To: &now}},
+		{"entity id without type", entity.AuditFilter{EntityID: "backend"}},
+		{"negative limit", entity.AuditFilter{Page: entity.Page{Limit: -1}}},
+		{"limit above max", entity.AuditFilter{Page: entity.Page{Limit: entity.MaxPageLimit + 1}}},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := uc.ListAuditEntries(ctx, tt.filter); entity.CodeOf(err) != entity.InvalidRequest {
+				t.Errorf("ListAuditEntries error = %v, want %s", err, entity.InvalidRequest)
+			}
+		})
+	}
+}
+
+// failingAuditRepo хранилище, в котором не удается записать успешное действие в журнал аудита
+type failingAuditRepo struct {
+	*memory.Repository
+}
+
+func (r *failingAuditRepo) AddAuditEntry(ctx context.Context, e *entity.AuditEntry) error {
+	if e.Outcome == entity.AuditSuccess {
+		return errors.New("add audit entry: connection reset")
+	}
+
+	return r.Repository.AddAuditEntry(ctx, e)
+}
+
+func TestAuditFailureRollsBackAction(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(&failingAuditRepo{Repository: memory.New()})
+
+	team := &entity.Team{Name: "backend", Members: []*entity.TeamMember{{UserID: "u1", Name: "user u1", IsActive: true}}}
+	if _, err := uc.CreateTeam(ctx, team); err == nil {
+		t.Fatal("team was created without an audit entry")
+	}
+
+	if _, err := uc.GetTeam(ctx, "backend"); entity.CodeOf(err) != entity.NotFound {
+		t.Errorf("unaudited team lookup error = %v, want %s", err, entity.NotFound)
+	}
+
+	entries, _, err := uc.ListAuditEntries(ctx, entity.AuditFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 || entries[0].Outcome != entity.AuditFailure || entries[0].ErrorCode != entity.InternalServer {
+		t.Errorf("audit entries = %+v, want one failure", entries)
+	}
+}
+
+func TestAuditTrailIsAppendOnly(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 3)
+
+	first, _, err := uc.ListAuditEntries(ctx, entity.AuditFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(first) != 1 {
+		t.Fatalf("audit entries = %+v, want team creation", first)
+	}
+
+	created := *first[0]
+	first[0].Outcome = entity.AuditFailure
+	first[0].Actor = "intruder"
+
+	if _, _, err := uc.SetIsActive(ctx, "u3", false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _, err := uc.ListAuditEntries(ctx, entity.AuditFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 3 {
+		t.Fatalf("audit entries = %+v, want 3", entries)
+	}
+
+	// записи только добавляются: номера растут, а прежние записи не меняются
+	for i := 1; i < len(entries); i++ {
+		if entries[i-1].ID <= entries[i].ID {
+			t.Errorf("entry %d follows entry %d", entries[i-1].ID, entries[i].ID)
+		}
+	}
+
+	if oldest := *entries[len(entries)-1]; oldest != created {
+		t.Errorf("oldest entry = %+v, want unchanged %+v", oldest, created)
+	}
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"log/slog"
+)
+
+// ListTeams получает страницу команд и курсор следующей страницы (пустой на последней)
+func (uc *UseCase) ListTeams(ctx context.Context, filter entity.TeamFilter) ([]*entity.TeamSummary, string, error) {
+	limit, err := pageLimit(&filter.Page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	teams, err := uc.repo.ListTeams(ctx, filter)
+	if err != nil {
+		slog.Error("failed to list teams", "error", err)
+		return nil, "", err
+	}
+
+	if len(teams) <= limit {
+		return teams, "", nil
+	}
+
+	teams = teams[:limit]
+	return teams, entity.Cursor{ID: teams[limit-1].Name}.Encode(), nil
+}
+
+// ListUsers получает страницу пользователей и курсор следующей страницы
+func (uc *UseCase) ListUsers(ctx context.Context, filter entity.UserFilter) ([]*entity.User, string, error) {
+	limit, err := pageLimit(&filter.Page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	users, err := uc.repo.ListUsers(ctx, filter)
+	if err != nil {
+		slog.Error("failed to list users", "error", err)
+		return nil, "", err
+	}
+
+	if len(users) <= limit {
+		return users, "", nil
+	}
+
+	users = users[:limit]
+	return users, entity.Cursor{ID: users[limit-1].UserID}.Encode(), nil
+}
+
+// ListPRs получает страницу PR и курсор следующей страницы
+func (uc *UseCase) ListPRs(ctx context.Context, filter entity.PRFilter) ([]*entity.PullRequest, string, error) {
+	if err := validatePRFilter(filter); err != nil {
+		return nil, "", err
+	}
+
+	limit, err := pageLimit(&filter.Page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	prs, err := uc.repo.ListPRs(ctx, filter)
+	if err != nil {
+		slog.Error("failed to list pull requests", "error", err)
+		return nil, "", err
+	}
+
+	if len(prs) <= limit {
+		return prs, "", nil
+	}
+
+	prs = prs[:limit]
+	last := prs[limit-1]
+	return prs, entity.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode(), nil
+}
+
+// pageLimit проверяет размер страницы и запрашивает у репозитория на один элемент больше,
+// чтобы понять, есть ли следующая страница
+func pageLimit(page *entity.Page) (int, error) {
+	if page.Limit == 0 {
+		page.Limit = entity.DefaultPageLimit
+	}
+
+	if page.Limit < 0 || page.Limit > entity.MaxPageLimit {
+		return 0, entity.NewError(entity.InvalidRequest, "limit must be between 1 and %d", entity.MaxPageLimit)
+	}
+
+	limit := page.Limit
+	page.Limit++
+	return limit, nil
+}
+
+func validatePRFilter(filter entity.PRFilter) error {
+	if filter.Status != "" {
+		if _, ok := prTransitions[filter.Status]; !ok {
+			return entity.NewError(entity.InvalidRequest, "unknown status %s", filter.Status)
+		}
+	}
+
+	if filter.After != nil && filter.After.CreatedAt == nil {
+		return entity.NewError(entity.InvalidRequest, "invalid cursor")
+	}
+
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return entity.NewError(entity.InvalidRequest, "created_from must be before created_to")
+	}
+
+	if filter.MergedFrom != nil && filter.MergedTo != nil && !filter.MergedFrom.Before(*filter.MergedTo) {
+		return entity.NewError(entity.InvalidRequest, "merged_from must be before merged_to")
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package usecase_test
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestListFilters(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 3)
+
+	if _, err := uc.CreateTeam(ctx, &entity.Team{Name: "frontend", Members: []*entity.TeamMember{{UserID: "f1", Name: "Fred", IsActive: true}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := uc.SetIsActive(ctx, "u3", false); err != nil {
+		t.Fatal(err)
+	}
+
+	teams, _, err := uc.ListTeams(ctx, entity.TeamFilter{NameContains: "back"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(teams) != 1 || teams[0].Name != "backend" || teams[0].Members != 3 || teams[0].ActiveMembers != 2 {
+		t.Errorf("teams = %+v, want backend with 2 of 3 active", teams)
+	}
+
+	active := true
+	users, _, err := uc.ListUsers(ctx, entity.UserFilter{TeamName: "backend", IsActive: &active})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(users) != 2 {
+		t.Errorf("active backend users = %d, want 2", len(users))
+	}
+
+	for _, u := range users {
+		if u.TeamName != "backend" || !u.IsActive {
+			t.Errorf("user %+v does not match the filter", u)
+		}
+	}
+
+	if _, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.CreatePR(ctx, "pr2", "bugfix", "u2", false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.MergePR(ctx, "pr2"); err != nil {
+		t.Fatal(err)
+	}
+
+	prs, _, err := uc.ListPRs(ctx, entity.PRFilter{TeamName: "backend", Status: entity.OPEN})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(prs) != 1 || prs[0].ID != "pr1" {
+		t.Errorf("open PRs = %+v, want pr1", prs)
+	}
+
+	prs, _, err = uc.ListPRs(ctx, entity.PRFilter{AuthorID: "u2", NameContains: "bug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(prs) != 1 || prs[0].ID != "pr2" || prs[0].Status != entity.MERGED {
+		t.Errorf("PRs of u2 = %+v, want merged pr2", prs)
+	}
+
+	prs, _, err = uc.ListPRs(ctx, entity.PRFilter{TeamName: "frontend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(prs) != 0 {
+		t.Errorf("frontend PRs = %+v, want none", prs)
+	}
+}
+
+func TestListPRsValidation(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+
+	now := time.Now()
+	invalid := []struct {
+		name   string
+		filter entity.PRFilter
+	}{
+		{"unknown status", entity.PRFilter{Status: "REVIEWED"}},
+		{"cursor without time", entity.PRFilter{Page: entity.Page{After: &entity.Cursor{ID: "pr1"}}}},
+		{"empty created range", entity.PRFilter{CreatedFrom: &now, CreatedTo: &now}},
+		{"empty merged range", entity.PRFilter{MergedFrom: &now, MergedTo: &now}},
+		{"limit above max", entity.PRFilter{Page: entity.Page{Limit: entity.MaxPageLimit + 1}}},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := uc.ListPRs(ctx, tt.filter); entity.CodeOf(err) != entity.InvalidRequest {
+				t.Errorf("ListPRs error = %v, want %s", err, entity.InvalidRequest)
+			}
+		})
+	}
+}
+
+func TestListPagination(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 5)
+
+	var want []string
+	for i := 1; i <= 5; i++ {
+		id := fmt.Sprintf("pr%d", i)
+		if _, err := uc.CreatePR(ctx, id, "feature", "u1", false); err != nil {
+			t.Fatal(err)
+		}
+
+		want = append(want, id)
+	}
+
+	slices.Reverse(want)
+
+	// ровно limit элементов — последняя страница без курсора
+	if users, next, err := uc.ListUsers(ctx, entity.UserFilter{Page: entity.Page{Limit: 5}}); err != nil || len(users) != 5 || next != "" {
+		t.Errorf("page of exactly limit users = %d, next %q, %v, want 5 without a cursor", len(users), next, err)
+	}
+
+	// limit+1 элементов — следующая страница с одним элементом
+	users, next, err := uc.ListUsers(ctx, entity.UserFilter{Page: entity.Page{Limit: 4}})
+	if err != nil || len(users) != 4 || next == "" {
+		t.Fatalf("first page = %d, next %q, %v, want 4 with a cursor", len(users), next, err)
+	}
+
+	after, err := entity.DecodeCursor(next)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rest, next, err := uc.ListUsers(ctx, entity.UserFilter{Page: entity.Page{Limit: 4, After: after}})
+	if err != nil || len(rest) != 1 || rest[0].UserID != "u5" || next != "" {
+		t.Errorf("last page = %+v, next %q, %v, want u5 without a cursor", rest, next, err)
+	}
+
+	// PR обходятся от новых к старым без пропусков и повторов
+	var got []string
+	page := entity.Page{Limit: 2}
+	for {
+		prs, next, err := uc.ListPRs(ctx, entity.PRFilter{Page: page})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, pr := range prs {
+			got = append(got, pr.ID)
+		}
+
+		if next == "" {
+			break
+		}
+
+		if page.After, err = entity.DecodeCursor(next); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("paged PRs = %v, want %v", got, want)
+	}
+
+	// нулевой лимит — лимит по умолчанию
+	if teams, next, err := uc.ListTeams(ctx, entity.TeamFilter{}); err != nil || len(teams) != 1 || next != "" {
+		t.Errorf("teams with default limit = %+v, next %q, %v", teams, next, err)
+	}
+
+	if _, _, err := uc.ListTeams(ctx, entity.TeamFilter{Page: entity.Page{Limit: -1}}); entity.CodeOf(err) != entity.InvalidRequest {
+		t.Errorf("negative limit error = %v, want %s", err, entity.InvalidRequest)
+	}
+}
@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"errors"
+	"log/slog"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// NotificationBatchSize сколько уведомлений отправляется за один проход
+	NotificationBatchSize = 100
+
+	// notificationConsumer имя потребителя outbox, который планирует уведомления
+	notificationConsumer = "notifications"
+)
+
+// NotificationSender отправляет уведомление по адресу его канала
+type NotificationSender interface {
+	Send(ctx context.Context, notification *entity.Notification) error
+}
+
+// GetNotificationPreferences получает настройки уведомлений пользователя
+func (uc *UseCase) GetNotificationPreferences(ctx context.Context, userID string) (*entity.NotificationPreferences, error) {
+	prefs, err := uc.repo.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		slog.Error("failed to get notification preferences", "error", err, "userID", userID)
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+// SetNotificationPreferences сохраняет каналы, адреса и тихие часы пользователя.
+// Пустой список каналов отключает уведомления
+func (uc *UseCase) SetNotificationPreferences(ctx context.Context, prefs *entity.NotificationPreferences) (*entity.NotificationPreferences, error) {
+	prefs.Email = strings.TrimSpace(prefs.Email)
+	prefs.ChatWebhookURL = strings.TrimSpace(prefs.ChatWebhookURL)
+	if err := validatePreferences(prefs); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.UpsertNotificationPreferences(ctx, prefs); err != nil {
+		slog.Error("failed to save notification preferences", "error", err, "userID", prefs.UserID)
+		return nil, err
+	}
+
+	slog.Info("notification preferences updated", "userID", prefs.UserID, "channels", prefs.Channels)
+	return prefs, nil
+}
+
+// ListNotifications получает журнал уведомлений от новых к старым и курсор следующей страницы
+func (uc *UseCase) ListNotifications(ctx context.Context, filter entity.NotificationFilter) ([]*entity.Notification, string, error) {
+	if filter.Status != "" && !slices.Contains(entity.NotificationStatuses, filter.Status) {
+		return nil, "", entity.NewError(entity.InvalidRequest, "unknown notification status %s", filter.Status)
+	}
+
+	limit, err := pageLimit(&filter.Page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	notifications, err := uc.repo.ListNotifications(ctx, filter)
+	if err != nil {
+		slog.Error("failed to list notifications", "error", err, "userID", filter.UserID)
+		return nil, "", err
+	}
+
+	if len(notifications) <= limit {
+		return notifications, "", nil
+	}
+
+	notifications = notifications[:limit]
+	return notifications, entity.Cursor{ID: strconv.FormatInt(notifications[limit-1].ID, 10)}.Encode(), nil
+}
+
+// PlanNotifications читает новые события outbox в порядке фиксации и ставит в очередь
+// уведомления их получателям во включенных каналах; уведомление в тихие часы откладывается
+// до их конца. Позиция чтения блокируется и сдвигается в одной транзакции с постановкой,
+// поэтому несколько экземпляров не планируют одно событие дважды. Возвращает число прочитанных событий
+func (uc *UseCase) PlanNotifications(ctx context.Context, now time.Time) (int, error) {
+	var read int
+	err := uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		after, err := uc.repo.LockConsumerOffset(ctx, notificationConsumer)
+		if err != nil {
+			return err
+		}
+
+		events, err := uc.repo.GetCommittedOutboxEvents(ctx, after, OutboxBatchSize)
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		notifications := make([]*entity.Notification, 0)
+		prefs := make(map[string]*entity.NotificationPreferences)
+		for _, event := range events {
+			for _, userID := range event.NotificationRecipients() {
+				p, ok := prefs[userID]
+				if !ok {
+					p, err = uc.repo.GetNotificationPreferences(ctx, userID)
+					if err != nil && !errors.Is(err, entity.ErrPreferencesNotFound) {
+						return err
+					}
+
+					prefs[userID] = p
+				}
+
+				notifications = append(notifications, planNotifications(p, event, now)...)
+			}
+		}
+
+		if len(notifications) > 0 {
+			if err := uc.repo.AddNotifications(ctx, notifications); err != nil {
+				return err
+			}
+		}
+
+		read = len(events)
+		return uc.repo.SetConsumerOffset(ctx, notificationConsumer, events[len(events)-1].Position())
+	})
+
+	if err != nil {
+		slog.Error("failed to plan notifications", "error", err)
+		return 0, err
+	}
+
+	return read, nil
+}
+
+// SendNotifications отправляет уведомления, время которых наступило, через отправителя их канала.
+// Неудачная отправка повторяется с экспоненциальной задержкой, после policy.MaxAttempts попыток
+// уведомление помечается FAILED. Возвращает число обработанных уведомлений
+func (uc *UseCase) SendNotifications(ctx context.Context, senders map[entity.NotificationChannel]NotificationSender, policy entity.RetryPolicy, lease time.Duration) (int, error) {
+	notifications, err := uc.repo.ClaimNotifications(ctx, time.Now(), lease, NotificationBatchSize)
+	if err != nil {
+		slog.Error("failed to claim notifications", "error", err)
+		return 0, err
+	}
+
+	for _, n := range notifications {
+		var sendErr error
+		if sender, ok := senders[n.Channel]; ok {
+			sendErr = sender.Send(ctx, n)
+		} else {
+			sendErr = errors.New("channel not configured")
+			n.Attempts = policy.MaxAttempts
+		}
+
+		if err := uc.completeNotification(ctx, n, sendErr, policy); err != nil {
+			slog.Error("failed to save notification", "error", err, "notificationID", n.ID)
+			return 0, err
+		}
+	}
+
+	return len(notifications), nil
+}
+
+// completeNotification сохраняет результат попытки и планирует следующую. ScheduledAt
+// завершенного уведомления — время последней попытки, а не срок аренды из ClaimNotifications
+func (uc *UseCase) completeNotification(ctx context.Context, n *entity.Notification, sendErr error, policy entity.RetryPolicy) error {
+	now := time.Now()
+	n.Attempts++
+	n.ScheduledAt = now
+
+	if sendErr == nil {
+		n.Status = entity.NotificationSent
+		n.SentAt = &now
+		n.LastError = ""
+		return uc.repo.UpdateNotification(ctx, n)
+	}
+
+	n.LastError = sendErr.Error()
+	if n.Attempts < policy.MaxAttempts {
+		n.Status = entity.NotificationRetrying
+		n.ScheduledAt = now.Add(policy.Delay(n.Attempts))
+
+		slog.Warn("notification failed, will retry",
+			"error", sendErr, "notificationID", n.ID, "attempts", n.Attempts, "scheduledAt", n.ScheduledAt)
+		return uc.repo.UpdateNotification(ctx, n)
+	}
+
+	n.Status = entity.NotificationFailed
+	slog.Error("notification failed permanently", "error", sendErr, "notificationID", n.ID, "channel", n.Channel)
+	return uc.repo.UpdateNotification(ctx, n)
+}
+
+// planNotifications уведомления пользователя о событии во всех включенных каналах с адресом
+func planNotifications(prefs *entity.NotificationPreferences, event *entity.OutboxEvent, now time.Time) []*entity.Notification {
+	if prefs == nil {
+		return nil
+	}
+
+	scheduledAt := now
+	if prefs.QuietHours != nil {
+		scheduledAt = prefs.QuietHours.Defer(now)
+	}
+
+	notifications := make([]*entity.Notification, 0, len(prefs.Channels))
+	for _, channel := range prefs.Channels {
+		address := prefs.Address(channel)
+		if address == "" {
+			continue
+		}
+
+		notifications = append(notifications, &entity.Notification{
+			UserID:      prefs.UserID,
+			Channel:     channel,
+			Address:     address,
+			EventKey:    event.Key,
+			EventType:   event.Type,
+			Payload:     event.Payload,
+			ScheduledAt: scheduledAt,
+		})
+	}
+
+	return notifications
+}
+
+func validatePreferences(prefs *entity.NotificationPreferences) error {
+	channels := make([]entity.NotificationChannel, 0, len(prefs.Channels))
+	for _, channel := range prefs.Channels {
+		if !channel.IsValid() {
+			return entity.NewError(entity.InvalidRequest, "unknown notification channel %s", channel)
+		}
+
+		if !slices.Contains(channels, channel) {
+			channels = append(channels, channel)
+		}
+	}
+
+	prefs.Channels = channels
+
+	if prefs.Email != "" {
+		if addr, err := mail.ParseAddress(prefs.Email); err != nil || addr.Address != prefs.Email {
+			return entity.NewError(entity.InvalidRequest, "email must be a valid address")
+		}
+	} else if slices.Contains(channels, entity.ChannelEmail) {
+		return entity.NewError(entity.InvalidRequest, "email is required for the %s channel", entity.ChannelEmail)
+	}
+
+	if prefs.ChatWebhookURL != "" {
+		u, err := url.Parse(prefs.ChatWebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+			len(prefs.ChatWebhookURL) > maxWebhookURLLength {
+			return entity.NewError(entity.InvalidRequest, "chat_webhook_url must be an absolute http(s) URL")
+		}
+
+		if !entity.IsPublicHost(u.Hostname()) {
+			return entity.NewError(entity.InvalidRequest, "chat_webhook_url must point to a public host")
+		}
+	} else if slices.Contains(channels, entity.ChannelChat) {
+		return entity.NewError(entity.InvalidRequest, "chat_webhook_url is required for the %s channel", entity.ChannelChat)
+	}
+
+	if prefs.QuietHours != nil {
+		if err := prefs.QuietHours.Validate(); err != nil {
+			return entity.NewError(entity.InvalidRequest, "invalid quiet_hours: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package usecase_test
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"testing"
+)
+
+func TestSetNotificationPreferencesRejectsInternalChatURL(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+
+	_, err := uc.CreateTeam(ctx, &entity.Team{Name: "backend", Members: []*entity.TeamMember{
+		{UserID: "u1", Name: "Alice", IsActive: true},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "https://chat.example.com/hooks/abc"},
+		{url: "http://localhost:8080/hook", wantErr: true},
+		{url: "http://127.0.0.1/hook", wantErr: true},
+		{url: "http://[::1]/hook", wantErr: true},
+		{url: "http://169.254.169.254/latest/meta-data", wantErr: true},
+		{url: "http://10.0.0.5/hook", wantErr: true},
+		{url: "ftp://chat.example.com/hook", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		_, err := uc.SetNotificationPreferences(ctx, &entity.NotificationPreferences{
+			UserID:         "u1",
+			ChatWebhookURL: tt.url,
+			Channels:       []entity.NotificationChannel{entity.ChannelChat},
+		})
+
+		if tt.wantErr {
+			if entity.CodeOf(err) != entity.InvalidRequest {
+				t.Errorf("%s: error = %v, want %s", tt.url, err, entity.InvalidRequest)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %v", tt.url, err)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// OutboxBatchSize сколько событий публикуется за один проход релея
+const OutboxBatchSize = 100
+
+// EventPublisher публикует событие из outbox во внешнюю систему. Повтор после ошибки
+// возможен, получатель отбрасывает дубликаты по event.Key
+type EventPublisher interface {
+	Publish(ctx context.Context, event *entity.OutboxEvent) error
+}
+
+// emit записывает доменное событие в outbox и ставит его в очередь подписанным вебхукам.
+// Вызывается внутри транзакции изменения, поэтому событие сохраняется и откатывается вместе с ним
+func (uc *UseCase) emit(ctx context.Context, eventType entity.EventType, teamName string, data any) error {
+	event := entity.NewEvent(eventType, teamName, data)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	err = uc.repo.AddOutboxEvent(ctx, &entity.OutboxEvent{
+		Key:      event.ID,
+		Type:     eventType,
+		TeamName: teamName,
+		Payload:  payload,
+	})
+	if err != nil {
+		slog.Error("failed to write outbox event", "error", err, "event", eventType)
+		return err
+	}
+
+	return uc.enqueueWebhooks(ctx, event, payload)
+}
+
+// emitPR публикует событие о PR от имени команды его автора
+func (uc *UseCase) emitPR(ctx context.Context, eventType entity.EventType, pr *entity.PullRequest) error {
+	author, err := uc.repo.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		return fmt.Errorf("get author: %w", err)
+	}
+
+	return uc.emit(ctx, eventType, author.TeamName, entity.PREventData{
+		PullRequestID: pr.ID,
+		Name:          pr.Name,
+		AuthorID:      pr.AuthorID,
+		Status:        pr.Status,
+		Reviewers:     pr.AssignReviewers,
+	})
+}
+
+// emitAssignments публикует назначения ревьюеров PR: замену одного ревьюера другим —
+// как reviewer.reassigned, остальные назначения — как reviewer.assigned
+func (uc *UseCase) emitAssignments(ctx context.Context, prID string, reason entity.AssignmentReason, assigned, unassigned []string) error {
+	if len(assigned) == 0 {
+		return nil
+	}
+
+	pr, err := uc.repo.GetPR(ctx, prID)
+	if err != nil {
+		return err
+	}
+
+	author, err := uc.repo.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		return fmt.Errorf("get author: %w", err)
+	}
+
+	if len(assigned) == 1 && len(unassigned) == 1 {
+		return uc.emit(ctx, entity.EventReviewerReassigned, author.TeamName, entity.ReviewerEventData{
+			PullRequestID: prID,
+			ReviewerID:    assigned[0],
+			OldReviewerID: unassigned[0],
+			Reason:        reason,
+		})
+	}
+
+	for _, reviewerID := range assigned {
+		err := uc.emit(ctx, entity.EventReviewerAssigned, author.TeamName, entity.ReviewerEventData{
+			PullRequestID: prID,
+			ReviewerID:    reviewerID,
+			Reason:        reason,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// emitUser публикует вывод пользователя из ротации или возврат в нее
+func (uc *UseCase) emitUser(ctx context.Context, eventType entity.EventType, user *entity.User, reason entity.AssignmentReason) error {
+	return uc.emit(ctx, eventType, user.TeamName, entity.UserEventData{
+		UserID: user.UserID,
+		Reason: reason,
+	})
+}
+
+// RelayOutbox забирает пачку событий и публикует их по порядку вне транзакции: забранные
+// события скрыты от релеев других экземпляров на lease, который должен превышать время
+// публикации пачки. На первом неудачном событии пачка останавливается, остальные события
+// возвращаются в очередь и ждут его: неудачное событие повторяется с экспоненциальной
+// задержкой policy, а после policy.MaxAttempts попыток пропускается. Возвращает число
+// событий, которые пытались опубликовать
+func (uc *UseCase) RelayOutbox(ctx context.Context, publisher EventPublisher, policy entity.RetryPolicy, lease time.Duration) (int, error) {
+	events, err := uc.repo.ClaimOutboxEvents(ctx, time.Now(), lease, OutboxBatchSize)
+	if err != nil {
+		slog.Error("failed to claim outbox events", "error", err)
+		return 0, err
+	}
+
+	ids := make([]int64, 0, len(events))
+	attempted := len(events)
+	for i, event := range events {
+		publishErr := publisher.Publish(ctx, event)
+		if publishErr == nil {
+			ids = append(ids, event.ID)
+			continue
+		}
+
+		if err := uc.failOutboxEvent(ctx, event, publishErr, policy); err != nil {
+			slog.Error("failed to save outbox event", "error", err, "eventID", event.ID)
+			return 0, err
+		}
+
+		if err := uc.releaseOutboxEvents(ctx, events[i+1:]); err != nil {
+			return 0, err
+		}
+
+		attempted = i + 1
+		break
+	}
+
+	if len(ids) == 0 {
+		return attempted, nil
+	}
+
+	if err := uc.repo.MarkOutboxPublished(ctx, ids, time.Now()); err != nil {
+		slog.Error("failed to mark outbox events published", "error", err)
+		return 0, err
+	}
+
+	return attempted, nil
+}
+
+// releaseOutboxEvents возвращает в очередь события пачки, оставшиеся за неудачным
+func (uc *UseCase) releaseOutboxEvents(ctx context.Context, events []*entity.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+
+	if err := uc.repo.ReleaseOutboxEvents(ctx, ids, time.Now()); err != nil {
+		slog.Error("failed to release outbox events", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// failOutboxEvent сохраняет неудачную попытку публикации и планирует следующую либо
+// отмечает, что событие исчерпало попытки
+func (uc *UseCase) failOutboxEvent(ctx context.Context, event *entity.OutboxEvent, publishErr error, policy entity.RetryPolicy) error {
+	now := time.Now()
+	event.Attempts++
+	event.LastError = publishErr.Error()
+
+	if event.Attempts < policy.MaxAttempts {
+		event.NextAttemptAt = now.Add(policy.Delay(event.Attempts))
+
+		slog.Warn("outbox event publish failed, will retry",
+			"error", publishErr, "eventID", event.ID, "attempts", event.Attempts, "nextAttemptAt", event.NextAttemptAt)
+		return uc.repo.RecordOutboxFailure(ctx, event)
+	}
+
+	event.FailedAt = &now
+
+	slog.Error("outbox event publish failed permanently", "error", publishErr, "eventID", event.ID, "attempts", event.Attempts)
+	return uc.repo.RecordOutboxFailure(ctx, event)
+}
+
+// PruneOutbox удаляет события, опубликованные раньше before и прочитанные всеми потребителями
+// outbox; возвращает число удаленных
+func (uc *UseCase) PruneOutbox(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := uc.repo.DeleteOutboxEvents(ctx, before)
+	if err != nil {
+		slog.Error("failed to prune outbox", "error", err)
+		return 0, err
+	}
+
+	if deleted > 0 {
+		slog.Info("outbox pruned", "deleted", deleted, "before", before)
+	}
+
+	return deleted, nil
+}
+
+// OutboxBacklog число событий, ожидающих публикации
+func (uc *UseCase) OutboxBacklog(ctx context.Context) (int, error) {
+	count, err := uc.repo.CountPendingOutboxEvents(ctx)
+	if err != nil {
+		slog.Error("failed to count outbox backlog", "error", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// OutboxEventsAfter получает страницу событий outbox после позиции after из завершенных транзакций
+func (uc *UseCase) OutboxEventsAfter(ctx context.Context, after entity.OutboxPosition) ([]*entity.OutboxEvent, error) {
+	events, err := uc.repo.GetCommittedOutboxEvents(ctx, after, OutboxBatchSize)
+	if err != nil {
+		slog.Error("failed to get outbox events", "error", err, "afterID", after.ID)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// OutboxEnd позиция, с которой читатель outbox получает только новые события
+func (uc *UseCase) OutboxEnd(ctx context.Context) (entity.OutboxPosition, error) {
+	position, err := uc.repo.GetOutboxEnd(ctx)
+	if err != nil {
+		slog.Error("failed to get outbox end", "error", err)
+		return entity.OutboxPosition{}, err
+	}
+
+	return position, nil
+}
+
+// ReviewStreamPosition проверяет, что пользователь существует, и возвращает позицию начала
+// его потока: позицию события lastEventID переподключившегося клиента или конец outbox для
+// нового. Если события lastEventID уже нет в outbox, поток начинается с конца
+func (uc *UseCase) ReviewStreamPosition(ctx context.Context, userID string, lastEventID int64) (entity.OutboxPosition, error) {
+	if _, err := uc.repo.GetUser(ctx, userID); err != nil {
+		slog.Error("failed to get user", "error", err, "userID", userID)
+		return entity.OutboxPosition{}, err
+	}
+
+	if lastEventID == 0 {
+		return uc.OutboxEnd(ctx)
+	}
+
+	position, err := uc.repo.GetOutboxEventPosition(ctx, lastEventID)
+	if errors.Is(err, entity.ErrOutboxEventNotFound) {
+		slog.Warn("last event is not in outbox, streaming from the end", "userID", userID, "lastEventID", lastEventID)
+		return uc.OutboxEnd(ctx)
+	}
+
+	if err != nil {
+		slog.Error("failed to get outbox event position", "error", err, "eventID", lastEventID)
+		return entity.OutboxPosition{}, err
+	}
+
+	return position, nil
+}
+
+// ReviewQueueEvents события очереди ревью пользователя из страницы outbox после позиции after.
+// last — позиция последнего просмотренного события, равна after, если новых событий нет
+func (uc *UseCase) ReviewQueueEvents(ctx context.Context, userID string, after entity.OutboxPosition) ([]*entity.OutboxEvent, entity.OutboxPosition, error) {
+	page, err := uc.OutboxEventsAfter(ctx, after)
+	if err != nil {
+		return nil, entity.OutboxPosition{}, err
+	}
+
+	events := make([]*entity.OutboxEvent, 0)
+	last := after
+	for _, event := range page {
+		last = event.Position()
+		if event.Concerns(userID) {
+			events = append(events, event)
+		}
+	}
+
+	return events, last, nil
+}
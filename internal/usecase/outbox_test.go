@@ -0,0 +1,384 @@
+package usecase_test
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakePublisher запоминает опубликованные события или возвращает err: для всех событий,
+// если failKey пуст, иначе только для события failKey
+type fakePublisher struct {
+	err       error
+	failKey   string
+	published []string
+}
+
+func (p *fakePublisher) Publish(_ context.Context, event *entity.OutboxEvent) error {
+	if p.err != nil && (p.failKey == "" || p.failKey == event.Key) {
+		return p.err
+	}
+
+	p.published = append(p.published, event.Key)
+	return nil
+}
+
+// seedOutbox создает команду и PR, порождающие события outbox, и возвращает их число
+func seedOutbox(t *testing.T, ctx context.Context, uc *usecase.UseCase) int {
+	t.Helper()
+
+	_, err := uc.CreateTeam(ctx, &entity.Team{Name: "backend", Members: []*entity.TeamMember{
+		{UserID: "u1", Name: "Alice", IsActive: true},
+		{UserID: "u2", Name: "Bob", IsActive: true},
+	}})
+	if err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+
+	if _, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false); err != nil {
+		t.Fatalf("CreatePR: %v", err)
+	}
+
+	backlog, err := uc.OutboxBacklog(ctx)
+	if err != nil {
+		t.Fatalf("OutboxBacklog: %v", err)
+	}
+
+	if backlog == 0 {
+		t.Fatal("no outbox events were written")
+	}
+
+	return backlog
+}
+
+func TestRelayOutboxRetriesAndGivesUp(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	total := seedOutbox(t, ctx, uc)
+
+	policy := entity.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Nanosecond, MaxDelay: time.Nanosecond}
+	failing := &fakePublisher{err: errors.New("broker is down")}
+
+	// пачка останавливается на первом неудачном событии, поэтому события сдаются по одному
+	for attempt := 1; attempt <= policy.MaxAttempts*total; attempt++ {
+		attempted, err := uc.RelayOutbox(ctx, failing, policy, time.Hour)
+		if err != nil {
+			t.Fatalf("attempt %d: RelayOutbox: %v", attempt, err)
+		}
+
+		if attempted != 1 {
+			t.Fatalf("attempt %d: attempted %d events, want 1", attempt, attempted)
+		}
+	}
+
+	backlog, err := uc.OutboxBacklog(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if backlog != 0 {
+		t.Errorf("backlog = %d after exhausting attempts, want 0", backlog)
+	}
+
+	ok := &fakePublisher{}
+	claimed, err := uc.RelayOutbox(ctx, ok, policy, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claimed != 0 || len(ok.published) != 0 {
+		t.Errorf("failed events were claimed again: claimed %d, published %v", claimed, ok.published)
+	}
+}
+
+func TestRelayOutboxSkipsLeasedEvents(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	repo := memory.New()
+	uc := usecase.New(repo)
+	total := seedOutbox(t, ctx, uc)
+
+	leased, err := repo.ClaimOutboxEvents(ctx, time.Now(), time.Hour, usecase.OutboxBatchSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(leased) != total {
+		t.Fatalf("leased %d events, want %d", len(leased), total)
+	}
+
+	publisher := &fakePublisher{}
+	policy := entity.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute}
+	claimed, err := uc.RelayOutbox(ctx, publisher, policy, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claimed != 0 || len(publisher.published) != 0 {
+		t.Errorf("leased events were published by another relay: %v", publisher.published)
+	}
+}
+
+func TestPruneOutboxKeepsUnreadEvents(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	repo := memory.New()
+	uc := usecase.New(repo)
+
+	if _, err := repo.LockConsumerOffset(ctx, "notifications"); err != nil {
+		t.Fatal(err)
+	}
+
+	total := seedOutbox(t, ctx, uc)
+	publisher := &fakePublisher{}
+	policy := entity.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute}
+	if _, err := uc.RelayOutbox(ctx, publisher, policy, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(publisher.published) != total {
+		t.Fatalf("published %d events, want %d", len(publisher.published), total)
+	}
+
+	end, err := uc.OutboxEnd(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	future := time.Now().Add(time.Minute)
+	if deleted, err := uc.PruneOutbox(ctx, future); err != nil || deleted != 0 {
+		t.Fatalf("pruned %d unread events (err %v), want 0", deleted, err)
+	}
+
+	if err := repo.SetConsumerOffset(ctx, "notifications", end); err != nil {
+		t.Fatal(err)
+	}
+
+	if deleted, err := uc.PruneOutbox(ctx, future); err != nil || deleted != total {
+		t.Fatalf("pruned %d events (err %v), want %d", deleted, err, total)
+	}
+
+	// позиция конца outbox не сдвигается назад после удаления
+	if after, err := uc.OutboxEnd(ctx); err != nil || after != end {
+		t.Errorf("outbox end = %+v (err %v), want %+v", after, err, end)
+	}
+}
+
+func TestReviewQueueEventsResumeFromLastEvent(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	seedOutbox(t, ctx, uc)
+
+	start, err := uc.ReviewStreamPosition(ctx, "u2", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, last, err := uc.ReviewQueueEvents(ctx, "u2", start)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 0 || last != start {
+		t.Fatalf("new stream got old events: %d, position %+v", len(events), last)
+	}
+
+	all, err := uc.OutboxEventsAfter(ctx, entity.OutboxPosition{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resumed, err := uc.ReviewStreamPosition(ctx, "u2", all[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, last, err = uc.ReviewQueueEvents(ctx, "u2", resumed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 1 || events[0].Type != entity.EventReviewerAssigned {
+		t.Fatalf("resumed stream events = %d, want one %s", len(events), entity.EventReviewerAssigned)
+	}
+
+	if last != all[len(all)-1].Position() {
+		t.Errorf("last position = %+v, want %+v", last, all[len(all)-1].Position())
+	}
+
+	if _, err := uc.ReviewStreamPosition(ctx, "u2", 1000); err != nil {
+		t.Errorf("unknown last event id must start from the end: %v", err)
+	}
+}
+
+// outboxOrder мержит PR из seedOutbox, чтобы событий было не меньше трех, и возвращает
+// ключи событий outbox в порядке публикации
+func outboxOrder(t *testing.T, ctx context.Context, uc *usecase.UseCase) []string {
+	t.Helper()
+
+	if _, err := uc.MergePR(ctx, "pr1"); err != nil {
+		t.Fatalf("MergePR: %v", err)
+	}
+
+	all, err := uc.OutboxEventsAfter(ctx, entity.OutboxPosition{})
+	if err != nil {
+		t.Fatalf("OutboxEventsAfter: %v", err)
+	}
+
+	if len(all) < 3 {
+		t.Fatalf("seeded %d events, want at least 3", len(all))
+	}
+
+	keys := make([]string, 0, len(all))
+	for _, e := range all {
+		keys = append(keys, e.Key)
+	}
+
+	return keys
+}
+
+func TestRelayOutboxFailedEventHoldsBackLaterEvents(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	seedOutbox(t, ctx, uc)
+	order := outboxOrder(t, ctx, uc)
+	total := len(order)
+
+	policy := entity.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	publisher := &fakePublisher{err: errors.New("rejected"), failKey: order[1]}
+	if _, err := uc.RelayOutbox(ctx, publisher, policy, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(publisher.published, order[:1]) {
+		t.Fatalf("published %v, want only %v before the failed event", publisher.published, order[:1])
+	}
+
+	// до повтора неудачного события следующие за ним не публикуются
+	ok := &fakePublisher{}
+	if _, err := uc.RelayOutbox(ctx, ok, policy, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ok.published) != 0 {
+		t.Errorf("events behind the failed one were published: %v", ok.published)
+	}
+
+	if backlog, err := uc.OutboxBacklog(ctx); err != nil || backlog != total-1 {
+		t.Errorf("backlog = %d (err %v), want %d", backlog, err, total-1)
+	}
+}
+
+func TestRelayOutboxKeepsOrderAfterGivingUp(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	seedOutbox(t, ctx, uc)
+	order := outboxOrder(t, ctx, uc)
+
+	policy := entity.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Nanosecond, MaxDelay: time.Nanosecond}
+	publisher := &fakePublisher{err: errors.New("rejected"), failKey: order[1]}
+	for range 3 {
+		if _, err := uc.RelayOutbox(ctx, publisher, policy, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := append([]string{order[0]}, order[2:]...)
+	if !slices.Equal(publisher.published, want) {
+		t.Errorf("published %v, want %v", publisher.published, want)
+	}
+}
+
+// drainReviewQueue читает события очереди ревью userID после позиции after до конца outbox
+func drainReviewQueue(t *testing.T, ctx context.Context, uc *usecase.UseCase, userID string, after entity.OutboxPosition) ([]*entity.OutboxEvent, entity.OutboxPosition) {
+	t.Helper()
+
+	var events []*entity.OutboxEvent
+	for {
+		page, last, err := uc.ReviewQueueEvents(ctx, userID, after)
+		if err != nil {
+			t.Fatalf("ReviewQueueEvents: %v", err)
+		}
+
+		events = append(events, page...)
+		if last == after {
+			return events, last
+		}
+
+		after = last
+	}
+}
+
+func TestReviewQueueReplayAfterReconnect(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 3)
+
+	if _, err := uc.ReviewStreamPosition(ctx, "ghost", 0); entity.CodeOf(err) != entity.NotFound {
+		t.Errorf("stream of unknown user error = %v, want %s", err, entity.NotFound)
+	}
+
+	start, err := uc.ReviewStreamPosition(ctx, "u2", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false); err != nil {
+		t.Fatal(err)
+	}
+
+	delivered, _ := drainReviewQueue(t, ctx, uc, "u2", start)
+	if len(delivered) != 1 || delivered[0].Type != entity.EventReviewerAssigned {
+		t.Fatalf("live events = %d, want one %s", len(delivered), entity.EventReviewerAssigned)
+	}
+
+	// пока клиент отключен, в очереди u2 меняется pr1, а PR самого u2 ее не касается
+	if _, err := uc.CreatePR(ctx, "pr2", "bugfix", "u2", false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.MergePR(ctx, "pr1"); err != nil {
+		t.Fatal(err)
+	}
+
+	resumed, err := uc.ReviewStreamPosition(ctx, "u2", delivered[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	missed, last := drainReviewQueue(t, ctx, uc, "u2", resumed)
+	if len(missed) != 1 || missed[0].Type != entity.EventPRMerged || missed[0].ID == delivered[0].ID {
+		t.Fatalf("replayed events = %+v, want only the merge of pr1", missed)
+	}
+
+	end, err := uc.OutboxEnd(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if last != end {
+		t.Errorf("replay stopped at %+v, want outbox end %+v", last, end)
+	}
+
+	// удаленное из outbox событие: поток начинается с конца, а не с начала outbox
+	policy := entity.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute}
+	if _, err := uc.RelayOutbox(ctx, &fakePublisher{}, policy, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if deleted, err := uc.PruneOutbox(ctx, time.Now().Add(time.Minute)); err != nil || deleted == 0 {
+		t.Fatalf("pruned %d events (err %v), want all", deleted, err)
+	}
+
+	pruned, err := uc.ReviewStreamPosition(ctx, "u2", delivered[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pruned != end {
+		t.Errorf("position after pruned event = %+v, want outbox end %+v", pruned, end)
+	}
+}
@@ -3,14 +3,33 @@ package usecase
 import (
 	"avito_test_task/internal/entity"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"sort"
+	"slices"
 	"time"
 )
 
-// CreatePR создает pull request и назначает наименее загруженных ревьюеров
-func (uc *UseCase) CreatePR(ctx context.Context, prID, prName, authorID string) (*entity.PullRequest, error) {
+// CreatePR создает pull request с записью в журнал аудита
+func (uc *UseCase) CreatePR(ctx context.Context, prID, prName, authorID string, draft bool) (*entity.PullRequest, error) {
+	var pr *entity.PullRequest
+	err := uc.audited(ctx, entity.AuditCreatePR, entity.AuditPullRequest, prID, func(ctx context.Context) error {
+		var err error
+		pr, err = uc.createPR(ctx, prID, prName, authorID, draft)
+		return err
+	})
+
+	return pr, err
+}
+
+// createPR создает pull request и назначает ревьюеров по стратегии команды,
+// черновик создается без ревьюеров
+func (uc *UseCase) createPR(ctx context.Context, prID, prName, authorID string, draft bool) (*entity.PullRequest, error) {
+	if err := uc.checkAuthor(ctx, authorID); err != nil {
+		slog.Error("author does not match the caller", "error", err, "authorID", authorID)
+		return nil, err
+	}
+
 	exists, err := uc.repo.PRExists(ctx, prID)
 	if err != nil {
 		slog.Error("failed to check existence of PR", "error", err)
@@ -19,25 +38,31 @@ func (uc *UseCase) CreatePR(ctx context.Context, prID, prName, authorID string)
 
 	if exists {
 		slog.Error("PR already exists")
-		return nil, fmt.Errorf("PR_EXISTS: PR id already exists")
+		return nil, entity.NewError(entity.PRExists, "PR id already exists")
 	}
 
 	user, err := uc.repo.GetUser(ctx, authorID)
 	if err != nil {
 		slog.Error("author not found", "error", err, "authorID", authorID)
-		return nil, fmt.Errorf("NOT_FOUND: author not found")
+		return nil, fmt.Errorf("get author: %w", err)
 	}
 
-	candidates, err := uc.repo.GetActiveCandidates(ctx, user.TeamName, []string{authorID})
-	if err != nil {
-		slog.Error("failed to get active candidates", "error", err)
-		return nil, fmt.Errorf("get candidates: %w", err)
-	}
+	status := entity.DRAFT
+	reviewers := []string{}
+	if !draft {
+		settings, err := uc.repo.GetTeamSettings(ctx, user.TeamName)
+		if err != nil {
+			slog.Error("failed to get team settings", "error", err, "team", user.TeamName)
+			return nil, err
+		}
 
-	reviewers, err := uc.selectLeastBusyReviewers(ctx, candidates, 2)
-	if err != nil {
-		slog.Error("failed to get reviewers", "error", err)
-		return nil, err
+		reviewers, err = uc.assignReviewers(ctx, settings, authorID)
+		if err != nil {
+			slog.Error("failed to get reviewers", "error", err)
+			return nil, err
+		}
+
+		status = entity.OPEN
 	}
 
 	now := time.Now()
@@ -45,160 +70,484 @@ func (uc *UseCase) CreatePR(ctx context.Context, prID, prName, authorID string)
 		ID:              prID,
 		Name:            prName,
 		AuthorID:        authorID,
-		Status:          entity.OPEN,
+		Status:          status,
 		AssignReviewers: reviewers,
+		Reviews:         []*entity.Review{},
 		CreatedAt:       &now,
 		MergedAt:        nil,
 	}
 
-	if err := uc.repo.CreatePR(ctx, pr); err != nil {
+	err = uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.repo.CreatePR(ctx, pr); err != nil {
+			return err
+		}
+
+		err := uc.emit(ctx, entity.EventPRCreated, user.TeamName, entity.PREventData{
+			PullRequestID: pr.ID,
+			Name:          pr.Name,
+			AuthorID:      pr.AuthorID,
+			Status:        pr.Status,
+			Reviewers:     pr.AssignReviewers,
+		})
+		if err != nil {
+			return err
+		}
+
+		return uc.recordAssignments(ctx, prID, entity.ReasonPRCreated, reviewers, nil)
+	})
+
+	if err != nil {
 		slog.Error("failed to create PR", "error", err)
 		return nil, err
 	}
 
-	slog.Info("PR created successfully", "prID", prID, "reviewersCount", len(reviewers))
+	slog.Info("PR created successfully", "prID", prID, "status", status, "reviewersCount", len(reviewers))
 
 	return pr, nil
 }
 
-// selectLeastBusyReviewers выбирает N наименее загруженных ревьюеров
-func (uc *UseCase) selectLeastBusyReviewers(ctx context.Context, candidates []*entity.User, countReviewers int) ([]string, error) {
-	if len(candidates) == 0 {
-		slog.Error("no candidates on review")
-		return []string{}, nil
+// checkAuthor проверяет, что инициатор создает PR от своего имени либо вправе создавать PR за других
+func (uc *UseCase) checkAuthor(ctx context.Context, authorID string) error {
+	return uc.checkOnBehalf(ctx, entity.PermPRCreate, "author_id", authorID, entity.AuthTarget{
+		UserIDs: []string{authorID},
+	})
+}
+
+// checkReviewer проверяет, что инициатор оставляет ревью от своего имени либо вправе
+// оставлять ревью за других ревьюеров
+func (uc *UseCase) checkReviewer(ctx context.Context, prID, reviewerID string) error {
+	return uc.checkOnBehalf(ctx, entity.PermPRReview, "reviewer_id", reviewerID, entity.AuthTarget{
+		PRID: prID,
+	})
+}
+
+// checkOnBehalf проверяет, что userID из поля field запроса — сам инициатор, либо у инициатора
+// есть общее право perm над объектами target
+func (uc *UseCase) checkOnBehalf(ctx context.Context, perm entity.Permission, field, userID string, target entity.AuthTarget) error {
+	actor := entity.ActorFromContext(ctx)
+	if !actor.IsAnonymous() && (actor.ID == userID || actor.HasRole(entity.RoleSystem)) {
+		return nil
 	}
 
-	userIDs := make([]string, len(candidates))
-	for i, c := range candidates {
-		userIDs[i] = c.UserID
+	target.OwnerIDs = []string{userID}
+	err := uc.Authorize(ctx, actor, perm, target)
+	if errors.Is(err, entity.Forbidden) {
+		return entity.WrapError(entity.Forbidden, err, "%s %s does not match the caller", field, userID)
 	}
 
-	workload, err := uc.repo.GetReviewersWorkload(ctx, userIDs)
+	return err
+}
+
+// assignReviewers подбирает ревьюеров нового PR согласно политике команды
+func (uc *UseCase) assignReviewers(ctx context.Context, settings *entity.TeamSettings, authorID string) ([]string, error) {
+	candidates, err := uc.repo.GetActiveCandidates(ctx, settings.TeamName, []string{authorID})
 	if err != nil {
-		slog.Error("failed to get reviewers workload", "error", err)
-		return nil, err
+		slog.Error("failed to get active candidates", "error", err)
+		return nil, fmt.Errorf("get candidates: %w", err)
 	}
 
-	workloads := make([]reviewerWorkload, 0, len(workload))
-	for id, w := range workload {
-		workloads = append(workloads, reviewerWorkload{
-			UserID: id,
-			Count:  w,
-		})
+	reviewers := make([]string, 0, settings.MaxReviewers)
+	if leadID := requiredTeamLead(settings, authorID); leadID != "" {
+		rest := make([]*entity.User, 0, len(candidates))
+		for _, c := range candidates {
+			if c.UserID == leadID {
+				reviewers = append(reviewers, leadID)
+				continue
+			}
+
+			rest = append(rest, c)
+		}
+
+		if len(reviewers) == 0 {
+			slog.Warn("team lead is not available for review", "team", settings.TeamName, "teamLeadID", leadID)
+		}
+
+		candidates = rest
 	}
 
-	sort.Slice(workloads, func(i, j int) bool {
-		return workloads[i].Count < workloads[j].Count
-	})
+	selected, err := uc.selectReviewers(ctx, settings.TeamName, candidates, settings.MaxReviewers-len(reviewers))
+	if err != nil {
+		return nil, err
+	}
 
-	count := minimum(countReviewers, len(candidates))
-	reviewers := make([]string, count)
-	for i := 0; i < count; i++ {
-		reviewers[i] = candidates[i].UserID
+	reviewers = append(reviewers, selected...)
+	if len(reviewers) < settings.MinReviewers {
+		slog.Error("not enough reviewers for team policy",
+			"team", settings.TeamName,
+			"available", len(reviewers),
+			"required", settings.MinReviewers)
+		return nil, entity.NewError(entity.NoCandidate, "team %s requires at least %d reviewers", settings.TeamName, settings.MinReviewers)
 	}
 
 	return reviewers, nil
 }
 
-type reviewerWorkload struct {
-	UserID string
-	Count  int
+// requiredTeamLead возвращает тимлида, которого политика требует назначить ревьюером
+func requiredTeamLead(settings *entity.TeamSettings, authorID string) string {
+	if !settings.RequireTeamLead || settings.TeamLeadID == authorID {
+		return ""
+	}
+
+	return settings.TeamLeadID
 }
 
-func minimum(a, b int) int {
-	if a < b {
-		return a
+// selectReviewers выбирает до count ревьюеров по стратегии команды
+func (uc *UseCase) selectReviewers(ctx context.Context, teamName string, candidates []*entity.User, count int) ([]string, error) {
+	if len(candidates) == 0 || count <= 0 {
+		slog.Error("no candidates on review")
+		return []string{}, nil
+	}
+
+	strategy, err := uc.repo.GetTeamReviewerStrategy(ctx, teamName)
+	if err != nil {
+		slog.Error("failed to get reviewer strategy", "error", err, "team", teamName)
+		return nil, err
+	}
+
+	selector, ok := uc.selectors[strategy]
+	if !ok {
+		slog.Warn("unknown reviewer strategy, falling back to least busy", "strategy", strategy, "team", teamName)
+		selector = uc.selectors[entity.LeastBusy]
+	}
+
+	workload, err := uc.repo.GetReviewersWorkload(ctx, userIDs(candidates))
+	if err != nil {
+		slog.Error("failed to get reviewers workload", "error", err)
+		return nil, err
+	}
+
+	lastAssigned, err := uc.repo.GetLastAssignedReviewer(ctx, teamName)
+	if err != nil {
+		slog.Error("failed to get last assigned reviewer", "error", err, "team", teamName)
+		return nil, err
 	}
 
-	return b
+	return selector.Select(candidates, workload, lastAssigned, count), nil
 }
 
-// MergePR помечает pull request как MERGED
+// MergePR мержит pull request с записью в журнал аудита
 func (uc *UseCase) MergePR(ctx context.Context, prID string) (*entity.PullRequest, error) {
-	pr, err := uc.repo.MergePR(ctx, prID)
+	return uc.auditedPR(ctx, entity.AuditMergePR, prID, uc.mergePR)
+}
+
+// mergePR помечает pull request как MERGED
+func (uc *UseCase) mergePR(ctx context.Context, prID string) (*entity.PullRequest, error) {
+	pr, err := uc.repo.GetPR(ctx, prID)
+	if err != nil {
+		slog.Error("failed to get PR", "error", err)
+		return nil, err
+	}
+
+	if pr.Status == entity.MERGED {
+		slog.Info("PR already merged", "prID", prID)
+		return pr, nil
+	}
+
+	if err := checkTransition(pr.Status, entity.MERGED); err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkMergePolicy(ctx, pr); err != nil {
+		return nil, err
+	}
+
+	err = uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		pr, err = uc.repo.MergePR(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		return uc.emitPR(ctx, entity.EventPRMerged, pr)
+	})
+
 	if err != nil {
 		slog.Error("failed to merge PR", "error", err)
-		return nil, fmt.Errorf("NOT_FOUND: PR not found")
+		return nil, err
 	}
 
 	slog.Info("PR merged successfully", "prID", prID)
 	return pr, nil
 }
 
-// ReassignReviewer переназначает ревьюера
-func (uc *UseCase) ReassignReviewer(ctx context.Context, prID, oldReviewerID string) (*entity.PullRequest, string, error) {
+// checkMergePolicy проверяет, что PR удовлетворяет политике команды автора
+func (uc *UseCase) checkMergePolicy(ctx context.Context, pr *entity.PullRequest) error {
+	author, err := uc.repo.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		slog.Error("author not found", "error", err, "authorID", pr.AuthorID)
+		return fmt.Errorf("get author: %w", err)
+	}
+
+	settings, err := uc.repo.GetTeamSettings(ctx, author.TeamName)
+	if err != nil {
+		slog.Error("failed to get team settings", "error", err, "team", author.TeamName)
+		return err
+	}
+
+	if len(pr.AssignReviewers) < settings.MinReviewers {
+		slog.Error("PR has not enough reviewers", "prID", pr.ID, "reviewers", len(pr.AssignReviewers))
+		return entity.NewError(entity.PolicyViolated, "team %s requires at least %d reviewers", settings.TeamName, settings.MinReviewers)
+	}
+
+	reviewers, err := uc.repo.GetPRReviewers(ctx, pr.ID)
+	if err != nil {
+		slog.Error("failed to get PR reviewers", "error", err, "prID", pr.ID)
+		return err
+	}
+
+	decisions := pr.LatestDecisions(reviewers)
+	approvals := 0
+	for reviewerID, decision := range decisions {
+		if decision == entity.ChangesRequested {
+			slog.Error("PR has requested changes", "prID", pr.ID, "reviewerID", reviewerID)
+			return entity.NewError(entity.NotApproved, "reviewer %s requested changes", reviewerID)
+		}
+
+		approvals++
+	}
+
+	if approvals < settings.RequiredApprovals {
+		slog.Error("PR has not enough approvals", "prID", pr.ID, "approvals", approvals)
+		return entity.NewError(entity.NotApproved, "%d of %d required approvals", approvals, settings.RequiredApprovals)
+	}
+
+	// тимлид, не доступный при назначении, мерж не блокирует — как и при подборе ревьюеров
+	leadID := requiredTeamLead(settings, pr.AuthorID)
+	if leadID != "" && slices.Contains(pr.AssignReviewers, leadID) && decisions[leadID] != entity.Approved {
+		slog.Error("PR is not approved by team lead", "prID", pr.ID, "teamLeadID", leadID)
+		return entity.NewError(entity.NotApproved, "team lead %s has not approved", leadID)
+	}
+
+	return nil
+}
+
+// SubmitReview сохраняет решение ревьюера с записью в журнал аудита
+func (uc *UseCase) SubmitReview(ctx context.Context, prID, reviewerID string, decision entity.ReviewDecision, comment string) (*entity.PullRequest, error) {
+	var pr *entity.PullRequest
+	err := uc.audited(ctx, entity.AuditReviewPR, entity.AuditPullRequest, prID, func(ctx context.Context) error {
+		var err error
+		pr, err = uc.submitReview(ctx, prID, reviewerID, decision, comment)
+		return err
+	})
+
+	return pr, err
+}
+
+// submitReview сохраняет решение назначенного ревьюера по открытому PR
+func (uc *UseCase) submitReview(ctx context.Context, prID, reviewerID string, decision entity.ReviewDecision, comment string) (*entity.PullRequest, error) {
+	if !decision.IsValid() {
+		return nil, entity.NewError(entity.InvalidRequest, "unknown review decision %s", decision)
+	}
+
+	if reviewerID == "" {
+		return nil, entity.NewError(entity.InvalidRequest, "reviewer_id is required")
+	}
+
+	if err := uc.checkReviewer(ctx, prID, reviewerID); err != nil {
+		slog.Error("reviewer does not match the caller", "error", err, "reviewerID", reviewerID)
+		return nil, err
+	}
+
+	pr, err := uc.repo.GetPR(ctx, prID)
+	if err != nil {
+		slog.Error("failed to get PR", "error", err)
+		return nil, err
+	}
+
+	if pr.Status == entity.MERGED {
+		slog.Error("PR is already merged", "prID", prID)
+		return nil, entity.NewError(entity.PRMerged, "cannot review merged PR")
+	}
+
+	if pr.Status != entity.OPEN {
+		slog.Error("PR is not open", "prID", prID, "status", pr.Status)
+		return nil, entity.NewError(entity.PRNotOpen, "cannot review %s PR", pr.Status)
+	}
+
+	if !contains(pr.AssignReviewers, reviewerID) {
+		slog.Error("reviewer is not assigned to PR", "prID", prID, "reviewerID", reviewerID)
+		return nil, entity.NewError(entity.NotAssigned, "reviewer is not assigned to this PR")
+	}
+
+	review := &entity.Review{
+		ReviewerID: reviewerID,
+		Decision:   decision,
+		Comment:    comment,
+	}
+
+	if err := uc.repo.AddReview(ctx, prID, review); err != nil {
+		slog.Error("failed to add review", "error", err)
+		return nil, err
+	}
+
+	pr.Reviews = append(pr.Reviews, review)
+
+	slog.Info("review submitted", "prID", prID, "reviewerID", reviewerID, "decision", decision)
+	return pr, nil
+}
+
+// contains проверяет, содержит ли слайс строку
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReassignReviewer переназначает ревьюера с записью в журнал аудита; reason попадает в историю
+// назначений: ручная замена или эскалация по SLA
+func (uc *UseCase) ReassignReviewer(ctx context.Context, prID, oldReviewerID string, reason entity.AssignmentReason) (*entity.PullRequest, string, error) {
+	var (
+		pr         *entity.PullRequest
+		replacedBy string
+	)
+
+	err := uc.audited(ctx, entity.AuditReassignPR, entity.AuditPullRequest, prID, func(ctx context.Context) error {
+		var err error
+		pr, replacedBy, err = uc.reassignReviewer(ctx, prID, oldReviewerID, reason)
+		return err
+	})
+
+	return pr, replacedBy, err
+}
+
+// reassignReviewer переназначает ревьюера
+func (uc *UseCase) reassignReviewer(ctx context.Context, prID, oldReviewerID string, reason entity.AssignmentReason) (*entity.PullRequest, string, error) {
 	pr, err := uc.repo.GetPR(ctx, prID)
 	if err != nil {
 		slog.Error("failed to get PR", "error", err)
-		return nil, "", fmt.Errorf("NOT_FOUND: PR not found")
+		return nil, "", err
 	}
 
 	user, err := uc.repo.GetUser(ctx, oldReviewerID)
 	if err != nil {
 		slog.Error("failed to get user", "error", err)
-		return nil, "", fmt.Errorf("NOT_FOUND: author not found")
+		return nil, "", fmt.Errorf("get reviewer: %w", err)
 	}
 
-	excludeIDs := append(pr.AssignReviewers, pr.AuthorID)
-	candidates, err := uc.repo.GetActiveCandidates(ctx, user.TeamName, excludeIDs)
+	settings, err := uc.repo.GetTeamSettings(ctx, user.TeamName)
 	if err != nil {
-		slog.Error("failed to get active candidates", "error", err)
-		return nil, "", fmt.Errorf("get active candidates error: %v", err)
+		slog.Error("failed to get team settings", "error", err, "team", user.TeamName)
+		return nil, "", err
 	}
 
-	if len(candidates) == 0 {
-		slog.Error("no candidates on review")
-		return nil, "", fmt.Errorf("NO_CANDIDATE: no active replacement candidate in team")
+	if user.IsActive && requiredTeamLead(settings, pr.AuthorID) == oldReviewerID {
+		slog.Error("team lead review is required", "prID", prID, "teamLeadID", oldReviewerID)
+		return nil, "", entity.NewError(entity.PolicyViolated, "team %s requires team lead review", settings.TeamName)
 	}
 
-	newReviewerID, err := uc.selectLeastBusyReviewer(ctx, candidates)
+	newReviewerID, err := uc.pickReplacement(ctx, pr, user.TeamName, false)
 	if err != nil {
 		slog.Error("failed to select reviewer", "error", err)
-		return nil, "", fmt.Errorf("select reviewer error: %v", err)
+		return nil, "", fmt.Errorf("select reviewer: %w", err)
 	}
 
-	updatedPR, err := uc.repo.ReassignReviewer(ctx, prID, oldReviewerID, newReviewerID)
+	if newReviewerID == "" {
+		slog.Error("no candidates on review")
+		return nil, "", entity.NewError(entity.NoCandidate, "no active replacement candidate in team")
+	}
+
+	var updatedPR *entity.PullRequest
+	err = uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		updatedPR, err = uc.replaceReviewer(ctx, prID, oldReviewerID, newReviewerID, reason)
+		return err
+	})
+
 	if err != nil {
 		slog.Error("failed to reassign reviewer", "error", err)
-		return nil, "", fmt.Errorf("reviewer reassign error: %v", err)
+		return nil, "", fmt.Errorf("reassign reviewer: %w", err)
 	}
 
 	slog.Info("reviewer reassigned",
 		"prID", prID,
 		"oldReviewer", oldReviewerID,
-		"newReviewer", newReviewerID)
+		"newReviewer", newReviewerID,
+		"reason", reason)
 
 	return updatedPR, newReviewerID, nil
 }
 
-// selectLeastBusyReviewer выбирает одного наименее загруженного ревьюера
-func (uc *UseCase) selectLeastBusyReviewer(ctx context.Context, candidates []*entity.User) (string, error) {
+// replaceReviewer заменяет ревьюера PR и записывает замену в историю назначений;
+// вызывается внутри WithinTx
+func (uc *UseCase) replaceReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string, reason entity.AssignmentReason) (*entity.PullRequest, error) {
+	pr, err := uc.repo.ReassignReviewer(ctx, prID, oldReviewerID, newReviewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.recordReplacement(ctx, prID, reason, oldReviewerID, newReviewerID); err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+// pickReplacement выбирает замену ревьюеру PR среди активных участников команды, исключая
+// автора и уже назначенных ревьюеров; при anyTeam, если в команде замены нет, ищет среди
+// активных пользователей остальных команд. Возвращает пустую строку, если кандидатов нет
+func (uc *UseCase) pickReplacement(ctx context.Context, pr *entity.PullRequest, teamName string, anyTeam bool) (string, error) {
+	exclude := make([]string, 0, len(pr.AssignReviewers)+1)
+	exclude = append(exclude, pr.AssignReviewers...)
+	exclude = append(exclude, pr.AuthorID)
+
+	candidates, err := uc.repo.GetActiveCandidates(ctx, teamName, exclude)
+	if err != nil {
+		slog.Error("failed to get active candidates", "error", err)
+		return "", fmt.Errorf("get active candidates: %w", err)
+	}
+
+	if len(candidates) == 0 && anyTeam {
+		candidates, err = uc.repo.GetActiveUsers(ctx, exclude)
+		if err != nil {
+			slog.Error("failed to get active users", "error", err)
+			return "", fmt.Errorf("get active users: %w", err)
+		}
+	}
+
 	if len(candidates) == 0 {
-		slog.Error("no available candidates on review")
-		return "", fmt.Errorf("NOT_FOUND: no available candidates on review")
+		return "", nil
 	}
 
-	userIDs := make([]string, len(candidates))
-	for i, c := range candidates {
-		userIDs[i] = c.UserID
+	selected, err := uc.selectReviewers(ctx, teamName, candidates, 1)
+	if err != nil {
+		return "", err
 	}
 
-	workload, err := uc.repo.GetReviewersWorkload(ctx, userIDs)
+	return selected[0], nil
+}
+
+// GetPRDetails получает PR с временем назначения ревьюеров, решениями и историей переназначений
+func (uc *UseCase) GetPRDetails(ctx context.Context, prID string) (*entity.PullRequestDetails, error) {
+	pr, err := uc.repo.GetPR(ctx, prID)
 	if err != nil {
-		slog.Error("failed to get reviewers workload", "error", err)
-		return "", fmt.Errorf("NOT_FOUND: reviewers workload not found")
+		slog.Error("failed to get PR", "error", err, "prID", prID)
+		return nil, err
 	}
 
-	minLoad := -1
-	reviewerID := ""
+	reviewers, err := uc.repo.GetPRReviewers(ctx, prID)
+	if err != nil {
+		slog.Error("failed to get PR reviewers", "error", err, "prID", prID)
+		return nil, err
+	}
 
-	for _, c := range candidates {
-		load := workload[c.UserID]
-		if minLoad == -1 || load < minLoad {
-			minLoad = load
-			reviewerID = c.UserID
-		}
+	reassignments, err := uc.repo.GetPRReassignments(ctx, prID)
+	if err != nil {
+		slog.Error("failed to get PR reassignments", "error", err, "prID", prID)
+		return nil, err
 	}
 
-	return reviewerID, nil
+	return &entity.PullRequestDetails{
+		ID:            pr.ID,
+		Name:          pr.Name,
+		AuthorID:      pr.AuthorID,
+		Status:        pr.Status,
+		Reviewers:     reviewers,
+		Reviews:       pr.Reviews,
+		Reassignments: reassignments,
+		CreatedAt:     pr.CreatedAt,
+		MergedAt:      pr.MergedAt,
+		ClosedAt:      pr.ClosedAt,
+	}, nil
 }
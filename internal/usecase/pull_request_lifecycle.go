@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// prTransitions допустимые переходы между статусами pull request
+var prTransitions = map[string][]string{
+	entity.DRAFT:  {entity.OPEN, entity.CLOSED},
+	entity.OPEN:   {entity.MERGED, entity.CLOSED},
+	entity.CLOSED: {entity.OPEN},
+	entity.MERGED: {},
+}
+
+// checkTransition проверяет, что PR можно перевести из статуса from в статус to
+func checkTransition(from, to string) error {
+	for _, allowed := range prTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+
+	slog.Error("invalid PR transition", "from", from, "to", to)
+	return entity.NewError(entity.InvalidTransition, "cannot move PR from %s to %s", from, to)
+}
+
+// MarkReady переводит черновик в OPEN с записью в журнал аудита
+func (uc *UseCase) MarkReady(ctx context.Context, prID string) (*entity.PullRequest, error) {
+	return uc.auditedPR(ctx, entity.AuditMarkReadyPR, prID, uc.markReady)
+}
+
+// markReady переводит черновик в OPEN и назначает ревьюеров по политике команды
+func (uc *UseCase) markReady(ctx context.Context, prID string) (*entity.PullRequest, error) {
+	pr, err := uc.repo.GetPR(ctx, prID)
+	if err != nil {
+		slog.Error("failed to get PR", "error", err)
+		return nil, err
+	}
+
+	if pr.Status != entity.DRAFT {
+		slog.Error("PR is not a draft", "prID", prID, "status", pr.Status)
+		return nil, entity.NewError(entity.InvalidTransition, "only DRAFT PR can be marked ready, got %s", pr.Status)
+	}
+
+	return uc.openPR(ctx, pr)
+}
+
+// ClosePR закрывает PR с записью в журнал аудита
+func (uc *UseCase) ClosePR(ctx context.Context, prID string) (*entity.PullRequest, error) {
+	return uc.auditedPR(ctx, entity.AuditClosePR, prID, uc.closePR)
+}
+
+// closePR закрывает PR без мержа; ревьюеры остаются назначенными и вернутся к ревью при
+// переоткрытии, поэтому история назначений не меняется
+func (uc *UseCase) closePR(ctx context.Context, prID string) (*entity.PullRequest, error) {
+	pr, err := uc.repo.GetPR(ctx, prID)
+	if err != nil {
+		slog.Error("failed to get PR", "error", err)
+		return nil, err
+	}
+
+	if err := checkTransition(pr.Status, entity.CLOSED); err != nil {
+		return nil, err
+	}
+
+	var closed *entity.PullRequest
+	err = uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		closed, err = uc.repo.TransitionPR(ctx, prID, pr.Status, entity.CLOSED, nil)
+		if err != nil {
+			return err
+		}
+
+		return uc.emitPR(ctx, entity.EventPRClosed, closed)
+	})
+
+	if err != nil {
+		slog.Error("failed to close PR", "error", err)
+		return nil, err
+	}
+
+	slog.Info("PR closed", "prID", prID)
+	return closed, nil
+}
+
+// ReopenPR переоткрывает PR с записью в журнал аудита
+func (uc *UseCase) ReopenPR(ctx context.Context, prID string) (*entity.PullRequest, error) {
+	return uc.auditedPR(ctx, entity.AuditReopenPR, prID, uc.reopenPR)
+}
+
+// reopenPR переоткрывает закрытый PR
+func (uc *UseCase) reopenPR(ctx context.Context, prID string) (*entity.PullRequest, error) {
+	pr, err := uc.repo.GetPR(ctx, prID)
+	if err != nil {
+		slog.Error("failed to get PR", "error", err)
+		return nil, err
+	}
+
+	if pr.Status != entity.CLOSED {
+		slog.Error("PR is not closed", "prID", prID, "status", pr.Status)
+		return nil, entity.NewError(entity.InvalidTransition, "only CLOSED PR can be reopened, got %s", pr.Status)
+	}
+
+	return uc.openPR(ctx, pr)
+}
+
+// openPR переводит PR в OPEN, назначая ревьюеров, если их еще нет
+func (uc *UseCase) openPR(ctx context.Context, pr *entity.PullRequest) (*entity.PullRequest, error) {
+	if err := checkTransition(pr.Status, entity.OPEN); err != nil {
+		return nil, err
+	}
+
+	var reviewers []string
+	if len(pr.AssignReviewers) == 0 {
+		author, err := uc.repo.GetUser(ctx, pr.AuthorID)
+		if err != nil {
+			slog.Error("author not found", "error", err, "authorID", pr.AuthorID)
+			return nil, fmt.Errorf("get author: %w", err)
+		}
+
+		settings, err := uc.repo.GetTeamSettings(ctx, author.TeamName)
+		if err != nil {
+			slog.Error("failed to get team settings", "error", err, "team", author.TeamName)
+			return nil, err
+		}
+
+		reviewers, err = uc.assignReviewers(ctx, settings, pr.AuthorID)
+		if err != nil {
+			slog.Error("failed to get reviewers", "error", err)
+			return nil, err
+		}
+	}
+
+	var opened *entity.PullRequest
+	err := uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		opened, err = uc.repo.TransitionPR(ctx, pr.ID, pr.Status, entity.OPEN, reviewers)
+		if err != nil {
+			return err
+		}
+
+		return uc.recordAssignments(ctx, pr.ID, entity.ReasonPROpened, reviewers, nil)
+	})
+
+	if err != nil {
+		slog.Error("failed to open PR", "error", err)
+		return nil, err
+	}
+
+	slog.Info("PR opened", "prID", pr.ID, "from", pr.Status, "reviewersCount", len(opened.AssignReviewers))
+	return opened, nil
+}
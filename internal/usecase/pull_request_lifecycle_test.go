@@ -0,0 +1,130 @@
+package usecase_test
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"slices"
+	"testing"
+)
+
+// newPRInStatus создает PR pr1 автора u1 и переводит его в статус status
+func newPRInStatus(t *testing.T, ctx context.Context, uc *usecase.UseCase, status string) {
+	t.Helper()
+
+	if _, err := uc.CreatePR(ctx, "pr1", "feature", "u1", status == entity.DRAFT); err != nil {
+		t.Fatalf("CreatePR: %v", err)
+	}
+
+	var err error
+	switch status {
+	case entity.CLOSED:
+		_, err = uc.ClosePR(ctx, "pr1")
+	case entity.MERGED:
+		_, err = uc.MergePR(ctx, "pr1")
+	}
+
+	if err != nil {
+		t.Fatalf("move PR to %s: %v", status, err)
+	}
+}
+
+func TestPRTransitions(t *testing.T) {
+	actions := map[string]func(*usecase.UseCase, context.Context, string) (*entity.PullRequest, error){
+		"markReady": (*usecase.UseCase).MarkReady,
+		"close":     (*usecase.UseCase).ClosePR,
+		"reopen":    (*usecase.UseCase).ReopenPR,
+		"merge":     (*usecase.UseCase).MergePR,
+	}
+
+	tests := []struct {
+		from   string
+		action string
+		want   string
+	}{
+		{entity.DRAFT, "markReady", entity.OPEN},
+		{entity.DRAFT, "close", entity.CLOSED},
+		{entity.DRAFT, "reopen", ""},
+		{entity.DRAFT, "merge", ""},
+		{entity.OPEN, "markReady", ""},
+		{entity.OPEN, "close", entity.CLOSED},
+		{entity.OPEN, "reopen", ""},
+		{entity.OPEN, "merge", entity.MERGED},
+		{entity.CLOSED, "markReady", ""},
+		{entity.CLOSED, "close", ""},
+		{entity.CLOSED, "reopen", entity.OPEN},
+		{entity.CLOSED, "merge", ""},
+		{entity.MERGED, "markReady", ""},
+		{entity.MERGED, "close", ""},
+		{entity.MERGED, "reopen", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" "+tt.action, func(t *testing.T) {
+			ctx := entity.WithSystemActor(context.Background())
+			uc := usecase.New(memory.New())
+			newTeam(t, ctx, uc, 3)
+			newPRInStatus(t, ctx, uc, tt.from)
+
+			pr, err := actions[tt.action](uc, ctx, "pr1")
+			if tt.want == "" {
+				if entity.CodeOf(err) != entity.InvalidTransition {
+					t.Errorf("error = %v, want %s", err, entity.InvalidTransition)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if pr.Status != tt.want {
+				t.Errorf("status = %s, want %s", pr.Status, tt.want)
+			}
+
+			if tt.want == entity.OPEN && len(pr.AssignReviewers) != 2 {
+				t.Errorf("opened PR reviewers = %v, want 2", pr.AssignReviewers)
+			}
+		})
+	}
+}
+
+func TestClosePREmitsEventAndKeepsReviewers(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 3)
+
+	opened, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := uc.OutboxEventsAfter(ctx, entity.OutboxPosition{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.ClosePR(ctx, "pr1"); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := uc.OutboxEventsAfter(ctx, before[len(before)-1].Position())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(after) != 1 || after[0].Type != entity.EventPRClosed || after[0].TeamName != "backend" {
+		t.Fatalf("events after close = %+v, want one pr.closed", after)
+	}
+
+	reopened, err := uc.ReopenPR(ctx, "pr1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(reopened.AssignReviewers, opened.AssignReviewers) {
+		t.Errorf("reviewers after reopen = %v, want %v", reopened.AssignReviewers, opened.AssignReviewers)
+	}
+}
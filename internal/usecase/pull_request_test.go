@@ -0,0 +1,605 @@
+package usecase_test
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// newTeam создает команду backend из участников u1..uN
+func newTeam(t *testing.T, ctx context.Context, uc *usecase.UseCase, size int) {
+	t.Helper()
+
+	members := make([]*entity.TeamMember, 0, size)
+	for i := 1; i <= size; i++ {
+		id := "u" + string(rune('0'+i))
+		members = append(members, &entity.TeamMember{UserID: id, Name: "user " + id, IsActive: true})
+	}
+
+	if _, err := uc.CreateTeam(ctx, &entity.Team{Name: "backend", Members: members}); err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+}
+
+func TestCreateTeamRejectsDuplicates(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 2)
+
+	team, err := uc.GetTeam(ctx, "backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(team.Members) != 2 {
+		t.Fatalf("team has %d members, want 2", len(team.Members))
+	}
+
+	_, err = uc.CreateTeam(ctx, &entity.Team{Name: "backend", Members: []*entity.TeamMember{
+		{UserID: "u9", Name: "Zed", IsActive: true},
+	}})
+	if entity.CodeOf(err) != entity.TeamExists {
+		t.Errorf("duplicate team error = %v, want %s", err, entity.TeamExists)
+	}
+
+	_, err = uc.CreateTeam(ctx, &entity.Team{Name: "frontend", Members: []*entity.TeamMember{
+		{UserID: "u1", Name: "user u1", IsActive: true},
+	}})
+	if err == nil {
+		t.Error("member of another team was added to a new team")
+	}
+
+	if _, err := uc.GetTeam(ctx, "missing"); entity.CodeOf(err) != entity.NotFound {
+		t.Errorf("missing team error = %v, want %s", err, entity.NotFound)
+	}
+}
+
+func TestCreatePRAssignsActiveReviewersExceptAuthor(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 4)
+
+	if _, _, err := uc.SetIsActive(ctx, "u2", false); err != nil {
+		t.Fatal(err)
+	}
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pr.Status != entity.OPEN {
+		t.Errorf("status = %s, want %s", pr.Status, entity.OPEN)
+	}
+
+	slices.Sort(pr.AssignReviewers)
+	if !slices.Equal(pr.AssignReviewers, []string{"u3", "u4"}) {
+		t.Errorf("reviewers = %v, want [u3 u4]", pr.AssignReviewers)
+	}
+
+	if _, err := uc.CreatePR(ctx, "pr1", "again", "u1", false); entity.CodeOf(err) != entity.PRExists {
+		t.Errorf("duplicate PR error = %v, want %s", err, entity.PRExists)
+	}
+
+	if _, err := uc.CreatePR(ctx, "pr2", "ghost", "nobody", false); entity.CodeOf(err) != entity.NotFound {
+		t.Errorf("unknown author error = %v, want %s", err, entity.NotFound)
+	}
+
+	draft, err := uc.CreatePR(ctx, "pr3", "draft", "u1", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if draft.Status != entity.DRAFT || len(draft.AssignReviewers) != 0 {
+		t.Errorf("draft = %s with reviewers %v, want DRAFT without reviewers", draft.Status, draft.AssignReviewers)
+	}
+}
+
+func TestReassignReviewer(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 4)
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := pr.AssignReviewers[0]
+	updated, replacedBy, err := uc.ReassignReviewer(ctx, "pr1", old, entity.ReasonManualReassign)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if replacedBy == old || replacedBy == "u1" || slices.Contains(pr.AssignReviewers, replacedBy) {
+		t.Errorf("replaced %s by %s, reviewers were %v", old, replacedBy, pr.AssignReviewers)
+	}
+
+	if slices.Contains(updated.AssignReviewers, old) || !slices.Contains(updated.AssignReviewers, replacedBy) {
+		t.Errorf("reviewers after reassign = %v", updated.AssignReviewers)
+	}
+
+	_, _, err = uc.ReassignReviewer(ctx, "pr1", "u1", entity.ReasonManualReassign)
+	if entity.CodeOf(err) != entity.NotAssigned {
+		t.Errorf("reassign of author error = %v, want %s", err, entity.NotAssigned)
+	}
+
+	// снятый ревьюер — единственный кандидат, после деактивации замены нет
+	if _, _, err := uc.SetIsActive(ctx, old, false); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = uc.ReassignReviewer(ctx, "pr1", updated.AssignReviewers[0], entity.ReasonManualReassign)
+	if entity.CodeOf(err) != entity.NoCandidate {
+		t.Errorf("reassign without candidates error = %v, want %s", err, entity.NoCandidate)
+	}
+}
+
+func TestMergePRIsIdempotentAndFreezesReviewers(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 4)
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := uc.MergePR(ctx, "pr1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if merged.Status != entity.MERGED || merged.MergedAt == nil {
+		t.Fatalf("merged PR = %+v", merged)
+	}
+
+	again, err := uc.MergePR(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("second merge: %v", err)
+	}
+
+	if !again.MergedAt.Equal(*merged.MergedAt) {
+		t.Errorf("second merge changed merged_at: %v -> %v", merged.MergedAt, again.MergedAt)
+	}
+
+	_, _, err = uc.ReassignReviewer(ctx, "pr1", pr.AssignReviewers[0], entity.ReasonManualReassign)
+	if entity.CodeOf(err) != entity.PRMerged {
+		t.Errorf("reassign on merged PR error = %v, want %s", err, entity.PRMerged)
+	}
+
+	if _, err := uc.MergePR(ctx, "missing"); entity.CodeOf(err) != entity.NotFound {
+		t.Errorf("merge of missing PR error = %v, want %s", err, entity.NotFound)
+	}
+}
+
+func TestSetIsActiveReassignsOpenReviews(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 4)
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reviewer := pr.AssignReviewers[0]
+	reviews, err := uc.GetUserReviews(ctx, reviewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reviews) != 1 || reviews[0].ID != "pr1" {
+		t.Fatalf("reviews of %s = %+v, want pr1", reviewer, reviews)
+	}
+
+	user, reassignments, err := uc.SetIsActive(ctx, reviewer, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.IsActive {
+		t.Error("user is still active")
+	}
+
+	if len(reassignments) != 1 || reassignments[0].NewReviewerID == "" {
+		t.Fatalf("reassignments = %+v, want one replacement", reassignments)
+	}
+
+	reviews, err = uc.GetUserReviews(ctx, reviewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reviews) != 0 {
+		t.Errorf("inactive user still reviews %+v", reviews)
+	}
+
+	if _, _, err := uc.SetIsActive(ctx, "nobody", false); entity.CodeOf(err) != entity.NotFound {
+		t.Errorf("unknown user error = %v, want %s", err, entity.NotFound)
+	}
+}
+
+func TestInactiveMembersAreNotAssigned(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+
+	team, err := uc.CreateTeam(ctx, &entity.Team{Name: "backend", Members: []*entity.TeamMember{
+		{UserID: "u1", Name: "Alice", IsActive: true},
+		{UserID: "u2", Name: "Bob", IsActive: true},
+		{UserID: "u3", Name: "Carol", IsActive: false},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, m := range team.Members {
+		if m.IsActive != (m.UserID != "u3") {
+			t.Errorf("member %s is_active = %v", m.UserID, m.IsActive)
+		}
+	}
+
+	// деактивированный пользователь без команды не возвращается в ротацию при добавлении
+	if _, _, err := uc.SetIsActive(ctx, "u2", false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.RemoveTeamMember(ctx, "backend", "u2"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = uc.AddTeamMembers(ctx, "backend", []*entity.TeamMember{
+		{UserID: "u2", Name: "Bob", IsActive: true},
+		{UserID: "u4", Name: "Dave", IsActive: false},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pr.AssignReviewers) != 0 {
+		t.Errorf("inactive members were assigned: %v", pr.AssignReviewers)
+	}
+}
+
+func TestConcurrentReassignOfSameReviewer(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 6)
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := pr.AssignReviewers[0]
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, errs[i] = uc.ReassignReviewer(ctx, "pr1", old, entity.ReasonManualReassign)
+		}()
+	}
+
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case entity.CodeOf(err) != entity.NotAssigned:
+			t.Errorf("concurrent reassign error = %v, want %s", err, entity.NotAssigned)
+		}
+	}
+
+	if succeeded != 1 {
+		t.Fatalf("%d concurrent reassignments succeeded, want 1", succeeded)
+	}
+
+	details, err := uc.GetPRDetails(ctx, "pr1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(details.Reassignments) != 1 {
+		t.Errorf("recorded %d reassignments, want 1", len(details.Reassignments))
+	}
+}
+
+func TestRoundRobinRotationFollowsCommittedAssignments(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	repo := memory.New()
+	uc := usecase.New(repo)
+
+	members := []*entity.TeamMember{
+		{UserID: "u1", Name: "user u1", IsActive: true},
+		{UserID: "u2", Name: "user u2", IsActive: true},
+		{UserID: "u3", Name: "user u3", IsActive: true},
+		{UserID: "u4", Name: "user u4", IsActive: true},
+		{UserID: "u5", Name: "user u5", IsActive: true},
+	}
+	if _, err := uc.CreateTeam(ctx, &entity.Team{Name: "backend", ReviewerStrategy: entity.RoundRobin, Members: members}); err != nil {
+		t.Fatal(err)
+	}
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(pr.AssignReviewers, []string{"u2", "u3"}) {
+		t.Fatalf("pr1 reviewers = %v, want [u2 u3]", pr.AssignReviewers)
+	}
+
+	// пробный запуск назначает замену в откатываемой транзакции и не сдвигает очередь
+	plan, err := uc.DeactivateUsers(ctx, "", []string{"u2"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.Reassignments) != 1 || plan.Reassignments[0].NewReviewerID != "u4" {
+		t.Fatalf("dry run reassignments = %+v, want u2 replaced by u4", plan.Reassignments)
+	}
+
+	// другой экземпляр сервиса продолжает ту же очередь
+	replica := usecase.New(repo)
+	pr, err = replica.CreatePR(ctx, "pr2", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(pr.AssignReviewers, []string{"u4", "u5"}) {
+		t.Errorf("pr2 reviewers = %v, want [u4 u5]", pr.AssignReviewers)
+	}
+}
+
+// setPolicy задает политику команды backend
+func setPolicy(t *testing.T, ctx context.Context, uc *usecase.UseCase, settings entity.TeamSettings) {
+	t.Helper()
+
+	settings.TeamName = "backend"
+	if _, err := uc.UpdateTeamSettings(ctx, &entity.TeamSettingsUpdate{TeamSettings: settings}); err != nil {
+		t.Fatalf("UpdateTeamSettings: %v", err)
+	}
+}
+
+// review оставляет решение ревьюера по pr1
+func review(t *testing.T, ctx context.Context, uc *usecase.UseCase, reviewerID string, decision entity.ReviewDecision) {
+	t.Helper()
+
+	if _, err := uc.SubmitReview(ctx, "pr1", reviewerID, decision, ""); err != nil {
+		t.Fatalf("SubmitReview: %v", err)
+	}
+}
+
+func TestMergePolicyRequiresApprovals(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 4)
+	setPolicy(t, ctx, uc, entity.TeamSettings{MaxReviewers: 2, RequiredApprovals: 2})
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.MergePR(ctx, "pr1"); entity.CodeOf(err) != entity.NotApproved {
+		t.Errorf("merge without approvals error = %v, want %s", err, entity.NotApproved)
+	}
+
+	review(t, ctx, uc, pr.AssignReviewers[0], entity.Approved)
+	review(t, ctx, uc, pr.AssignReviewers[1], entity.Commented)
+
+	// комментарий не одобрение
+	if _, err := uc.MergePR(ctx, "pr1"); entity.CodeOf(err) != entity.NotApproved {
+		t.Errorf("merge with 1 of 2 approvals error = %v, want %s", err, entity.NotApproved)
+	}
+
+	setPolicy(t, ctx, uc, entity.TeamSettings{MinReviewers: 3, MaxReviewers: 3, RequiredApprovals: 1})
+	if _, err := uc.MergePR(ctx, "pr1"); entity.CodeOf(err) != entity.PolicyViolated {
+		t.Errorf("merge with too few reviewers error = %v, want %s", err, entity.PolicyViolated)
+	}
+
+	setPolicy(t, ctx, uc, entity.TeamSettings{MaxReviewers: 2, RequiredApprovals: 2})
+	review(t, ctx, uc, pr.AssignReviewers[1], entity.Approved)
+
+	merged, err := uc.MergePR(ctx, "pr1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if merged.Status != entity.MERGED {
+		t.Errorf("status = %s, want %s", merged.Status, entity.MERGED)
+	}
+}
+
+func TestMergePolicyChangesRequestedBlocks(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 3)
+	setPolicy(t, ctx, uc, entity.TeamSettings{MaxReviewers: 2, RequiredApprovals: 1})
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	review(t, ctx, uc, pr.AssignReviewers[0], entity.Approved)
+	review(t, ctx, uc, pr.AssignReviewers[1], entity.ChangesRequested)
+
+	if _, err := uc.MergePR(ctx, "pr1"); entity.CodeOf(err) != entity.NotApproved {
+		t.Errorf("merge with requested changes error = %v, want %s", err, entity.NotApproved)
+	}
+
+	// учитывается последнее значимое решение ревьюера
+	review(t, ctx, uc, pr.AssignReviewers[1], entity.Approved)
+	review(t, ctx, uc, pr.AssignReviewers[1], entity.Commented)
+
+	if _, err := uc.MergePR(ctx, "pr1"); err != nil {
+		t.Errorf("merge after changes were approved: %v", err)
+	}
+}
+
+func TestMergePolicyRequiresTeamLeadApproval(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 4)
+	setPolicy(t, ctx, uc, entity.TeamSettings{
+		MaxReviewers:      2,
+		RequiredApprovals: 1,
+		RequireTeamLead:   true,
+		TeamLeadID:        "u2",
+	})
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Contains(pr.AssignReviewers, "u2") {
+		t.Fatalf("reviewers = %v, want team lead u2", pr.AssignReviewers)
+	}
+
+	other := pr.AssignReviewers[0]
+	if other == "u2" {
+		other = pr.AssignReviewers[1]
+	}
+
+	review(t, ctx, uc, other, entity.Approved)
+	if _, err := uc.MergePR(ctx, "pr1"); entity.CodeOf(err) != entity.NotApproved {
+		t.Errorf("merge without team lead approval error = %v, want %s", err, entity.NotApproved)
+	}
+
+	review(t, ctx, uc, "u2", entity.Approved)
+	if _, err := uc.MergePR(ctx, "pr1"); err != nil {
+		t.Errorf("merge approved by team lead: %v", err)
+	}
+
+	// PR самого тимлида его одобрения не ждет
+	if _, err := uc.CreatePR(ctx, "pr2", "lead feature", "u2", false); err != nil {
+		t.Fatal(err)
+	}
+
+	leadPR, err := uc.SubmitReview(ctx, "pr2", "u1", entity.Approved, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if slices.Contains(leadPR.AssignReviewers, "u2") {
+		t.Fatalf("author u2 is a reviewer of own PR: %v", leadPR.AssignReviewers)
+	}
+
+	if _, err := uc.MergePR(ctx, "pr2"); err != nil {
+		t.Errorf("merge of team lead PR: %v", err)
+	}
+}
+
+func TestMergePolicyIgnoresDecisionsOfReassignedReviewers(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 4)
+	setPolicy(t, ctx, uc, entity.TeamSettings{MaxReviewers: 2, RequiredApprovals: 1})
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// в команде один свободный участник, поэтому замена однозначна
+	approver := pr.AssignReviewers[0]
+	review(t, ctx, uc, approver, entity.Approved)
+
+	_, spare, err := uc.ReassignReviewer(ctx, "pr1", approver, entity.ReasonManualReassign)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.MergePR(ctx, "pr1"); entity.CodeOf(err) != entity.NotApproved {
+		t.Errorf("merge with approval of reassigned reviewer error = %v, want %s", err, entity.NotApproved)
+	}
+
+	review(t, ctx, uc, spare, entity.ChangesRequested)
+
+	_, back, err := uc.ReassignReviewer(ctx, "pr1", spare, entity.ReasonManualReassign)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if back != approver {
+		t.Fatalf("replacement = %s, want %s back", back, approver)
+	}
+
+	// запрос изменений снятого ревьюера больше не блокирует, а одобрение вернувшегося
+	// ревьюера дано до его нового назначения
+	if _, err := uc.MergePR(ctx, "pr1"); entity.CodeOf(err) != entity.NotApproved {
+		t.Errorf("merge with approval before reassignment error = %v, want %s", err, entity.NotApproved)
+	}
+
+	review(t, ctx, uc, approver, entity.Approved)
+	if _, err := uc.MergePR(ctx, "pr1"); err != nil {
+		t.Errorf("merge approved by current reviewer: %v", err)
+	}
+}
+
+func TestActingOnBehalfOfAnotherUser(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 4)
+
+	if _, err := uc.BindRole(ctx, &entity.RoleBinding{UserID: "u4", Role: entity.RoleTeamLead, TeamName: "backend"}); err != nil {
+		t.Fatal(err)
+	}
+
+	as := func(id string) context.Context {
+		return entity.WithActor(context.Background(), entity.Actor{ID: id, Roles: []string{entity.RoleUser}})
+	}
+
+	if _, err := uc.CreatePR(context.Background(), "pr0", "feature", "u1", false); entity.CodeOf(err) != entity.Unauthorized {
+		t.Errorf("anonymous CreatePR error = %v, want %s", err, entity.Unauthorized)
+	}
+
+	if _, err := uc.CreatePR(as("u2"), "pr0", "feature", "u1", false); entity.CodeOf(err) != entity.Forbidden {
+		t.Errorf("CreatePR for another author error = %v, want %s", err, entity.Forbidden)
+	}
+
+	pr, err := uc.CreatePR(as("u1"), "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatalf("CreatePR of own PR: %v", err)
+	}
+
+	// лидер команды вправе создавать PR за участников своей команды
+	if _, err := uc.CreatePR(as("u4"), "pr2", "feature", "u2", false); err != nil {
+		t.Errorf("team lead CreatePR for member: %v", err)
+	}
+
+	// автор не может оставить ревью за назначенного ревьюера
+	i := slices.IndexFunc(pr.AssignReviewers, func(id string) bool { return id != "u4" })
+	if i < 0 {
+		t.Fatalf("reviewers = %v, want a reviewer besides the team lead", pr.AssignReviewers)
+	}
+
+	reviewer := pr.AssignReviewers[i]
+	if _, err := uc.SubmitReview(as("u1"), "pr1", reviewer, entity.Approved, ""); entity.CodeOf(err) != entity.Forbidden {
+		t.Errorf("author review on behalf of %s error = %v, want %s", reviewer, err, entity.Forbidden)
+	}
+
+	if _, err := uc.SubmitReview(as(reviewer), "pr1", reviewer, entity.Approved, ""); err != nil {
+		t.Errorf("own review: %v", err)
+	}
+
+	// ревью оставляют только от своего имени, даже лидер команды
+	if _, err := uc.SubmitReview(as("u4"), "pr1", reviewer, entity.Approved, ""); entity.CodeOf(err) != entity.Forbidden {
+		t.Errorf("team lead review on behalf of %s error = %v, want %s", reviewer, err, entity.Forbidden)
+	}
+}
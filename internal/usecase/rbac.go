@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Authorize проверяет, что у инициатора есть право perm: через роли из токена или глобальные
+// привязки, либо через привязки во всех командах, к которым относится target. Если права нет,
+// но есть его вариант для своих объектов (OwnPermissions), достаточно владеть target.
+// API-ключу доступны только права из его scopes
+func (uc *UseCase) Authorize(ctx context.Context, actor entity.Actor, perm entity.Permission, target entity.AuthTarget) error {
+	if actor.IsAnonymous() {
+		return entity.NewError(entity.Unauthorized, "caller is not authenticated")
+	}
+
+	denied := entity.NewError(entity.Forbidden, "permission %s required", perm)
+	if actor.IsAPIKey() {
+		if slices.Contains(actor.Scopes, perm) {
+			return nil
+		}
+
+		return denied
+	}
+
+	ok, err := uc.granted(ctx, actor, perm, target)
+	if err != nil || ok {
+		return err
+	}
+
+	own, hasOwn := entity.OwnPermissions[perm]
+	if !hasOwn {
+		return denied
+	}
+
+	if ok, err = uc.granted(ctx, actor, own, target); err != nil || !ok {
+		if err != nil {
+			return err
+		}
+
+		return denied
+	}
+
+	owner, err := uc.ownsTarget(ctx, actor, target)
+	if err != nil {
+		return err
+	}
+
+	if !owner {
+		return entity.NewError(entity.Forbidden, "permission %s required to act on behalf of another user", perm)
+	}
+
+	return nil
+}
+
+// granted проверяет право perm по ролям из токена и привязкам ролей
+func (uc *UseCase) granted(ctx context.Context, actor entity.Actor, perm entity.Permission, target entity.AuthTarget) (bool, error) {
+	for _, role := range actor.Roles {
+		if entity.RoleGrants(role, perm) {
+			return true, nil
+		}
+	}
+
+	bindings, err := uc.repo.GetRoleBindings(ctx, entity.RoleBindingFilter{UserID: actor.ID})
+	if err != nil {
+		slog.Error("failed to get role bindings", "error", err, "userID", actor.ID)
+		return false, err
+	}
+
+	teamGrants := make(map[string]bool)
+	for _, b := range bindings {
+		if !entity.RoleGrants(b.Role, perm) {
+			continue
+		}
+
+		if b.TeamName == "" {
+			return true, nil
+		}
+
+		teamGrants[b.TeamName] = true
+	}
+
+	if len(teamGrants) == 0 || target.IsEmpty() {
+		return false, nil
+	}
+
+	teams, err := uc.targetTeams(ctx, target)
+	if err != nil {
+		if errors.Is(err, entity.NotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if len(teams) == 0 {
+		return false, nil
+	}
+
+	for _, team := range teams {
+		if !teamGrants[team] {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// ownsTarget проверяет, что запрос действует от имени самого инициатора: все OwnerIDs совпадают
+// с ним, а без них он автор PR
+func (uc *UseCase) ownsTarget(ctx context.Context, actor entity.Actor, target entity.AuthTarget) (bool, error) {
+	if len(target.OwnerIDs) > 0 {
+		for _, id := range target.OwnerIDs {
+			if id != actor.ID {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}
+
+	if target.PRID == "" {
+		return false, nil
+	}
+
+	pr, err := uc.repo.GetPR(ctx, target.PRID)
+	if err != nil {
+		if errors.Is(err, entity.NotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return pr.AuthorID == actor.ID, nil
+}
+
+// targetTeams определяет команды объектов запроса; пользователь без команды дает пустую
+// команду, на которую командные привязки не распространяются
+func (uc *UseCase) targetTeams(ctx context.Context, target entity.AuthTarget) ([]string, error) {
+	teams := append([]string(nil), target.Teams...)
+	userIDs := append([]string(nil), target.UserIDs...)
+
+	if target.AbsenceID != 0 {
+		absence, err := uc.repo.GetAbsence(ctx, target.AbsenceID)
+		if err != nil {
+			return nil, err
+		}
+
+		userIDs = append(userIDs, absence.UserID)
+	}
+
+	if target.PRID != "" {
+		pr, err := uc.repo.GetPR(ctx, target.PRID)
+		if err != nil {
+			return nil, err
+		}
+
+		userIDs = append(userIDs, pr.AuthorID)
+	}
+
+	for _, userID := range userIDs {
+		user, err := uc.repo.GetUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		teams = append(teams, user.TeamName)
+	}
+
+	return teams, nil
+}
+
+// GetRoleBindings получает привязки ролей по фильтру
+func (uc *UseCase) GetRoleBindings(ctx context.Context, filter entity.RoleBindingFilter) ([]*entity.RoleBinding, error) {
+	bindings, err := uc.repo.GetRoleBindings(ctx, filter)
+	if err != nil {
+		slog.Error("failed to get role bindings", "error", err)
+		return nil, err
+	}
+
+	return bindings, nil
+}
+
+// BindRole выдает пользователю роль глобально или в команде с записью в журнал аудита
+func (uc *UseCase) BindRole(ctx context.Context, binding *entity.RoleBinding) (*entity.RoleBinding, error) {
+	binding.Role = strings.ToUpper(strings.TrimSpace(binding.Role))
+	if binding.UserID == "" {
+		return nil, entity.NewError(entity.InvalidRequest, "user_id is required")
+	}
+
+	if _, ok := entity.RolePermissions[binding.Role]; !ok {
+		return nil, entity.NewError(entity.InvalidRequest, "unknown role %s", binding.Role)
+	}
+
+	if binding.Role == entity.RoleTeamLead && binding.TeamName == "" {
+		return nil, entity.NewError(entity.InvalidRequest, "role %s must be bound to a team", binding.Role)
+	}
+
+	binding.CreatedBy = entity.ActorFromContext(ctx).ID
+	err := uc.audited(ctx, entity.AuditBindRole, entity.AuditUser, binding.UserID, func(ctx context.Context) error {
+		return uc.repo.AddRoleBinding(ctx, binding)
+	})
+
+	if err != nil {
+		slog.Error("failed to bind role", "error", err, "userID", binding.UserID, "role", binding.Role)
+		return nil, err
+	}
+
+	slog.Info("role bound", "userID", binding.UserID, "role", binding.Role, "team", binding.TeamName)
+	return binding, nil
+}
+
+// UnbindRole отзывает привязку роли с записью в журнал аудита
+func (uc *UseCase) UnbindRole(ctx context.Context, id int64) (*entity.RoleBinding, error) {
+	var binding *entity.RoleBinding
+	err := uc.audited(ctx, entity.AuditUnbindRole, entity.AuditRoleBinding, strconv.FormatInt(id, 10), func(ctx context.Context) error {
+		var err error
+		binding, err = uc.repo.DeleteRoleBinding(ctx, id)
+		return err
+	})
+
+	if err != nil {
+		slog.Error("failed to unbind role", "error", err, "bindingID", id)
+		return nil, err
+	}
+
+	slog.Info("role unbound", "userID", binding.UserID, "role", binding.Role, "team", binding.TeamName)
+	return binding, nil
+}
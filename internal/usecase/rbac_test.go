@@ -0,0 +1,174 @@
+package usecase_test
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"testing"
+)
+
+func TestBindRole(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 2)
+
+	invalid := []struct {
+		name    string
+		binding entity.RoleBinding
+	}{
+		{"no user", entity.RoleBinding{Role: entity.RoleAdmin}},
+		{"unknown role", entity.RoleBinding{UserID: "u1", Role: "OWNER"}},
+		{"global team lead", entity.RoleBinding{UserID: "u1", Role: entity.RoleTeamLead}},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.BindRole(ctx, &tt.binding); entity.CodeOf(err) != entity.InvalidRequest {
+				t.Errorf("BindRole error = %v, want %s", err, entity.InvalidRequest)
+			}
+		})
+	}
+
+	binding, err := uc.BindRole(ctx, &entity.RoleBinding{UserID: "u1", Role: " team_lead ", TeamName: "backend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if binding.ID == 0 || binding.Role != entity.RoleTeamLead || binding.CreatedBy != entity.SystemActor {
+		t.Errorf("binding = %+v", binding)
+	}
+
+	_, err = uc.BindRole(ctx, &entity.RoleBinding{UserID: "u1", Role: entity.RoleTeamLead, TeamName: "backend"})
+	if entity.CodeOf(err) != entity.BindingExists {
+		t.Errorf("duplicate binding error = %v, want %s", err, entity.BindingExists)
+	}
+
+	bindings, err := uc.GetRoleBindings(ctx, entity.RoleBindingFilter{TeamName: "backend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bindings) != 1 || bindings[0].ID != binding.ID {
+		t.Errorf("bindings = %+v, want the created one", bindings)
+	}
+
+	unbound, err := uc.UnbindRole(ctx, binding.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if unbound.UserID != "u1" {
+		t.Errorf("unbound = %+v", unbound)
+	}
+
+	if _, err := uc.UnbindRole(ctx, binding.ID); entity.CodeOf(err) != entity.NotFound {
+		t.Errorf("second unbind error = %v, want %s", err, entity.NotFound)
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 3)
+
+	if _, err := uc.CreateTeam(ctx, &entity.Team{Name: "frontend", Members: []*entity.TeamMember{
+		{UserID: "f1", Name: "Fred", IsActive: true},
+		{UserID: "f2", Name: "Fiona", IsActive: true},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pr := range []struct{ id, author string }{{"pr-backend", "u1"}, {"pr-frontend", "f1"}} {
+		if _, err := uc.CreatePR(ctx, pr.id, "feature", pr.author, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, b := range []*entity.RoleBinding{
+		{UserID: "u3", Role: entity.RoleTeamLead, TeamName: "backend"},
+		{UserID: "auditor", Role: entity.RoleAdmin},
+	} {
+		if _, err := uc.BindRole(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	user := func(id string) entity.Actor { return entity.Actor{ID: id, Roles: []string{entity.RoleUser}} }
+	tests := []struct {
+		name   string
+		actor  entity.Actor
+		perm   entity.Permission
+		target entity.AuthTarget
+		want   entity.ErrorCode
+	}{
+		{"anonymous", entity.Actor{}, entity.PermTeamRead, entity.AuthTarget{}, entity.Unauthorized},
+		{"token role", entity.Actor{ID: "admin", Roles: []string{entity.RoleAdmin}}, entity.PermTeamManage, entity.AuthTarget{}, ""},
+		{"token role without permission", user("u2"), entity.PermTeamManage, entity.AuthTarget{Teams: []string{"backend"}}, entity.Forbidden},
+		{"global binding", user("auditor"), entity.PermTeamManage, entity.AuthTarget{Teams: []string{"frontend"}}, ""},
+		{"global binding without target", user("auditor"), entity.PermRBACManage, entity.AuthTarget{}, ""},
+		{"team binding by PR", user("u3"), entity.PermPRMerge, entity.AuthTarget{PRID: "pr-backend"}, ""},
+		{"team binding by users", user("u3"), entity.PermUserDeactivate, entity.AuthTarget{UserIDs: []string{"u1", "u2"}}, ""},
+		{"team binding in another team", user("u3"), entity.PermPRMerge, entity.AuthTarget{PRID: "pr-frontend"}, entity.Forbidden},
+		{"team binding without target", user("u3"), entity.PermPRMerge, entity.AuthTarget{}, entity.Forbidden},
+		{"team binding for unknown PR", user("u3"), entity.PermPRMerge, entity.AuthTarget{PRID: "pr-missing"}, entity.Forbidden},
+		{"team binding without permission", user("u3"), entity.PermTeamManage, entity.AuthTarget{Teams: []string{"backend"}}, entity.Forbidden},
+		{"team binding covers all teams", user("u3"), entity.PermUserDeactivate, entity.AuthTarget{Teams: []string{"backend", "backend"}}, ""},
+		{"team binding covers one of teams", user("u3"), entity.PermUserDeactivate, entity.AuthTarget{Teams: []string{"backend", "frontend"}}, entity.Forbidden},
+		{"team binding covers one of users", user("u3"), entity.PermUserDeactivate, entity.AuthTarget{UserIDs: []string{"u1", "f1"}}, entity.Forbidden},
+		{"own PR", user("u1"), entity.PermPRMerge, entity.AuthTarget{PRID: "pr-backend"}, ""},
+		{"PR of another author", user("u2"), entity.PermPRMerge, entity.AuthTarget{PRID: "pr-backend"}, entity.Forbidden},
+		{"on own behalf", user("u2"), entity.PermPRCreate, entity.AuthTarget{Teams: []string{"backend"}, OwnerIDs: []string{"u2"}}, ""},
+		{"on behalf of another user", user("u2"), entity.PermPRCreate, entity.AuthTarget{Teams: []string{"backend"}, OwnerIDs: []string{"u2", "u1"}}, entity.Forbidden},
+		{"no own variant", user("u1"), entity.PermTeamManage, entity.AuthTarget{Teams: []string{"backend"}, OwnerIDs: []string{"u1"}}, entity.Forbidden},
+		{"team lead acts on behalf of member", user("u3"), entity.PermPRCreate, entity.AuthTarget{Teams: []string{"backend"}, OwnerIDs: []string{"u1"}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := uc.Authorize(ctx, tt.actor, tt.perm, tt.target)
+			if tt.want == "" && err != nil {
+				t.Errorf("Authorize error = %v, want allowed", err)
+			}
+
+			if tt.want != "" && entity.CodeOf(err) != tt.want {
+				t.Errorf("Authorize error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnbindRoleRevokesTeamAccess(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 3)
+
+	binding, err := uc.BindRole(ctx, &entity.RoleBinding{UserID: "u3", Role: entity.RoleTeamLead, TeamName: "backend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lead := entity.Actor{ID: "u3", Roles: []string{entity.RoleUser}}
+	target := entity.AuthTarget{Teams: []string{"backend"}, UserIDs: []string{"u1"}}
+	if err := uc.Authorize(ctx, lead, entity.PermUserDeactivate, target); err != nil {
+		t.Fatalf("team lead error = %v, want allowed", err)
+	}
+
+	if _, err := uc.UnbindRole(ctx, binding.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := uc.Authorize(ctx, lead, entity.PermUserDeactivate, target); entity.CodeOf(err) != entity.Forbidden {
+		t.Errorf("unbound team lead error = %v, want %s", err, entity.Forbidden)
+	}
+
+	// снятие и выдача ролей попадают в журнал аудита
+	entries, _, err := uc.ListAuditEntries(ctx, entity.AuditFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) < 2 || entries[0].Action != entity.AuditUnbindRole || entries[1].Action != entity.AuditBindRole {
+		t.Errorf("audit entries = %+v, want bind and unbind", entries)
+	}
+}
@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"log/slog"
+	"sort"
+)
+
+// rebalanceLock имя блокировки лидера задачи перераспределения нагрузки
+const rebalanceLock = "rebalance"
+
+// Rebalance перераспределяет открытые ревью между активными участниками команды с записью
+// в журнал аудита; пробный запуск ничего не меняет и в журнал не попадает
+func (uc *UseCase) Rebalance(ctx context.Context, teamName string, dryRun bool) (*entity.Rebalance, error) {
+	if dryRun {
+		return uc.rebalance(ctx, teamName, true)
+	}
+
+	var result *entity.Rebalance
+	err := uc.audited(ctx, entity.AuditRebalanceTeam, entity.AuditTeam, teamName, func(ctx context.Context) error {
+		var err error
+		result, err = uc.rebalance(ctx, teamName, false)
+		return err
+	})
+
+	return result, err
+}
+
+// rebalance считает нагрузку активных участников команды через GetReviewersWorkload, делит
+// ее поровну и передает ревью от перегруженных недогруженным одной транзакцией, с причиной
+// REBALANCE в истории назначений. При dryRun передачи только рассчитываются
+func (uc *UseCase) rebalance(ctx context.Context, teamName string, dryRun bool) (*entity.Rebalance, error) {
+	if teamName == "" {
+		return nil, entity.NewError(entity.InvalidRequest, "team_name is required")
+	}
+
+	result := &entity.Rebalance{TeamName: teamName, DryRun: dryRun}
+	err := uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		exists, err := uc.repo.TeamExists(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return entity.ErrTeamNotFound
+		}
+
+		members, err := uc.repo.GetActiveCandidates(ctx, teamName, nil)
+		if err != nil {
+			slog.Error("failed to get active candidates", "error", err, "team", teamName)
+			return err
+		}
+
+		workload, err := uc.repo.GetReviewersWorkload(ctx, userIDs(members))
+		if err != nil {
+			slog.Error("failed to get reviewers workload", "error", err, "team", teamName)
+			return err
+		}
+
+		result.WorkloadBefore = make(map[string]int, len(workload))
+		for id, n := range workload {
+			result.WorkloadBefore[id] = n
+		}
+
+		result.Target = rebalanceTarget(workload)
+		result.Moves, err = uc.planRebalance(ctx, workload, result.Target)
+		if err != nil {
+			return err
+		}
+
+		result.WorkloadAfter = workload
+		if dryRun {
+			return nil
+		}
+
+		for _, move := range result.Moves {
+			if _, err := uc.replaceReviewer(ctx, move.PullRequestID, move.FromUserID, move.ToUserID, entity.ReasonRebalance); err != nil {
+				slog.Error("failed to reassign reviewer", "error", err, "prID", move.PullRequestID)
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		slog.Error("failed to rebalance team workload", "error", err, "team", teamName)
+		return nil, err
+	}
+
+	if len(result.Moves) == 0 {
+		slog.Debug("team workload is balanced", "team", teamName)
+		return result, nil
+	}
+
+	slog.Info("team workload rebalanced", "team", teamName, "dryRun", dryRun, "moves", len(result.Moves))
+	return result, nil
+}
+
+// rebalanceTarget делит суммарную нагрузку поровну; остаток достается самым загруженным,
+// так до цели нужно меньше всего передач
+func rebalanceTarget(workload map[string]int) map[string]int {
+	ids := byWorkload(workload)
+	target := make(map[string]int, len(ids))
+	if len(ids) == 0 {
+		return target
+	}
+
+	total := 0
+	for _, n := range workload {
+		total += n
+	}
+
+	base, extra := total/len(ids), total%len(ids)
+	for i, id := range ids {
+		target[id] = base
+		if i < extra {
+			target[id]++
+		}
+	}
+
+	return target
+}
+
+// planRebalance подбирает передачи открытых ревью от участников с нагрузкой выше целевой
+// наименее загруженным участникам ниже целевой; workload обновляется по мере подбора.
+// Не передаются ревью, по которым ревьюер уже высказался, и ревью обязательного тимлида
+func (uc *UseCase) planRebalance(ctx context.Context, workload, target map[string]int) ([]*entity.RebalanceMove, error) {
+	moves := make([]*entity.RebalanceMove, 0)
+	prs := make(map[string]*entity.PullRequest)
+	settings := make(map[string]*entity.TeamSettings)
+
+	for _, fromID := range byWorkload(workload) {
+		if workload[fromID] <= target[fromID] {
+			continue
+		}
+
+		reviews, err := uc.repo.GetReview(ctx, fromID)
+		if err != nil {
+			slog.Error("failed to get user reviews", "error", err, "userID", fromID)
+			return nil, err
+		}
+
+		for _, review := range reviews {
+			if workload[fromID] <= target[fromID] {
+				break
+			}
+
+			if review.Status != entity.OPEN {
+				continue
+			}
+
+			pr, ok := prs[review.ID]
+			if !ok {
+				if pr, err = uc.repo.GetPR(ctx, review.ID); err != nil {
+					slog.Error("failed to get PR", "error", err, "prID", review.ID)
+					return nil, err
+				}
+
+				prs[pr.ID] = pr
+			}
+
+			movable, err := uc.canRebalance(ctx, pr, fromID, settings)
+			if err != nil {
+				return nil, err
+			}
+
+			if !movable {
+				continue
+			}
+
+			toID := rebalanceReceiver(pr, workload, target)
+			if toID == "" {
+				continue
+			}
+
+			for i, id := range pr.AssignReviewers {
+				if id == fromID {
+					pr.AssignReviewers[i] = toID
+				}
+			}
+
+			workload[fromID]--
+			workload[toID]++
+			moves = append(moves, &entity.RebalanceMove{
+				PullRequestID: pr.ID,
+				FromUserID:    fromID,
+				ToUserID:      toID,
+			})
+		}
+	}
+
+	return moves, nil
+}
+
+// canRebalance проверяет, что ревью можно передать: ревьюер еще ничего не написал в PR
+// и не является тимлидом, чье ревью требует политика команды автора
+func (uc *UseCase) canRebalance(ctx context.Context, pr *entity.PullRequest, reviewerID string, settings map[string]*entity.TeamSettings) (bool, error) {
+	for _, review := range pr.Reviews {
+		if review.ReviewerID == reviewerID {
+			return false, nil
+		}
+	}
+
+	author, err := uc.repo.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		slog.Error("author not found", "error", err, "authorID", pr.AuthorID)
+		return false, err
+	}
+
+	teamSettings, ok := settings[author.TeamName]
+	if !ok {
+		if teamSettings, err = uc.repo.GetTeamSettings(ctx, author.TeamName); err != nil {
+			slog.Error("failed to get team settings", "error", err, "team", author.TeamName)
+			return false, err
+		}
+
+		settings[author.TeamName] = teamSettings
+	}
+
+	return requiredTeamLead(teamSettings, pr.AuthorID) != reviewerID, nil
+}
+
+// rebalanceReceiver выбирает наименее загруженного участника ниже целевой нагрузки, который
+// не автор PR и еще не назначен на него; пустая строка, если такого нет
+func rebalanceReceiver(pr *entity.PullRequest, workload, target map[string]int) string {
+	ids := byWorkload(workload)
+	for i := len(ids) - 1; i >= 0; i-- {
+		id := ids[i]
+		if workload[id] >= target[id] {
+			continue
+		}
+
+		if id != pr.AuthorID && !contains(pr.AssignReviewers, id) {
+			return id
+		}
+	}
+
+	return ""
+}
+
+// byWorkload возвращает участников от самых загруженных к наименее, при равенстве по user_id
+func byWorkload(workload map[string]int) []string {
+	ids := make([]string, 0, len(workload))
+	for id := range workload {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		if workload[ids[i]] != workload[ids[j]] {
+			return workload[ids[i]] > workload[ids[j]]
+		}
+
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
+
+// RebalanceTeams перераспределяет нагрузку во всех командах; передачи применяются только
+// там, где пробный расчет их нашел, чтобы не писать в журнал аудита пустые проходы. Команда,
+// которую не удалось обработать из-за доменной ошибки, пропускается. Проход выполняет только
+// экземпляр, взявший блокировку лидера; возвращает число выполненных передач
+func (uc *UseCase) RebalanceTeams(ctx context.Context) (int, error) {
+	moved := 0
+	leader, err := uc.repo.WithLeaderLock(ctx, rebalanceLock, func(ctx context.Context) error {
+		filter := entity.TeamFilter{Page: entity.Page{Limit: entity.MaxPageLimit}}
+		for {
+			teams, err := uc.repo.ListTeams(ctx, filter)
+			if err != nil {
+				return err
+			}
+
+			for _, team := range teams {
+				n, err := uc.rebalanceTeam(ctx, team.Name)
+				if err != nil {
+					return err
+				}
+
+				moved += n
+			}
+
+			if len(teams) < filter.Limit {
+				return nil
+			}
+
+			filter.After = &entity.Cursor{ID: teams[len(teams)-1].Name}
+		}
+	})
+
+	if err != nil {
+		slog.Error("failed to rebalance teams", "error", err)
+		return moved, err
+	}
+
+	if !leader {
+		slog.Debug("rebalance is run by another instance")
+	}
+
+	return moved, nil
+}
+
+// rebalanceTeam перераспределяет нагрузку одной команды, если пробный расчет нашел передачи
+func (uc *UseCase) rebalanceTeam(ctx context.Context, teamName string) (int, error) {
+	plan, err := uc.Rebalance(ctx, teamName, true)
+	if err == nil && len(plan.Moves) > 0 {
+		plan, err = uc.Rebalance(ctx, teamName, false)
+	}
+
+	if err == nil {
+		return len(plan.Moves), nil
+	}
+
+	if entity.CodeOf(err) == entity.InternalServer {
+		return 0, err
+	}
+
+	slog.Warn("team is skipped by rebalance", "error", err, "team", teamName)
+	return 0, nil
+}
@@ -0,0 +1,217 @@
+package usecase_test
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"maps"
+	"slices"
+	"testing"
+)
+
+// newUnbalancedTeam создает команду u1..u6, в которой u5 и u6 были неактивны, пока u1..u4
+// открывали по PR, поэтому ревью не досталось только им; при requireLead тимлид u2 обязателен
+func newUnbalancedTeam(t *testing.T, ctx context.Context, uc *usecase.UseCase, requireLead bool) {
+	t.Helper()
+
+	newTeam(t, ctx, uc, 6)
+
+	if requireLead {
+		_, err := uc.UpdateTeamSettings(ctx, &entity.TeamSettingsUpdate{TeamSettings: entity.TeamSettings{
+			TeamName:        "backend",
+			MaxReviewers:    2,
+			RequireTeamLead: true,
+			TeamLeadID:      "u2",
+		}})
+		if err != nil {
+			t.Fatalf("UpdateTeamSettings: %v", err)
+		}
+	}
+
+	for _, id := range []string{"u5", "u6"} {
+		if _, _, err := uc.SetIsActive(ctx, id, false); err != nil {
+			t.Fatalf("SetIsActive: %v", err)
+		}
+	}
+
+	for i := 1; i <= 4; i++ {
+		n := string(rune('0' + i))
+		if _, err := uc.CreatePR(ctx, "pr"+n, "feature", "u"+n, false); err != nil {
+			t.Fatalf("CreatePR: %v", err)
+		}
+	}
+
+	for _, id := range []string{"u5", "u6"} {
+		if _, _, err := uc.SetIsActive(ctx, id, true); err != nil {
+			t.Fatalf("SetIsActive: %v", err)
+		}
+	}
+}
+
+// openReviews число открытых ревью каждого участника u1..u6
+func openReviews(t *testing.T, ctx context.Context, uc *usecase.UseCase) map[string]int {
+	t.Helper()
+
+	workload := make(map[string]int)
+	for i := 1; i <= 6; i++ {
+		id := "u" + string(rune('0'+i))
+		reviews, err := uc.GetUserReviews(ctx, id)
+		if err != nil {
+			t.Fatalf("GetUserReviews: %v", err)
+		}
+
+		for _, review := range reviews {
+			if review.Status == entity.OPEN {
+				workload[id]++
+			}
+		}
+	}
+
+	return workload
+}
+
+// rebalanceEntries число записей истории назначений pr1..pr4 с причиной REBALANCE
+func rebalanceEntries(t *testing.T, ctx context.Context, uc *usecase.UseCase) int {
+	t.Helper()
+
+	n := 0
+	for i := 1; i <= 4; i++ {
+		history, err := uc.GetAssignmentHistory(ctx, entity.AssignmentHistoryFilter{
+			PullRequestID: "pr" + string(rune('0'+i)),
+		})
+		if err != nil {
+			t.Fatalf("GetAssignmentHistory: %v", err)
+		}
+
+		for _, h := range history {
+			if h.Reason == entity.ReasonRebalance {
+				n++
+			}
+		}
+	}
+
+	return n
+}
+
+func TestRebalanceMovesMinimalSetToTarget(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newUnbalancedTeam(t, ctx, uc, false)
+
+	result, err := uc.Rebalance(ctx, "backend", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total, excess := 0, 0
+	for id, n := range result.WorkloadBefore {
+		total += n
+		excess += max(0, n-result.Target[id])
+	}
+
+	if total != 8 {
+		t.Fatalf("workload before = %v, want 8 open reviews", result.WorkloadBefore)
+	}
+
+	// 8 ревью на 6 участников: цель 2 у двоих и 1 у остальных
+	targets := slices.Sorted(maps.Values(result.Target))
+	if !slices.Equal(targets, []int{1, 1, 1, 1, 2, 2}) {
+		t.Errorf("target = %v, want two members with 2 and four with 1", result.Target)
+	}
+
+	if len(result.Moves) != excess {
+		t.Errorf("moves = %d, want the minimal %d", len(result.Moves), excess)
+	}
+
+	if !maps.Equal(result.WorkloadAfter, result.Target) {
+		t.Errorf("workload after = %v, want target %v", result.WorkloadAfter, result.Target)
+	}
+
+	for _, move := range result.Moves {
+		if move.ToUserID == "u"+move.PullRequestID[2:] || move.FromUserID == move.ToUserID {
+			t.Errorf("invalid move %+v", move)
+		}
+	}
+
+	if got := openReviews(t, ctx, uc); !maps.Equal(got, result.Target) {
+		t.Errorf("stored workload = %v, want %v", got, result.Target)
+	}
+
+	// каждая передача — снятие и назначение
+	if rebalanced := rebalanceEntries(t, ctx, uc); rebalanced != 2*len(result.Moves) {
+		t.Errorf("history has %d rebalance entries, want %d", rebalanced, 2*len(result.Moves))
+	}
+
+	again, err := uc.Rebalance(ctx, "backend", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(again.Moves) != 0 {
+		t.Errorf("balanced team got moves %+v", again.Moves)
+	}
+}
+
+func TestRebalanceKeepsRequiredTeamLead(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newUnbalancedTeam(t, ctx, uc, true)
+
+	result, err := uc.Rebalance(ctx, "backend", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lead := result.WorkloadBefore["u2"]
+	if lead <= result.Target["u2"] {
+		t.Fatalf("team lead workload = %d, want above target %d", lead, result.Target["u2"])
+	}
+
+	if len(result.Moves) == 0 {
+		t.Fatal("no moves for unbalanced team")
+	}
+
+	for _, move := range result.Moves {
+		if move.FromUserID == "u2" {
+			t.Errorf("required team lead review was moved: %+v", move)
+		}
+	}
+
+	if got := openReviews(t, ctx, uc)["u2"]; got != lead {
+		t.Errorf("team lead has %d open reviews after rebalance, want %d", got, lead)
+	}
+}
+
+func TestRebalanceDryRunKeepsState(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newUnbalancedTeam(t, ctx, uc, false)
+
+	before := openReviews(t, ctx, uc)
+	plan, err := uc.Rebalance(ctx, "backend", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !plan.DryRun || len(plan.Moves) == 0 {
+		t.Fatalf("dry run = %+v, want planned moves", plan)
+	}
+
+	if got := openReviews(t, ctx, uc); !maps.Equal(got, before) {
+		t.Errorf("dry run changed workload: %v -> %v", before, got)
+	}
+
+	if n := rebalanceEntries(t, ctx, uc); n != 0 {
+		t.Errorf("dry run wrote %d history entries", n)
+	}
+
+	applied, err := uc.Rebalance(ctx, "backend", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(applied.Moves) != len(plan.Moves) {
+		t.Errorf("applied %d moves, dry run planned %d", len(applied.Moves), len(plan.Moves))
+	}
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	// SLABatchSize сколько просроченных назначений обрабатывается за один проход
+	SLABatchSize = 100
+
+	// reviewSLALock имя блокировки лидера задачи SLA
+	reviewSLALock = "review-sla"
+)
+
+// EnforceReviewSLA напоминает ревьюерам, не принимающим решения по открытому PR дольше SLA
+// команды автора, а если и после напоминания решения нет — заменяет их через ReassignReviewer
+// с причиной SLA_ESCALATION. Если замена невозможна (нет кандидатов, обязательный лид), она
+// больше не повторяется до следующего действия ревьюера. Назначение, которое не удалось
+// обработать, пропускается и не останавливает проход. Проход выполняет только экземпляр,
+// взявший блокировку лидера; остальные получают пустой итог
+func (uc *UseCase) EnforceReviewSLA(ctx context.Context, now time.Time) (entity.SLAResult, error) {
+	var result entity.SLAResult
+	leader, err := uc.repo.WithLeaderLock(ctx, reviewSLALock, func(ctx context.Context) error {
+		stale, err := uc.repo.GetStaleReviews(ctx, now, SLABatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range stale {
+			result.Checked++
+			if err := uc.enforceReviewSLA(ctx, s, now, &result); err != nil {
+				result.Errors++
+				slog.Error("failed to enforce review SLA for reviewer",
+					"error", err, "prID", s.PullRequestID, "reviewerID", s.ReviewerID)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		slog.Error("failed to enforce review SLA", "error", err)
+		return result, err
+	}
+
+	if !leader {
+		slog.Debug("review SLA is enforced by another instance")
+		return result, nil
+	}
+
+	if result.Reminded > 0 || result.Escalated > 0 || result.Failed > 0 || result.Errors > 0 {
+		slog.Info("review SLA enforced",
+			"reminded", result.Reminded, "escalated", result.Escalated, "failed", result.Failed, "errors", result.Errors)
+	}
+
+	return result, nil
+}
+
+// enforceReviewSLA заменяет или напоминает ревьюеру одного просроченного назначения.
+// Неудачная замена отмечается, чтобы не повторяться каждый проход
+func (uc *UseCase) enforceReviewSLA(ctx context.Context, s *entity.StaleReview, now time.Time, result *entity.SLAResult) error {
+	if s.EscalationDue(now) {
+		_, newReviewerID, err := uc.ReassignReviewer(ctx, s.PullRequestID, s.ReviewerID, entity.ReasonSLAEscalation)
+		if err == nil {
+			result.Escalated++
+			slog.Info("stale reviewer replaced",
+				"prID", s.PullRequestID, "oldReviewer", s.ReviewerID, "newReviewer", newReviewerID, "idleSince", s.IdleSince)
+			return nil
+		}
+
+		// доменная ошибка означает, что заменить нельзя; повторять каждый проход бессмысленно
+		if entity.CodeOf(err) == entity.InternalServer {
+			return err
+		}
+
+		slog.Warn("failed to replace stale reviewer", "error", err, "prID", s.PullRequestID, "reviewerID", s.ReviewerID)
+		if err := uc.repo.MarkReviewEscalated(ctx, s.PullRequestID, s.ReviewerID, now); err != nil {
+			return err
+		}
+
+		result.Failed++
+		return nil
+	}
+
+	if !s.ReminderDue(now) {
+		return nil
+	}
+
+	reminded := *s
+	reminded.RemindedAt = &now
+
+	err := uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		err := uc.emit(ctx, entity.EventReviewerReminded, s.TeamName, entity.ReminderEventData{
+			PullRequestID: s.PullRequestID,
+			ReviewerID:    s.ReviewerID,
+			IdleSince:     s.IdleSince,
+			EscalatesAt:   reminded.EscalatesAt(),
+		})
+		if err != nil {
+			return err
+		}
+
+		return uc.repo.MarkReviewReminded(ctx, s.PullRequestID, s.ReviewerID, now)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	result.Reminded++
+	return nil
+}
@@ -0,0 +1,194 @@
+package usecase_test
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newSLATeam(t *testing.T, ctx context.Context, uc *usecase.UseCase) *entity.PullRequest {
+	t.Helper()
+
+	_, err := uc.CreateTeam(ctx, &entity.Team{Name: "backend", Members: []*entity.TeamMember{
+		{UserID: "u1", Name: "Alice", IsActive: true},
+		{UserID: "u2", Name: "Bob", IsActive: true},
+		{UserID: "u3", Name: "Carol", IsActive: true},
+		{UserID: "u4", Name: "Dave", IsActive: true},
+	}})
+	if err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatalf("CreatePR: %v", err)
+	}
+
+	if len(pr.AssignReviewers) != 2 {
+		t.Fatalf("assigned %d reviewers, want 2", len(pr.AssignReviewers))
+	}
+
+	return pr
+}
+
+func TestEnforceReviewSLAEscalatesOnlyAfterReminder(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newSLATeam(t, ctx, uc)
+
+	sla := time.Duration(entity.DefaultReviewSLAMinutes) * time.Minute
+	escalation := time.Duration(entity.DefaultEscalationMinutes) * time.Minute
+
+	// оба порога уже пройдены, но напоминания не было: сначала только напоминание
+	remindedAt := time.Now().Add(escalation + time.Hour)
+	result, err := uc.EnforceReviewSLA(ctx, remindedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Reminded != 2 || result.Escalated != 0 || result.Failed != 0 {
+		t.Fatalf("first pass = %+v, want two reminders and no escalation", result)
+	}
+
+	result, err = uc.EnforceReviewSLA(ctx, remindedAt.Add(escalation-sla-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Checked != 0 {
+		t.Fatalf("pass before escalation is due = %+v, want nothing", result)
+	}
+
+	result, err = uc.EnforceReviewSLA(ctx, remindedAt.Add(escalation-sla))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Reminded != 0 || result.Escalated == 0 || result.Escalated+result.Failed != 2 || result.Errors != 0 {
+		t.Fatalf("pass after escalation is due = %+v, want both reviewers escalated or failed", result)
+	}
+}
+
+func TestEnforceReviewSLAWithoutRemindersEscalatesFromIdleStart(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newSLATeam(t, ctx, uc)
+
+	noReminders, escalation := 0, 60
+	_, err := uc.UpdateTeamSettings(ctx, &entity.TeamSettingsUpdate{
+		TeamSettings:      entity.TeamSettings{TeamName: "backend", MaxReviewers: 2},
+		ReviewSLAMinutes:  &noReminders,
+		EscalationMinutes: &escalation,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := uc.EnforceReviewSLA(ctx, time.Now().Add(61*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Reminded != 0 || result.Escalated == 0 {
+		t.Fatalf("result = %+v, want escalation without reminders", result)
+	}
+}
+
+func TestUpdateTeamSettingsKeepsMissingSLAFields(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newSLATeam(t, ctx, uc)
+
+	update := func(sla, escalation *int) *entity.TeamSettings {
+		t.Helper()
+
+		settings, err := uc.UpdateTeamSettings(ctx, &entity.TeamSettingsUpdate{
+			TeamSettings:      entity.TeamSettings{TeamName: "backend", MinReviewers: 1, MaxReviewers: 2},
+			ReviewSLAMinutes:  sla,
+			EscalationMinutes: escalation,
+		})
+		if err != nil {
+			t.Fatalf("UpdateTeamSettings: %v", err)
+		}
+
+		return settings
+	}
+
+	settings := update(nil, nil)
+	if settings.ReviewSLAMinutes != entity.DefaultReviewSLAMinutes || settings.EscalationMinutes != entity.DefaultEscalationMinutes {
+		t.Errorf("defaults were not kept: %+v", settings)
+	}
+
+	sla, escalation := 60, 120
+	update(&sla, &escalation)
+
+	settings = update(nil, nil)
+	if settings.ReviewSLAMinutes != sla || settings.EscalationMinutes != escalation {
+		t.Errorf("current SLA was not kept: %+v", settings)
+	}
+
+	disabled := 0
+	settings = update(nil, &disabled)
+	if settings.ReviewSLAMinutes != sla || settings.EscalationMinutes != 0 {
+		t.Errorf("escalation was not disabled: %+v", settings)
+	}
+
+	stored, err := uc.GetTeamSettings(ctx, "backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.ReviewSLAMinutes != sla || stored.EscalationMinutes != 0 || stored.MinReviewers != 1 {
+		t.Errorf("stored settings = %+v", stored)
+	}
+}
+
+// failingReminderRepo хранилище, в котором не удается отметить напоминание ревьюеру failReviewer
+type failingReminderRepo struct {
+	*memory.Repository
+	failReviewer string
+}
+
+func (r *failingReminderRepo) MarkReviewReminded(ctx context.Context, prID, userID string, at time.Time) error {
+	if userID == r.failReviewer {
+		return errors.New("mark reminded: connection reset")
+	}
+
+	return r.Repository.MarkReviewReminded(ctx, prID, userID, at)
+}
+
+func TestEnforceReviewSLAFailedReviewDoesNotRequestMore(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	repo := &failingReminderRepo{Repository: memory.New()}
+	uc := usecase.New(repo)
+	pr := newSLATeam(t, ctx, uc)
+	repo.failReviewer = pr.AssignReviewers[0]
+
+	now := time.Now().Add(time.Duration(entity.DefaultReviewSLAMinutes+1) * time.Minute)
+	result, err := uc.EnforceReviewSLA(ctx, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Checked != 2 || result.Reminded != 1 || result.Errors != 1 {
+		t.Fatalf("pass = %+v, want one reminder and one error", result)
+	}
+
+	// неотмеченное назначение вернется в выборку, поэтому полная пачка с ошибкой не повторяется сразу
+	if result.MoreDue(result.Checked) {
+		t.Errorf("pass with an error reports more due reviews: %+v", result)
+	}
+
+	again, err := uc.EnforceReviewSLA(ctx, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if again.Checked != 1 || again.Errors != 1 {
+		t.Errorf("second pass = %+v, want the failed review again", again)
+	}
+}
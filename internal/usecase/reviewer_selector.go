@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"avito_test_task/internal/entity"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ReviewerSelector выбирает ревьюеров среди активных кандидатов команды; lastAssigned —
+// последний назначенный в команде ревьюер по истории назначений, пустой, если назначений не было
+type ReviewerSelector interface {
+	Select(candidates []*entity.User, workload map[string]int, lastAssigned string, count int) []string
+}
+
+// defaultSelectors возвращает встроенные стратегии выбора ревьюеров
+func defaultSelectors() map[entity.ReviewerStrategy]ReviewerSelector {
+	return map[entity.ReviewerStrategy]ReviewerSelector{
+		entity.LeastBusy:      leastBusySelector{},
+		entity.RoundRobin:     roundRobinSelector{},
+		entity.RandomWeighted: newRandomWeightedSelector(),
+		entity.SeniorityAware: seniorityAwareSelector{},
+	}
+}
+
+// leastBusySelector выбирает кандидатов с наименьшим числом открытых ревью
+type leastBusySelector struct{}
+
+func (leastBusySelector) Select(candidates []*entity.User, workload map[string]int, _ string, count int) []string {
+	sorted := sortByWorkload(candidates, workload)
+	return userIDs(sorted[:minimum(count, len(sorted))])
+}
+
+// roundRobinSelector выбирает кандидатов по очереди, продолжая после последнего назначенного
+// в команде ревьюера. Позиция берется из истории назначений в той же транзакции, поэтому
+// пробные запуски и откаты ее не сдвигают, а все экземпляры сервиса видят одну очередь
+type roundRobinSelector struct{}
+
+func (roundRobinSelector) Select(candidates []*entity.User, _ map[string]int, lastAssigned string, count int) []string {
+	sorted := make([]*entity.User, len(candidates))
+	copy(sorted, candidates)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].UserID < sorted[j].UserID
+	})
+
+	start := sort.Search(len(sorted), func(i int) bool {
+		return sorted[i].UserID > lastAssigned
+	})
+
+	count = minimum(count, len(sorted))
+	reviewers := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		reviewers = append(reviewers, sorted[(start+i)%len(sorted)].UserID)
+	}
+
+	return reviewers
+}
+
+// randomWeightedSelector выбирает кандидатов случайно с весом, обратно пропорциональным загрузке
+type randomWeightedSelector struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+func newRandomWeightedSelector() *randomWeightedSelector {
+	now := uint64(time.Now().UnixNano())
+	return &randomWeightedSelector{
+		rnd: rand.New(rand.NewPCG(now, now>>1)),
+	}
+}
+
+func (s *randomWeightedSelector) Select(candidates []*entity.User, workload map[string]int, _ string, count int) []string {
+	pool := make([]*entity.User, len(candidates))
+	copy(pool, candidates)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count = minimum(count, len(pool))
+	reviewers := make([]string, 0, count)
+	for len(reviewers) < count {
+		total := 0.0
+		for _, c := range pool {
+			total += weight(workload[c.UserID])
+		}
+
+		point := s.rnd.Float64() * total
+		idx := len(pool) - 1
+		for i, c := range pool {
+			point -= weight(workload[c.UserID])
+			if point < 0 {
+				idx = i
+				break
+			}
+		}
+
+		reviewers = append(reviewers, pool[idx].UserID)
+		pool = append(pool[:idx], pool[idx+1:]...)
+	}
+
+	return reviewers
+}
+
+func weight(load int) float64 {
+	return 1 / float64(load+1)
+}
+
+// seniorityAwareSelector гарантирует одного ревьюера из более опытной половины команды,
+// остальных выбирает по наименьшей загрузке
+type seniorityAwareSelector struct{}
+
+func (seniorityAwareSelector) Select(candidates []*entity.User, workload map[string]int, _ string, count int) []string {
+	if count <= 0 || len(candidates) == 0 {
+		return []string{}
+	}
+
+	bySeniority := make([]*entity.User, len(candidates))
+	copy(bySeniority, candidates)
+	sort.SliceStable(bySeniority, func(i, j int) bool {
+		return joinedBefore(bySeniority[i], bySeniority[j])
+	})
+
+	seniors := bySeniority[:(len(bySeniority)+1)/2]
+	senior := sortByWorkload(seniors, workload)[0]
+
+	reviewers := []string{senior.UserID}
+	for _, c := range sortByWorkload(candidates, workload) {
+		if len(reviewers) >= count {
+			break
+		}
+
+		if c.UserID != senior.UserID {
+			reviewers = append(reviewers, c.UserID)
+		}
+	}
+
+	return reviewers
+}
+
+func joinedBefore(a, b *entity.User) bool {
+	if a.CreatedAt == nil || b.CreatedAt == nil {
+		return a.CreatedAt != nil
+	}
+
+	return a.CreatedAt.Before(*b.CreatedAt)
+}
+
+// sortByWorkload сортирует кандидатов по возрастанию загрузки, при равенстве по user_id
+func sortByWorkload(candidates []*entity.User, workload map[string]int) []*entity.User {
+	sorted := make([]*entity.User, len(candidates))
+	copy(sorted, candidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		li, lj := workload[sorted[i].UserID], workload[sorted[j].UserID]
+		if li != lj {
+			return li < lj
+		}
+
+		return sorted[i].UserID < sorted[j].UserID
+	})
+
+	return sorted
+}
+
+func userIDs(users []*entity.User) []string {
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.UserID
+	}
+
+	return ids
+}
+
+func minimum(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"avito_test_task/internal/entity"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+)
+
+// selectorCandidates участники u1..u4, u1 пришел в команду раньше всех
+func selectorCandidates() []*entity.User {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	users := make([]*entity.User, 0, 4)
+	for i := 1; i <= 4; i++ {
+		joined := base.AddDate(0, i, 0)
+		users = append(users, &entity.User{UserID: "u" + string(rune('0'+i)), IsActive: true, CreatedAt: &joined})
+	}
+
+	return users
+}
+
+func TestLeastBusySelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		workload map[string]int
+		count    int
+		want     []string
+	}{
+		{"least loaded first", map[string]int{"u1": 3, "u2": 0, "u3": 1, "u4": 2}, 2, []string{"u2", "u3"}},
+		{"ties by user id", map[string]int{"u1": 1, "u2": 1, "u3": 0, "u4": 1}, 3, []string{"u3", "u1", "u2"}},
+		{"count above candidates", nil, 9, []string{"u1", "u2", "u3", "u4"}},
+		{"zero count", nil, 0, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := leastBusySelector{}.Select(selectorCandidates(), tt.workload, "", tt.count)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Select = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundRobinSelector(t *testing.T) {
+	tests := []struct {
+		name         string
+		lastAssigned string
+		count        int
+		want         []string
+	}{
+		{"no assignments yet", "", 2, []string{"u1", "u2"}},
+		{"continues after last", "u2", 2, []string{"u3", "u4"}},
+		{"wraps around", "u3", 2, []string{"u4", "u1"}},
+		{"last is not a candidate", "u25", 1, []string{"u3"}},
+		{"last after every candidate", "u9", 2, []string{"u1", "u2"}},
+		{"count above candidates", "u1", 9, []string{"u2", "u3", "u4", "u1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// порядок кандидатов не влияет на очередь
+			candidates := selectorCandidates()
+			slices.Reverse(candidates)
+
+			got := roundRobinSelector{}.Select(candidates, nil, tt.lastAssigned, tt.count)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Select = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRandomWeightedSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		workload map[string]int
+		count    int
+		want     int
+	}{
+		{"picks count distinct candidates", map[string]int{"u1": 2}, 2, 2},
+		{"count above candidates", nil, 9, 4},
+		{"zero count", nil, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &randomWeightedSelector{rnd: rand.New(rand.NewPCG(1, 2))}
+			got := s.Select(selectorCandidates(), tt.workload, "", tt.count)
+			if len(got) != tt.want {
+				t.Fatalf("Select = %v, want %d reviewers", got, tt.want)
+			}
+
+			slices.Sort(got)
+			if len(slices.Compact(got)) != tt.want {
+				t.Errorf("Select = %v, want distinct reviewers", got)
+			}
+		})
+	}
+
+	// загрузка 9 дает вес в 10 раз меньше, чем у свободного ревьюера
+	s := &randomWeightedSelector{rnd: rand.New(rand.NewPCG(1, 2))}
+	picks := make(map[string]int)
+	for range 1000 {
+		for _, id := range s.Select(selectorCandidates(), map[string]int{"u1": 9, "u2": 9, "u3": 9}, "", 1) {
+			picks[id]++
+		}
+	}
+
+	if picks["u4"] < 2*(picks["u1"]+picks["u2"]+picks["u3"]) {
+		t.Errorf("picks = %v, want the idle reviewer picked most of the time", picks)
+	}
+}
+
+func TestSeniorityAwareSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		workload map[string]int
+		count    int
+		want     []string
+	}{
+		{"least busy senior first", map[string]int{"u1": 2, "u2": 1, "u3": 0, "u4": 0}, 2, []string{"u2", "u3"}},
+		{"senior when juniors are idle", map[string]int{"u1": 5, "u2": 5, "u3": 0, "u4": 0}, 1, []string{"u1"}},
+		{"rest by workload", map[string]int{"u1": 0, "u2": 3, "u3": 2, "u4": 1}, 3, []string{"u1", "u4", "u3"}},
+		{"zero count", nil, 0, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := seniorityAwareSelector{}.Select(selectorCandidates(), tt.workload, "", tt.count)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Select = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// без даты прихода пользователь считается младше остальных
+	candidates := selectorCandidates()
+	candidates[0].CreatedAt = nil
+	if got := (seniorityAwareSelector{}).Select(candidates, map[string]int{"u1": 0, "u2": 1, "u3": 1}, "", 1); !slices.Equal(got, []string{"u2"}) {
+		t.Errorf("Select without join date = %v, want [u2]", got)
+	}
+}
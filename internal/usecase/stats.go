@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"log/slog"
+)
+
+// GetReviewerStats получает статистику распределения ревью по пользователям
+func (uc *UseCase) GetReviewerStats(ctx context.Context, filter entity.StatsFilter) ([]*entity.ReviewerStats, error) {
+	if err := validateStatsFilter(filter); err != nil {
+		return nil, err
+	}
+
+	stats, err := uc.repo.GetReviewerStats(ctx, filter)
+	if err != nil {
+		slog.Error("failed to get reviewer stats", "error", err)
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// GetTeamStats получает статистику pull request'ов по командам
+func (uc *UseCase) GetTeamStats(ctx context.Context, filter entity.StatsFilter) ([]*entity.TeamStats, error) {
+	if err := validateStatsFilter(filter); err != nil {
+		return nil, err
+	}
+
+	stats, err := uc.repo.GetTeamStats(ctx, filter)
+	if err != nil {
+		slog.Error("failed to get team stats", "error", err)
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func validateStatsFilter(filter entity.StatsFilter) error {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return entity.NewError(entity.InvalidRequest, "from must be before to")
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package usecase_test
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"testing"
+)
+
+func TestReviewerStatsCountReassignedReviews(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 4)
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := pr.AssignReviewers[0]
+	if _, _, err := uc.ReassignReviewer(ctx, "pr1", old, entity.ReasonManualReassign); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := uc.GetReviewerStats(ctx, entity.StatsFilter{TeamName: "backend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range stats {
+		if s.UserID == old && (s.Assigned != 1 || s.Open != 0 || s.ReassignedOut != 1) {
+			t.Errorf("stats of replaced reviewer = %+v, want assigned 1, open 0", s)
+		}
+	}
+
+	teams, err := uc.GetTeamStats(ctx, entity.StatsFilter{TeamName: "backend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(teams) != 1 || teams[0].ReviewsAssigned != 3 {
+		t.Errorf("team stats = %+v, want 3 reviews assigned", teams)
+	}
+}
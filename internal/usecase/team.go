@@ -7,28 +7,64 @@ import (
 	"log/slog"
 )
 
-// CreateTeam валидирует команду и создает ее
+// CreateTeam создает команду и записывает действие в журнал аудита
 func (uc *UseCase) CreateTeam(ctx context.Context, team *entity.Team) (*entity.Team, error) {
+	var created *entity.Team
+	err := uc.audited(ctx, entity.AuditCreateTeam, entity.AuditTeam, team.Name, func(ctx context.Context) error {
+		var err error
+		created, err = uc.createTeam(ctx, team)
+		return err
+	})
+
+	return created, err
+}
+
+// createTeam валидирует команду и создает ее; участники не должны состоять в других командах
+func (uc *UseCase) createTeam(ctx context.Context, team *entity.Team) (*entity.Team, error) {
 	exists, err := uc.repo.TeamExists(ctx, team.Name)
 	if err != nil {
 		return nil, err
 	}
 
 	if exists {
-		return nil, fmt.Errorf("TEAM_EXISTS: team with name %s already exists", team.Name)
+		return nil, entity.NewError(entity.TeamExists, "team with name %s already exists", team.Name)
 	}
 
 	if err := validateTeam(team); err != nil {
 		return nil, err
 	}
 
-	if err := uc.repo.CreateTeam(ctx, team); err != nil {
+	if team.ReviewerStrategy == "" {
+		team.ReviewerStrategy = entity.LeastBusy
+	}
+
+	err = uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		for _, member := range team.Members {
+			if err := uc.checkNotInTeam(ctx, member.UserID); err != nil {
+				return err
+			}
+		}
+
+		if err := uc.repo.CreateTeam(ctx, team); err != nil {
+			return err
+		}
+
+		for _, member := range team.Members {
+			if err := uc.recordMembership(ctx, member.UserID, entity.MemberAdded, "", team.Name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
 		slog.Error("error to create team", "error", err, "team", team.Name)
 		return nil, err
 	}
 
 	slog.Info("team successfully created", "team", team.Name, "members", team.Members)
-	return team, nil
+	return uc.repo.GetTeam(ctx, team.Name)
 }
 
 // GetTeam получает команду по ее имени
@@ -36,34 +72,167 @@ func (uc *UseCase) GetTeam(ctx context.Context, teamName string) (*entity.Team,
 	team, err := uc.repo.GetTeam(ctx, teamName)
 	if err != nil {
 		slog.Error("failed to get team", "error", err, "teamName", teamName)
-		return nil, fmt.Errorf("NOT_FOUND: team with name %s not found", teamName)
+		return nil, err
 	}
 
 	slog.Info("team successfully retrieved", "teamName", teamName)
 	return team, nil
 }
 
+// SetReviewerStrategy меняет стратегию выбора ревьюеров команды с записью в журнал аудита
+func (uc *UseCase) SetReviewerStrategy(ctx context.Context, teamName string, strategy entity.ReviewerStrategy) (*entity.Team, error) {
+	var team *entity.Team
+	err := uc.audited(ctx, entity.AuditSetReviewerStrategy, entity.AuditTeam, teamName, func(ctx context.Context) error {
+		var err error
+		team, err = uc.setReviewerStrategy(ctx, teamName, strategy)
+		return err
+	})
+
+	return team, err
+}
+
+// setReviewerStrategy меняет стратегию выбора ревьюеров команды
+func (uc *UseCase) setReviewerStrategy(ctx context.Context, teamName string, strategy entity.ReviewerStrategy) (*entity.Team, error) {
+	if !strategy.IsValid() {
+		return nil, entity.NewError(entity.InvalidRequest, "unknown reviewer strategy %s", strategy)
+	}
+
+	if err := uc.repo.SetTeamReviewerStrategy(ctx, teamName, strategy); err != nil {
+		slog.Error("failed to set reviewer strategy", "error", err, "teamName", teamName)
+		return nil, err
+	}
+
+	slog.Info("reviewer strategy updated", "teamName", teamName, "strategy", strategy)
+	return uc.GetTeam(ctx, teamName)
+}
+
+// GetTeamSettings получает политику ревью команды
+func (uc *UseCase) GetTeamSettings(ctx context.Context, teamName string) (*entity.TeamSettings, error) {
+	settings, err := uc.repo.GetTeamSettings(ctx, teamName)
+	if err != nil {
+		slog.Error("failed to get team settings", "error", err, "teamName", teamName)
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// UpdateTeamSettings сохраняет политику ревью команды с записью в журнал аудита
+func (uc *UseCase) UpdateTeamSettings(ctx context.Context, update *entity.TeamSettingsUpdate) (*entity.TeamSettings, error) {
+	var updated *entity.TeamSettings
+	err := uc.audited(ctx, entity.AuditUpdateTeamSettings, entity.AuditTeam, update.TeamName, func(ctx context.Context) error {
+		var err error
+		updated, err = uc.updateTeamSettings(ctx, update)
+		return err
+	})
+
+	return updated, err
+}
+
+// updateTeamSettings валидирует и сохраняет политику ревью команды; не переданные поля SLA
+// берутся из текущей политики
+func (uc *UseCase) updateTeamSettings(ctx context.Context, update *entity.TeamSettingsUpdate) (*entity.TeamSettings, error) {
+	settings := update.TeamSettings
+	if settings.TeamName == "" {
+		return nil, entity.NewError(entity.InvalidRequest, "team name is required")
+	}
+
+	if update.ReviewSLAMinutes == nil || update.EscalationMinutes == nil {
+		current, err := uc.repo.GetTeamSettings(ctx, settings.TeamName)
+		if err != nil {
+			slog.Error("failed to get team settings", "error", err, "teamName", settings.TeamName)
+			return nil, err
+		}
+
+		settings.ReviewSLAMinutes, settings.EscalationMinutes = current.ReviewSLAMinutes, current.EscalationMinutes
+	}
+
+	if update.ReviewSLAMinutes != nil {
+		settings.ReviewSLAMinutes = *update.ReviewSLAMinutes
+	}
+
+	if update.EscalationMinutes != nil {
+		settings.EscalationMinutes = *update.EscalationMinutes
+	}
+
+	if err := validateTeamSettings(&settings); err != nil {
+		return nil, err
+	}
+
+	if settings.TeamLeadID != "" {
+		lead, err := uc.repo.GetUser(ctx, settings.TeamLeadID)
+		if err != nil {
+			slog.Error("team lead not found", "error", err, "teamLeadID", settings.TeamLeadID)
+			return nil, fmt.Errorf("get team lead: %w", err)
+		}
+
+		if lead.TeamName != settings.TeamName {
+			return nil, entity.NewError(entity.InvalidRequest, "team lead %s is not a member of team %s", lead.UserID, settings.TeamName)
+		}
+	}
+
+	if err := uc.repo.UpsertTeamSettings(ctx, &settings); err != nil {
+		slog.Error("failed to update team settings", "error", err, "teamName", settings.TeamName)
+		return nil, err
+	}
+
+	slog.Info("team settings updated", "teamName", settings.TeamName)
+	return &settings, nil
+}
+
+func validateTeamSettings(settings *entity.TeamSettings) error {
+	if settings.TeamName == "" {
+		return entity.NewError(entity.InvalidRequest, "team name is required")
+	}
+
+	if settings.MinReviewers < 0 || settings.MaxReviewers < settings.MinReviewers {
+		return entity.NewError(entity.InvalidRequest, "reviewers range must satisfy 0 <= min_reviewers <= max_reviewers")
+	}
+
+	if settings.RequiredApprovals < 0 || settings.RequiredApprovals > settings.MaxReviewers {
+		return entity.NewError(entity.InvalidRequest, "required_approvals must be between 0 and max_reviewers")
+	}
+
+	if settings.ReviewSLAMinutes < 0 || settings.EscalationMinutes < 0 {
+		return entity.NewError(entity.InvalidRequest, "review_sla_minutes and escalation_minutes must not be negative")
+	}
+
+	if settings.EscalationMinutes > 0 && settings.EscalationMinutes <= settings.ReviewSLAMinutes {
+		return entity.NewError(entity.InvalidRequest, "escalation_minutes must be greater than review_sla_minutes")
+	}
+
+	if settings.RequireTeamLead && settings.TeamLeadID == "" {
+		return entity.NewError(entity.InvalidRequest, "team_lead_id is required when require_team_lead is set")
+	}
+
+	return nil
+}
+
 func validateTeam(team *entity.Team) error {
 	if team.Name == "" {
-		return fmt.Errorf("team name is required")
+		return entity.NewError(entity.InvalidRequest, "team name is required")
 	}
 
 	if len(team.Members) == 0 {
-		return fmt.Errorf("team must have at least one member")
+		return entity.NewError(entity.InvalidRequest, "team must have at least one member")
+	}
+
+	if team.ReviewerStrategy != "" && !team.ReviewerStrategy.IsValid() {
+		return entity.NewError(entity.InvalidRequest, "unknown reviewer strategy %s", team.ReviewerStrategy)
 	}
 
 	seen := make(map[string]bool)
 	for _, member := range team.Members {
 		if member.UserID == "" {
-			return fmt.Errorf("member user_id is required")
+			return entity.NewError(entity.InvalidRequest, "member user_id is required")
 		}
 
 		if member.Name == "" {
-			return fmt.Errorf("member username must be required")
+			return entity.NewError(entity.InvalidRequest, "member username must be required")
 		}
 
 		if seen[member.UserID] {
-			return fmt.Errorf("team user %s is already member of team %s", member.UserID, team.Name)
+			return entity.NewError(entity.InvalidRequest, "team user %s is already member of team %s", member.UserID, team.Name)
 		}
 
 		seen[member.UserID] = true
@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// AddTeamMembers добавляет участников в команду с записью в журнал аудита
+func (uc *UseCase) AddTeamMembers(ctx context.Context, teamName string, members []*entity.TeamMember) (*entity.Team, error) {
+	var team *entity.Team
+	err := uc.audited(ctx, entity.AuditAddTeamMembers, entity.AuditTeam, teamName, func(ctx context.Context) error {
+		var err error
+		team, err = uc.addTeamMembers(ctx, teamName, members)
+		return err
+	})
+
+	return team, err
+}
+
+// addTeamMembers добавляет участников в существующую команду. Пользователь из другой команды
+// не добавляется (MEMBER_EXISTS): для перевода есть MoveTeamMember
+func (uc *UseCase) addTeamMembers(ctx context.Context, teamName string, members []*entity.TeamMember) (*entity.Team, error) {
+	if err := validateTeam(&entity.Team{Name: teamName, Members: members}); err != nil {
+		return nil, err
+	}
+
+	err := uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		for _, member := range members {
+			if err := uc.checkNotInTeam(ctx, member.UserID); err != nil {
+				return err
+			}
+
+			if err := uc.repo.AddTeamMember(ctx, teamName, member); err != nil {
+				return err
+			}
+
+			if err := uc.recordMembership(ctx, member.UserID, entity.MemberAdded, "", teamName); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		slog.Error("failed to add team members", "error", err, "team", teamName)
+		return nil, err
+	}
+
+	slog.Info("team members added", "team", teamName, "count", len(members))
+	return uc.GetTeam(ctx, teamName)
+}
+
+// RemoveTeamMember убирает участника из команды с записью в журнал аудита
+func (uc *UseCase) RemoveTeamMember(ctx context.Context, teamName, userID string) (*entity.MembershipResult, error) {
+	var result *entity.MembershipResult
+	err := uc.audited(ctx, entity.AuditRemoveTeamMember, entity.AuditUser, userID, func(ctx context.Context) error {
+		var err error
+		result, err = uc.removeTeamMember(ctx, teamName, userID)
+		return err
+	})
+
+	return result, err
+}
+
+// removeTeamMember убирает пользователя из команды. Его открытые ревью переназначаются
+// на оставшихся участников; пользователь сохраняется, так как на него ссылаются PR
+func (uc *UseCase) removeTeamMember(ctx context.Context, teamName, userID string) (*entity.MembershipResult, error) {
+	result := &entity.MembershipResult{}
+	err := uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		user, err := uc.repo.GetUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if user.TeamName != teamName {
+			return entity.NewError(entity.NotFound, "user %s is not a member of team %s", userID, teamName)
+		}
+
+		if result.Reassignments, err = uc.leaveTeam(ctx, user); err != nil {
+			return err
+		}
+
+		if result.User, err = uc.repo.SetUserTeam(ctx, userID, ""); err != nil {
+			return err
+		}
+
+		return uc.recordMembership(ctx, userID, entity.MemberRemoved, teamName, "")
+	})
+
+	if err != nil {
+		slog.Error("failed to remove team member", "error", err, "team", teamName, "userID", userID)
+		return nil, err
+	}
+
+	slog.Info("team member removed", "team", teamName, "userID", userID, "reassignments", len(result.Reassignments))
+	return result, nil
+}
+
+// MoveTeamMember переводит участника в другую команду с записью в журнал аудита
+func (uc *UseCase) MoveTeamMember(ctx context.Context, userID, teamName string) (*entity.MembershipResult, error) {
+	var result *entity.MembershipResult
+	err := uc.audited(ctx, entity.AuditMoveTeamMember, entity.AuditUser, userID, func(ctx context.Context) error {
+		var err error
+		result, err = uc.moveTeamMember(ctx, userID, teamName)
+		return err
+	})
+
+	return result, err
+}
+
+// moveTeamMember переводит пользователя в команду teamName. Открытые ревью в прежней
+// команде переназначаются на ее участников, перевод попадает в журнал состава
+func (uc *UseCase) moveTeamMember(ctx context.Context, userID, teamName string) (*entity.MembershipResult, error) {
+	if userID == "" || teamName == "" {
+		return nil, entity.NewError(entity.InvalidRequest, "user_id and team_name are required")
+	}
+
+	result := &entity.MembershipResult{
+		Reassignments: make([]*entity.ReviewReassignment, 0),
+	}
+
+	err := uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		user, err := uc.repo.GetUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if user.TeamName == teamName {
+			return entity.NewError(entity.MemberExists, "user %s is already a member of team %s", userID, teamName)
+		}
+
+		exists, err := uc.repo.TeamExists(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return entity.ErrTeamNotFound
+		}
+
+		action := entity.MemberAdded
+		if user.TeamName != "" {
+			action = entity.MemberMoved
+			if result.Reassignments, err = uc.leaveTeam(ctx, user); err != nil {
+				return err
+			}
+		}
+
+		if result.User, err = uc.repo.SetUserTeam(ctx, userID, teamName); err != nil {
+			return err
+		}
+
+		return uc.recordMembership(ctx, userID, action, user.TeamName, teamName)
+	})
+
+	if err != nil {
+		slog.Error("failed to move team member", "error", err, "team", teamName, "userID", userID)
+		return nil, err
+	}
+
+	slog.Info("team member moved", "team", teamName, "userID", userID, "reassignments", len(result.Reassignments))
+	return result, nil
+}
+
+// RenameTeam переименовывает команду с записью в журнал аудита
+func (uc *UseCase) RenameTeam(ctx context.Context, teamName, newTeamName string) (*entity.Team, error) {
+	var team *entity.Team
+	err := uc.audited(ctx, entity.AuditRenameTeam, entity.AuditTeam, teamName, func(ctx context.Context) error {
+		var err error
+		team, err = uc.renameTeam(ctx, teamName, newTeamName)
+		return err
+	})
+
+	return team, err
+}
+
+// renameTeam переименовывает команду
+func (uc *UseCase) renameTeam(ctx context.Context, teamName, newTeamName string) (*entity.Team, error) {
+	if teamName == "" || newTeamName == "" {
+		return nil, entity.NewError(entity.InvalidRequest, "team_name and new_team_name are required")
+	}
+
+	if teamName == newTeamName {
+		return nil, entity.NewError(entity.InvalidRequest, "new_team_name must differ from team_name")
+	}
+
+	err := uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		return uc.repo.RenameTeam(ctx, teamName, newTeamName)
+	})
+
+	if err != nil {
+		slog.Error("failed to rename team", "error", err, "team", teamName, "newTeam", newTeamName)
+		return nil, err
+	}
+
+	slog.Info("team renamed", "team", teamName, "newTeam", newTeamName)
+	return uc.GetTeam(ctx, newTeamName)
+}
+
+// DeleteTeam удаляет команду с записью в журнал аудита
+func (uc *UseCase) DeleteTeam(ctx context.Context, teamName string) error {
+	return uc.audited(ctx, entity.AuditDeleteTeam, entity.AuditTeam, teamName, func(ctx context.Context) error {
+		return uc.deleteTeam(ctx, teamName)
+	})
+}
+
+// deleteTeam удаляет команду без участников (иначе TEAM_NOT_EMPTY)
+func (uc *UseCase) deleteTeam(ctx context.Context, teamName string) error {
+	if teamName == "" {
+		return entity.NewError(entity.InvalidRequest, "team name is required")
+	}
+
+	if err := uc.repo.DeleteTeam(ctx, teamName); err != nil {
+		slog.Error("failed to delete team", "error", err, "team", teamName)
+		return err
+	}
+
+	slog.Info("team deleted", "team", teamName)
+	return nil
+}
+
+// GetMembershipChanges получает журнал изменений состава команды
+func (uc *UseCase) GetMembershipChanges(ctx context.Context, teamName string) ([]*entity.MembershipChange, error) {
+	changes, err := uc.repo.GetMembershipChanges(ctx, teamName)
+	if err != nil {
+		slog.Error("failed to get membership changes", "error", err, "team", teamName)
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// leaveTeam готовит уход пользователя из команды: тимлида убрать нельзя, пока он указан
+// в настройках, открытые ревью переназначаются на участников команды
+func (uc *UseCase) leaveTeam(ctx context.Context, user *entity.User) ([]*entity.ReviewReassignment, error) {
+	settings, err := uc.repo.GetTeamSettings(ctx, user.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.TeamLeadID == user.UserID {
+		return nil, entity.NewError(entity.PolicyViolated,
+			"user %s is the team lead of %s, change team settings first", user.UserID, user.TeamName)
+	}
+
+	return uc.reassignOpenReviews(ctx, user, false, entity.ReasonTeamChange)
+}
+
+// checkNotInTeam проверяет, что пользователь новый или не состоит ни в одной команде
+func (uc *UseCase) checkNotInTeam(ctx context.Context, userID string) error {
+	user, err := uc.repo.GetUser(ctx, userID)
+	if errors.Is(err, entity.NotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if user.TeamName != "" {
+		return entity.NewError(entity.MemberExists,
+			"user %s already belongs to team %s, use /team/moveMember", userID, user.TeamName)
+	}
+
+	return nil
+}
+
+func (uc *UseCase) recordMembership(ctx context.Context, userID string, action entity.MembershipAction, fromTeam, toTeam string) error {
+	return uc.repo.AddMembershipChange(ctx, &entity.MembershipChange{
+		UserID:   userID,
+		Action:   action,
+		FromTeam: fromTeam,
+		ToTeam:   toTeam,
+	})
+}
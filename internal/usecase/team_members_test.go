@@ -0,0 +1,275 @@
+package usecase_test
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"slices"
+	"testing"
+)
+
+// membershipActions действия журнала состава команды по порядку
+func membershipActions(t *testing.T, ctx context.Context, uc *usecase.UseCase, teamName string) []entity.MembershipAction {
+	t.Helper()
+
+	changes, err := uc.GetMembershipChanges(ctx, teamName)
+	if err != nil {
+		t.Fatalf("GetMembershipChanges: %v", err)
+	}
+
+	actions := make([]entity.MembershipAction, 0, len(changes))
+	for _, c := range changes {
+		actions = append(actions, c.Action)
+	}
+
+	return actions
+}
+
+func TestAddTeamMembers(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 2)
+
+	team, err := uc.AddTeamMembers(ctx, "backend", []*entity.TeamMember{{UserID: "u3", Name: "user u3", IsActive: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(team.Members) != 3 {
+		t.Errorf("team has %d members, want 3", len(team.Members))
+	}
+
+	if _, err := uc.CreateTeam(ctx, &entity.Team{Name: "frontend", Members: []*entity.TeamMember{{UserID: "f1", Name: "Fiona", IsActive: true}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = uc.AddTeamMembers(ctx, "frontend", []*entity.TeamMember{{UserID: "u1", Name: "user u1", IsActive: true}})
+	if entity.CodeOf(err) != entity.MemberExists {
+		t.Errorf("add member of another team error = %v, want %s", err, entity.MemberExists)
+	}
+
+	want := []entity.MembershipAction{entity.MemberAdded, entity.MemberAdded, entity.MemberAdded}
+	if got := membershipActions(t, ctx, uc, "backend"); !slices.Equal(got, want) {
+		t.Errorf("membership changes = %v, want %v", got, want)
+	}
+}
+
+func TestRemoveTeamMemberReassignsReviews(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 4)
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reviewer := pr.AssignReviewers[0]
+	result, err := uc.RemoveTeamMember(ctx, "backend", reviewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.User.TeamName != "" {
+		t.Errorf("removed user team = %q, want none", result.User.TeamName)
+	}
+
+	if len(result.Reassignments) != 1 || result.Reassignments[0].NewReviewerID == "" {
+		t.Fatalf("reassignments = %+v, want one replacement", result.Reassignments)
+	}
+
+	reviews, err := uc.GetUserReviews(ctx, reviewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reviews) != 0 {
+		t.Errorf("removed member still reviews %+v", reviews)
+	}
+
+	if _, err := uc.RemoveTeamMember(ctx, "backend", reviewer); entity.CodeOf(err) != entity.NotFound {
+		t.Errorf("second removal error = %v, want %s", err, entity.NotFound)
+	}
+}
+
+func TestTeamLeadCannotLeaveTeam(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 3)
+
+	_, err := uc.UpdateTeamSettings(ctx, &entity.TeamSettingsUpdate{TeamSettings: entity.TeamSettings{
+		TeamName:     "backend",
+		MaxReviewers: 2,
+		TeamLeadID:   "u2",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.RemoveTeamMember(ctx, "backend", "u2"); entity.CodeOf(err) != entity.PolicyViolated {
+		t.Errorf("remove team lead error = %v, want %s", err, entity.PolicyViolated)
+	}
+
+	if _, err := uc.CreateTeam(ctx, &entity.Team{Name: "frontend", Members: []*entity.TeamMember{{UserID: "f1", Name: "Fiona", IsActive: true}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.MoveTeamMember(ctx, "u2", "frontend"); entity.CodeOf(err) != entity.PolicyViolated {
+		t.Errorf("move team lead error = %v, want %s", err, entity.PolicyViolated)
+	}
+}
+
+func TestMoveTeamMember(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 3)
+
+	if _, err := uc.CreateTeam(ctx, &entity.Team{Name: "frontend", Members: []*entity.TeamMember{{UserID: "f1", Name: "Fiona", IsActive: true}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := uc.MoveTeamMember(ctx, "u3", "frontend")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.User.TeamName != "frontend" {
+		t.Errorf("moved user team = %q, want frontend", result.User.TeamName)
+	}
+
+	if _, err := uc.MoveTeamMember(ctx, "u3", "frontend"); entity.CodeOf(err) != entity.MemberExists {
+		t.Errorf("move to the same team error = %v, want %s", err, entity.MemberExists)
+	}
+
+	if _, err := uc.MoveTeamMember(ctx, "u1", "missing"); entity.CodeOf(err) != entity.NotFound {
+		t.Errorf("move to missing team error = %v, want %s", err, entity.NotFound)
+	}
+
+	changes, err := uc.GetMembershipChanges(ctx, "frontend")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moved := slices.ContainsFunc(changes, func(c *entity.MembershipChange) bool {
+		return c.UserID == "u3" && c.Action == entity.MemberMoved && c.FromTeam == "backend" && c.ToTeam == "frontend"
+	})
+	if !moved {
+		t.Errorf("membership changes = %+v, want u3 moved from backend", changes)
+	}
+}
+
+func TestRenameAndDeleteTeam(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 1)
+
+	team, err := uc.RenameTeam(ctx, "backend", "platform")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if team.Name != "platform" || len(team.Members) != 1 {
+		t.Errorf("renamed team = %+v", team)
+	}
+
+	if _, err := uc.RenameTeam(ctx, "platform", "platform"); entity.CodeOf(err) != entity.InvalidRequest {
+		t.Errorf("rename to the same name error = %v, want %s", err, entity.InvalidRequest)
+	}
+
+	if err := uc.DeleteTeam(ctx, "platform"); entity.CodeOf(err) != entity.TeamNotEmpty {
+		t.Errorf("delete of non-empty team error = %v, want %s", err, entity.TeamNotEmpty)
+	}
+
+	if _, err := uc.RemoveTeamMember(ctx, "platform", "u1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := uc.DeleteTeam(ctx, "platform"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.GetTeam(ctx, "platform"); entity.CodeOf(err) != entity.NotFound {
+		t.Errorf("deleted team error = %v, want %s", err, entity.NotFound)
+	}
+}
+
+func TestUserWithoutTeam(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 3)
+
+	if _, err := uc.RemoveTeamMember(ctx, "backend", "u3"); err != nil {
+		t.Fatal(err)
+	}
+
+	// пользователь без команды остается в сервисе, но не попадает в списки команды и ревьюеры
+	users, _, err := uc.ListUsers(ctx, entity.UserFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if i := slices.IndexFunc(users, func(u *entity.User) bool { return u.UserID == "u3" }); i < 0 || users[i].TeamName != "" {
+		t.Fatalf("users = %+v, want u3 without a team", users)
+	}
+
+	if members, _, err := uc.ListUsers(ctx, entity.UserFilter{TeamName: "backend"}); err != nil || len(members) != 2 {
+		t.Errorf("backend users = %+v, %v, want 2", members, err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		pr, err := uc.CreatePR(ctx, "pr"+string(rune('0'+i)), "feature", "u1", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if slices.Contains(pr.AssignReviewers, "u3") {
+			t.Fatalf("user without a team was assigned: %v", pr.AssignReviewers)
+		}
+	}
+
+	if _, err := uc.CreatePR(ctx, "pr-teamless", "feature", "u3", false); entity.CodeOf(err) != entity.NotFound {
+		t.Errorf("PR of author without a team error = %v, want %s", err, entity.NotFound)
+	}
+
+	// активность меняется и без команды и сохраняется при возвращении в команду
+	if _, _, err := uc.SetIsActive(ctx, "u3", false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.AddTeamMembers(ctx, "backend", []*entity.TeamMember{{UserID: "u3", Name: "user u3", IsActive: true}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if userActive(t, ctx, uc, "u3") {
+		t.Error("returning user was activated by addMembers")
+	}
+
+	if _, err := uc.RemoveTeamMember(ctx, "backend", "u3"); err != nil {
+		t.Fatal(err)
+	}
+
+	// перевод пользователя без команды — вступление в команду
+	if _, err := uc.MoveTeamMember(ctx, "u3", "backend"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []entity.MembershipAction{entity.MemberAdded, entity.MemberRemoved, entity.MemberAdded, entity.MemberRemoved}
+	if got := membershipActions(t, ctx, uc, "backend"); !slices.Equal(got[:4], want) {
+		t.Errorf("membership changes = %v, want %v first", got, want)
+	}
+
+	// командная привязка не распространяется на пользователя без команды
+	if _, err := uc.RemoveTeamMember(ctx, "backend", "u3"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.BindRole(ctx, &entity.RoleBinding{UserID: "u2", Role: entity.RoleTeamLead, TeamName: "backend"}); err != nil {
+		t.Fatal(err)
+	}
+
+	lead := entity.Actor{ID: "u2", Roles: []string{entity.RoleUser}}
+	if err := uc.Authorize(ctx, lead, entity.PermUserDeactivate, entity.AuthTarget{UserIDs: []string{"u3"}}); entity.CodeOf(err) != entity.Forbidden {
+		t.Errorf("team lead over user without a team error = %v, want %s", err, entity.Forbidden)
+	}
+}
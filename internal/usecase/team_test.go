@@ -0,0 +1,144 @@
+package usecase_test
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"testing"
+)
+
+func TestCreateTeamValidation(t *testing.T) {
+	member := func(id, name string) *entity.TeamMember {
+		return &entity.TeamMember{UserID: id, Name: name, IsActive: true}
+	}
+
+	tests := []struct {
+		name string
+		team *entity.Team
+	}{
+		{"empty name", &entity.Team{Members: []*entity.TeamMember{member("u1", "Alice")}}},
+		{"no members", &entity.Team{Name: "backend"}},
+		{"unknown strategy", &entity.Team{Name: "backend", ReviewerStrategy: "FASTEST", Members: []*entity.TeamMember{member("u1", "Alice")}}},
+		{"member without id", &entity.Team{Name: "backend", Members: []*entity.TeamMember{member("", "Alice")}}},
+		{"member without name", &entity.Team{Name: "backend", Members: []*entity.TeamMember{member("u1", "")}}},
+		{"duplicate member", &entity.Team{Name: "backend", Members: []*entity.TeamMember{member("u1", "Alice"), member("u1", "Alice")}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := entity.WithSystemActor(context.Background())
+			uc := usecase.New(memory.New())
+
+			if _, err := uc.CreateTeam(ctx, tt.team); entity.CodeOf(err) != entity.InvalidRequest {
+				t.Errorf("CreateTeam error = %v, want %s", err, entity.InvalidRequest)
+			}
+
+			if _, err := uc.GetAbsences(ctx, "u1"); entity.CodeOf(err) != entity.NotFound {
+				t.Errorf("invalid team created user u1: %v", err)
+			}
+		})
+	}
+}
+
+func TestSetReviewerStrategy(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 2)
+
+	team, err := uc.GetTeam(ctx, "backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if team.ReviewerStrategy != entity.LeastBusy {
+		t.Errorf("default strategy = %s, want %s", team.ReviewerStrategy, entity.LeastBusy)
+	}
+
+	team, err = uc.SetReviewerStrategy(ctx, "backend", entity.SeniorityAware)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if team.ReviewerStrategy != entity.SeniorityAware {
+		t.Errorf("strategy = %s, want %s", team.ReviewerStrategy, entity.SeniorityAware)
+	}
+
+	if _, err := uc.SetReviewerStrategy(ctx, "backend", "FASTEST"); entity.CodeOf(err) != entity.InvalidRequest {
+		t.Errorf("unknown strategy error = %v, want %s", err, entity.InvalidRequest)
+	}
+
+	if _, err := uc.SetReviewerStrategy(ctx, "missing", entity.RoundRobin); entity.CodeOf(err) != entity.NotFound {
+		t.Errorf("missing team error = %v, want %s", err, entity.NotFound)
+	}
+}
+
+func TestUpdateTeamSettings(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 4)
+
+	defaults, err := uc.GetTeamSettings(ctx, "backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if defaults.MaxReviewers != entity.DefaultMaxReviewers || defaults.ReviewSLAMinutes != entity.DefaultReviewSLAMinutes {
+		t.Errorf("default settings = %+v", defaults)
+	}
+
+	sla := 60
+	updated, err := uc.UpdateTeamSettings(ctx, &entity.TeamSettingsUpdate{
+		TeamSettings: entity.TeamSettings{
+			TeamName:          "backend",
+			MinReviewers:      1,
+			MaxReviewers:      3,
+			RequiredApprovals: 2,
+			RequireTeamLead:   true,
+			TeamLeadID:        "u2",
+		},
+		ReviewSLAMinutes: &sla,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// escalation_minutes не передан и сохраняет текущее значение
+	if updated.ReviewSLAMinutes != 60 || updated.EscalationMinutes != entity.DefaultEscalationMinutes {
+		t.Errorf("SLA = %d/%d, want 60/%d", updated.ReviewSLAMinutes, updated.EscalationMinutes, entity.DefaultEscalationMinutes)
+	}
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pr.AssignReviewers) != 3 || pr.AssignReviewers[0] != "u2" {
+		t.Errorf("reviewers = %v, want team lead u2 and two more", pr.AssignReviewers)
+	}
+
+	invalid := []struct {
+		name     string
+		settings entity.TeamSettings
+	}{
+		{"min above max", entity.TeamSettings{TeamName: "backend", MinReviewers: 3, MaxReviewers: 2}},
+		{"approvals above max", entity.TeamSettings{TeamName: "backend", MaxReviewers: 2, RequiredApprovals: 3}},
+		{"lead required without lead", entity.TeamSettings{TeamName: "backend", MaxReviewers: 2, RequireTeamLead: true}},
+		{"lead from another team", entity.TeamSettings{TeamName: "frontend", MaxReviewers: 2, TeamLeadID: "u2"}},
+		{"escalation before SLA", entity.TeamSettings{TeamName: "backend", MaxReviewers: 2, ReviewSLAMinutes: 60, EscalationMinutes: 30}},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			update := &entity.TeamSettingsUpdate{
+				TeamSettings:      tt.settings,
+				ReviewSLAMinutes:  &tt.settings.ReviewSLAMinutes,
+				EscalationMinutes: &tt.settings.EscalationMinutes,
+			}
+
+			if _, err := uc.UpdateTeamSettings(ctx, update); entity.CodeOf(err) != entity.InvalidRequest {
+				t.Errorf("UpdateTeamSettings error = %v, want %s", err, entity.InvalidRequest)
+			}
+		})
+	}
+}
@@ -2,38 +2,138 @@ package usecase
 
 import (
 	"avito_test_task/internal/entity"
-	"avito_test_task/internal/repository/pg"
 	"context"
+	"time"
 )
 
 // UseCase содержит всю бизнес-логику
 type UseCase struct {
-	repo *pg.Repository
+	repo      Repository
+	selectors map[entity.ReviewerStrategy]ReviewerSelector
 }
 
 // New создаёт новый use case
-func New(repo *pg.Repository) *UseCase {
+func New(repo Repository) *UseCase {
 	return &UseCase{
-		repo: repo,
+		repo:      repo,
+		selectors: defaultSelectors(),
 	}
 }
 
+// Repository хранилище, с которым работает бизнес-логика; реализации: pg и memory
 type Repository interface {
+	// WithinTx выполняет fn в одной транзакции, методы репозитория получают ее через ctx
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+
 	// Teams
 	CreateTeam(ctx context.Context, team *entity.Team) error
 	GetTeam(ctx context.Context, teamName string) (*entity.Team, error)
 	TeamExists(ctx context.Context, teamName string) (bool, error)
+	GetTeamReviewerStrategy(ctx context.Context, teamName string) (entity.ReviewerStrategy, error)
+	SetTeamReviewerStrategy(ctx context.Context, teamName string, strategy entity.ReviewerStrategy) error
+	GetTeamSettings(ctx context.Context, teamName string) (*entity.TeamSettings, error)
+	UpsertTeamSettings(ctx context.Context, settings *entity.TeamSettings) error
+	AddTeamMember(ctx context.Context, teamName string, member *entity.TeamMember) error
+	SetUserTeam(ctx context.Context, userID, teamName string) (*entity.User, error)
+	RenameTeam(ctx context.Context, teamName, newTeamName string) error
+	DeleteTeam(ctx context.Context, teamName string) error
+	AddMembershipChange(ctx context.Context, change *entity.MembershipChange) error
+	GetMembershipChanges(ctx context.Context, teamName string) ([]*entity.MembershipChange, error)
 
 	// Users
 	GetUser(ctx context.Context, userID string) (*entity.User, error)
 	SetIsActive(ctx context.Context, userID string, isActive bool) (*entity.User, error)
 	GetActiveCandidates(ctx context.Context, teamName string, excludeUserIDs []string) ([]*entity.User, error)
+	GetActiveUsers(ctx context.Context, excludeUserIDs []string) ([]*entity.User, error)
+
+	// Absences
+	CreateAbsence(ctx context.Context, absence *entity.Absence) error
+	GetAbsence(ctx context.Context, id int64) (*entity.Absence, error)
+	GetAbsences(ctx context.Context, userID string) ([]*entity.Absence, error)
+	GetDueAbsences(ctx context.Context, at time.Time) ([]*entity.Absence, error)
+	GetCurrentAbsence(ctx context.Context, userID string, at time.Time) (*entity.Absence, error)
+	UpdateAbsence(ctx context.Context, absence *entity.Absence) error
+	DeleteAbsence(ctx context.Context, id int64) error
 
 	// PRs
 	CreatePR(ctx context.Context, pr *entity.PullRequest) error
 	GetPR(ctx context.Context, prID string) (*entity.PullRequest, error)
 	MergePR(ctx context.Context, prID string) (*entity.PullRequest, error)
+	TransitionPR(ctx context.Context, prID, from, to string, reviewers []string) (*entity.PullRequest, error)
 	ReassignReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) (*entity.PullRequest, error)
 	GetReview(ctx context.Context, userID string) ([]*entity.PullRequestShort, error)
+	AddReview(ctx context.Context, prID string, review *entity.Review) error
 	PRExists(ctx context.Context, prID string) (bool, error)
+	GetReviewersWorkload(ctx context.Context, userIDs []string) (map[string]int, error)
+	GetPRReviewers(ctx context.Context, prID string) ([]*entity.AssignedReviewer, error)
+	GetPRReassignments(ctx context.Context, prID string) ([]*entity.Reassignment, error)
+	AddAssignmentHistory(ctx context.Context, entries []*entity.AssignmentHistory) error
+	GetAssignmentHistory(ctx context.Context, filter entity.AssignmentHistoryFilter) ([]*entity.AssignmentHistory, error)
+	GetLastAssignedReviewer(ctx context.Context, teamName string) (string, error)
+
+	// Review SLA
+	GetStaleReviews(ctx context.Context, now time.Time, limit int) ([]*entity.StaleReview, error)
+	MarkReviewReminded(ctx context.Context, prID, userID string, at time.Time) error
+	MarkReviewEscalated(ctx context.Context, prID, userID string, at time.Time) error
+	// WithLeaderLock выполняет fn, только если этот экземпляр взял блокировку name
+	WithLeaderLock(ctx context.Context, name string, fn func(ctx context.Context) error) (bool, error)
+
+	// Lists
+	ListTeams(ctx context.Context, filter entity.TeamFilter) ([]*entity.TeamSummary, error)
+	ListUsers(ctx context.Context, filter entity.UserFilter) ([]*entity.User, error)
+	ListPRs(ctx context.Context, filter entity.PRFilter) ([]*entity.PullRequest, error)
+
+	// Audit
+	AddAuditEntry(ctx context.Context, entry *entity.AuditEntry) error
+	ListAuditEntries(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error)
+
+	// RBAC
+	AddRoleBinding(ctx context.Context, binding *entity.RoleBinding) error
+	DeleteRoleBinding(ctx context.Context, id int64) (*entity.RoleBinding, error)
+	GetRoleBindings(ctx context.Context, filter entity.RoleBindingFilter) ([]*entity.RoleBinding, error)
+
+	// API keys
+	CreateAPIKey(ctx context.Context, key *entity.APIKey) error
+	GetAPIKeyByHash(ctx context.Context, hash string) (*entity.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]*entity.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int64, at time.Time) (*entity.APIKey, error)
+	TouchAPIKey(ctx context.Context, id int64, at time.Time) error
+
+	// Webhooks
+	CreateWebhook(ctx context.Context, hook *entity.Webhook) error
+	GetWebhooks(ctx context.Context, teamName string) ([]*entity.Webhook, error)
+	GetSubscribedWebhooks(ctx context.Context, teamName string, eventType entity.EventType) ([]*entity.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	AddWebhookDeliveries(ctx context.Context, deliveries []*entity.WebhookDelivery) error
+	ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+	AddDeadLetter(ctx context.Context, deadLetter *entity.DeadLetter) error
+	ListWebhookDeliveries(ctx context.Context, filter entity.DeliveryFilter) ([]*entity.WebhookDelivery, error)
+	ListDeadLetters(ctx context.Context, filter entity.DeliveryFilter) ([]*entity.DeadLetter, error)
+
+	// Outbox
+	AddOutboxEvent(ctx context.Context, event *entity.OutboxEvent) error
+	ClaimOutboxEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.OutboxEvent, error)
+	MarkOutboxPublished(ctx context.Context, ids []int64, at time.Time) error
+	ReleaseOutboxEvents(ctx context.Context, ids []int64, at time.Time) error
+	RecordOutboxFailure(ctx context.Context, event *entity.OutboxEvent) error
+	CountPendingOutboxEvents(ctx context.Context) (int, error)
+	DeleteOutboxEvents(ctx context.Context, publishedBefore time.Time) (int, error)
+	GetCommittedOutboxEvents(ctx context.Context, after entity.OutboxPosition, limit int) ([]*entity.OutboxEvent, error)
+	GetOutboxEventPosition(ctx context.Context, id int64) (entity.OutboxPosition, error)
+	GetOutboxEnd(ctx context.Context) (entity.OutboxPosition, error)
+	LockConsumerOffset(ctx context.Context, name string) (entity.OutboxPosition, error)
+	SetConsumerOffset(ctx context.Context, name string, position entity.OutboxPosition) error
+
+	// Notifications
+	GetNotificationPreferences(ctx context.Context, userID string) (*entity.NotificationPreferences, error)
+	UpsertNotificationPreferences(ctx context.Context, prefs *entity.NotificationPreferences) error
+	AddNotifications(ctx context.Context, notifications []*entity.Notification) error
+	ClaimNotifications(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.Notification, error)
+	UpdateNotification(ctx context.Context, notification *entity.Notification) error
+	ListNotifications(ctx context.Context, filter entity.NotificationFilter) ([]*entity.Notification, error)
+
+	// Stats
+	GetReviewerStats(ctx context.Context, filter entity.StatsFilter) ([]*entity.ReviewerStats, error)
+	GetTeamStats(ctx context.Context, filter entity.StatsFilter) ([]*entity.TeamStats, error)
 }
@@ -3,19 +3,231 @@ package usecase
 import (
 	"avito_test_task/internal/entity"
 	"context"
+	"errors"
 	"log/slog"
+	"strings"
 )
 
-// SetIsActive устанавливает активность пользователя
-func (uc *UseCase) SetIsActive(ctx context.Context, userID string, active bool) (*entity.User, error) {
-	user, err := uc.repo.SetIsActive(ctx, userID, active)
+// errDryRun откатывает транзакцию пробного запуска после расчета изменений
+var errDryRun = errors.New("dry run")
+
+// SetIsActive меняет активность пользователя с записью в журнал аудита
+func (uc *UseCase) SetIsActive(ctx context.Context, userID string, active bool) (*entity.User, []*entity.ReviewReassignment, error) {
+	var (
+		user          *entity.User
+		reassignments []*entity.ReviewReassignment
+	)
+
+	err := uc.audited(ctx, entity.AuditSetIsActive, entity.AuditUser, userID, func(ctx context.Context) error {
+		var err error
+		user, reassignments, err = uc.setIsActive(ctx, userID, active)
+		return err
+	})
+
+	return user, reassignments, err
+}
+
+// setIsActive устанавливает активность пользователя; при деактивации его открытые ревью
+// переназначаются в той же транзакции
+func (uc *UseCase) setIsActive(ctx context.Context, userID string, active bool) (*entity.User, []*entity.ReviewReassignment, error) {
+	var user *entity.User
+	reassignments := make([]*entity.ReviewReassignment, 0)
+
+	err := uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = uc.repo.SetIsActive(ctx, userID, active)
+		if err != nil {
+			return err
+		}
+
+		if active {
+			return uc.emitUser(ctx, entity.EventUserActivated, user, "")
+		}
+
+		if err := uc.emitUser(ctx, entity.EventUserDeactivated, user, entity.ReasonUserDeactivated); err != nil {
+			return err
+		}
+
+		reassignments, err = uc.reassignOpenReviews(ctx, user, false, entity.ReasonUserDeactivated)
+		return err
+	})
+
 	if err != nil {
 		slog.Error("failed to set user active status", "error", err, "userID", userID)
+		return nil, nil, err
+	}
+
+	slog.Info("user active status updated", "status", active, "userID", userID, "reassignments", len(reassignments))
+	return user, reassignments, nil
+}
+
+// reassignOpenReviews переназначает открытые ревью пользователя, выведенного из ротации,
+// с причиной reason в истории назначений; при anyTeam замена ищется и в других командах,
+// если в своей никого нет.
+// PR без подходящей замены остаются за ним и попадают в результат с кодом NO_CANDIDATE
+func (uc *UseCase) reassignOpenReviews(ctx context.Context, reviewer *entity.User, anyTeam bool, reason entity.AssignmentReason) ([]*entity.ReviewReassignment, error) {
+	reviews, err := uc.repo.GetReview(ctx, reviewer.UserID)
+	if err != nil {
+		slog.Error("failed to get user reviews", "error", err, "userID", reviewer.UserID)
+		return nil, err
+	}
+
+	result := make([]*entity.ReviewReassignment, 0)
+	for _, review := range reviews {
+		if review.Status != entity.OPEN {
+			continue
+		}
+
+		pr, err := uc.repo.GetPR(ctx, review.ID)
+		if err != nil {
+			slog.Error("failed to get PR", "error", err, "prID", review.ID)
+			return nil, err
+		}
+
+		item := &entity.ReviewReassignment{
+			PullRequestID: pr.ID,
+			OldReviewerID: reviewer.UserID,
+		}
+
+		newReviewerID, err := uc.pickReplacement(ctx, pr, reviewer.TeamName, anyTeam)
+		if err != nil {
+			return nil, err
+		}
+
+		if newReviewerID == "" {
+			slog.Warn("no replacement for inactive reviewer", "prID", pr.ID, "reviewerID", reviewer.UserID)
+			item.Error = entity.NoCandidate
+			result = append(result, item)
+			continue
+		}
+
+		if _, err := uc.replaceReviewer(ctx, pr.ID, reviewer.UserID, newReviewerID, reason); err != nil {
+			slog.Error("failed to reassign reviewer", "error", err, "prID", pr.ID)
+			return nil, err
+		}
+
+		item.NewReviewerID = newReviewerID
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+// DeactivateUsers выполняет массовую деактивацию с записью в журнал аудита; пробный запуск
+// ничего не меняет и в журнал не попадает
+func (uc *UseCase) DeactivateUsers(ctx context.Context, teamName string, userIDs []string, dryRun bool) (*entity.Deactivation, error) {
+	if dryRun {
+		return uc.deactivateUsers(ctx, teamName, userIDs, true)
+	}
+
+	entityType, entityID := entity.AuditTeam, teamName
+	if teamName == "" {
+		entityType, entityID = entity.AuditUser, strings.Join(userIDs, ",")
+	}
+
+	var result *entity.Deactivation
+	err := uc.audited(ctx, entity.AuditDeactivateUsers, entityType, entityID, func(ctx context.Context) error {
+		var err error
+		result, err = uc.deactivateUsers(ctx, teamName, userIDs, false)
+		return err
+	})
+
+	return result, err
+}
+
+// deactivateUsers выводит из ротации пользователей команды teamName и/или из списка userIDs
+// и переназначает их открытые ревью одной транзакцией. Сначала деактивируются все пользователи
+// пакета, поэтому никто из них не будет выбран заменой. При dryRun изменения рассчитываются
+// в транзакции, которая затем откатывается
+func (uc *UseCase) deactivateUsers(ctx context.Context, teamName string, userIDs []string, dryRun bool) (*entity.Deactivation, error) {
+	if teamName == "" && len(userIDs) == 0 {
+		return nil, entity.NewError(entity.InvalidRequest, "team_name or user_ids is required")
+	}
+
+	result := &entity.Deactivation{DryRun: dryRun}
+	err := uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		ids, err := uc.deactivationBatch(ctx, teamName, userIDs)
+		if err != nil {
+			return err
+		}
+
+		result.Users = make([]*entity.User, 0, len(ids))
+		for _, id := range ids {
+			user, err := uc.repo.SetIsActive(ctx, id, false)
+			if err != nil {
+				slog.Error("failed to deactivate user", "error", err, "userID", id)
+				return err
+			}
+
+			if err := uc.emitUser(ctx, entity.EventUserDeactivated, user, entity.ReasonBulkDeactivation); err != nil {
+				return err
+			}
+
+			result.Users = append(result.Users, user)
+		}
+
+		result.Reassignments = make([]*entity.ReviewReassignment, 0)
+		for _, user := range result.Users {
+			reassignments, err := uc.reassignOpenReviews(ctx, user, true, entity.ReasonBulkDeactivation)
+			if err != nil {
+				return err
+			}
+
+			result.Reassignments = append(result.Reassignments, reassignments...)
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errDryRun) {
+		slog.Error("failed to deactivate users", "error", err, "team", teamName)
 		return nil, err
 	}
 
-	slog.Info("user active status updated", "status", active, "userID", userID)
-	return user, nil
+	slog.Info("users deactivated",
+		"dryRun", dryRun,
+		"users", len(result.Users),
+		"reassignments", len(result.Reassignments))
+
+	return result, nil
+}
+
+// deactivationBatch объединяет участников команды и явно указанных пользователей без повторов
+func (uc *UseCase) deactivationBatch(ctx context.Context, teamName string, userIDs []string) ([]string, error) {
+	ids := make([]string, 0, len(userIDs))
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if teamName != "" {
+		team, err := uc.repo.GetTeam(ctx, teamName)
+		if err != nil {
+			slog.Error("failed to get team", "error", err, "team", teamName)
+			return nil, err
+		}
+
+		for _, member := range team.Members {
+			add(member.UserID)
+		}
+	}
+
+	for _, id := range userIDs {
+		if id == "" {
+			return nil, entity.NewError(entity.InvalidRequest, "user_ids must not contain empty values")
+		}
+
+		add(id)
+	}
+
+	return ids, nil
 }
 
 // GetUserReviews получает все pull request, назначенные пользователю на ревью
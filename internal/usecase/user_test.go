@@ -0,0 +1,258 @@
+package usecase_test
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSetIsActiveKeepsReviewWithoutReplacement(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 2)
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pr.AssignReviewers) != 1 || pr.AssignReviewers[0] != "u2" {
+		t.Fatalf("reviewers = %v, want [u2]", pr.AssignReviewers)
+	}
+
+	user, reassignments, err := uc.SetIsActive(ctx, "u2", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.IsActive {
+		t.Error("user is still active")
+	}
+
+	if len(reassignments) != 1 || reassignments[0].Error != entity.NoCandidate || reassignments[0].NewReviewerID != "" {
+		t.Fatalf("reassignments = %+v, want one without candidate", reassignments)
+	}
+
+	reviews, err := uc.GetUserReviews(ctx, "u2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reviews) != 1 {
+		t.Errorf("review without replacement was dropped: %+v", reviews)
+	}
+
+	user, reassignments, err = uc.SetIsActive(ctx, "u2", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !user.IsActive || len(reassignments) != 0 {
+		t.Errorf("activation = %+v with %+v, want active without reassignments", user, reassignments)
+	}
+}
+
+func TestReadsOutsideTransactionSeeCommittedState(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	repo := memory.New()
+	uc := usecase.New(repo)
+	newTeam(t, ctx, uc, 2)
+
+	inTx := make(chan struct{})
+	release := make(chan struct{})
+	rollback := errors.New("rollback")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- repo.WithinTx(ctx, func(ctx context.Context) error {
+			if _, err := repo.SetIsActive(ctx, "u2", false); err != nil {
+				return err
+			}
+
+			close(inTx)
+			<-release
+			return rollback
+		})
+	}()
+
+	<-inTx
+
+	read := make(chan *entity.User, 1)
+	go func() {
+		user, _ := repo.GetUser(ctx, "u2")
+		read <- user
+	}()
+
+	select {
+	case user := <-read:
+		t.Fatalf("read during transaction returned %+v, want it to wait", user)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-done; !errors.Is(err, rollback) {
+		t.Fatalf("WithinTx error = %v, want rollback", err)
+	}
+
+	if user := <-read; user == nil || !user.IsActive {
+		t.Errorf("read after rollback = %+v, want active user", user)
+	}
+}
+
+func TestDeactivateUsersValidation(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 2)
+
+	tests := []struct {
+		name     string
+		teamName string
+		userIDs  []string
+		want     entity.ErrorCode
+	}{
+		{"nothing to deactivate", "", nil, entity.InvalidRequest},
+		{"empty user id", "", []string{"u1", ""}, entity.InvalidRequest},
+		{"unknown team", "frontend", nil, entity.NotFound},
+		{"unknown user", "", []string{"u1", "ghost"}, entity.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, dryRun := range []bool{true, false} {
+				if _, err := uc.DeactivateUsers(ctx, tt.teamName, tt.userIDs, dryRun); entity.CodeOf(err) != tt.want {
+					t.Errorf("DeactivateUsers(dryRun=%v) error = %v, want %s", dryRun, err, tt.want)
+				}
+			}
+		})
+	}
+
+	// неудачный пакет откатывается целиком
+	if !userActive(t, ctx, uc, "u1") {
+		t.Error("u1 was deactivated by a failed batch")
+	}
+}
+
+func TestDeactivateUsersDryRunPlansTheApply(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 4)
+
+	if _, err := uc.CreateTeam(ctx, &entity.Team{Name: "frontend", Members: []*entity.TeamMember{
+		{UserID: "f1", Name: "Fred", IsActive: true},
+		{UserID: "f2", Name: "Fiona", IsActive: true},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false); err != nil {
+		t.Fatal(err)
+	}
+
+	history := func() int {
+		t.Helper()
+
+		entries, err := uc.GetAssignmentHistory(ctx, entity.AssignmentHistoryFilter{PullRequestID: "pr1"})
+		if err != nil {
+			t.Fatalf("GetAssignmentHistory: %v", err)
+		}
+
+		return len(entries)
+	}
+
+	before := history()
+	backlog, err := uc.OutboxBacklog(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// в пакете все участники backend, кроме автора: замены берутся из другой команды
+	batch := []string{"u2", "u3", "u4"}
+	plan, err := uc.DeactivateUsers(ctx, "", batch, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !plan.DryRun || len(plan.Users) != 3 || len(plan.Reassignments) != 2 {
+		t.Fatalf("plan = %+v, want 3 users and 2 reassignments", plan)
+	}
+
+	for _, r := range plan.Reassignments {
+		if r.Error != "" || slices.Contains(batch, r.NewReviewerID) || r.NewReviewerID == "u1" {
+			t.Errorf("planned reassignment %+v, want a replacement outside the batch", r)
+		}
+	}
+
+	for _, id := range batch {
+		if !userActive(t, ctx, uc, id) {
+			t.Errorf("dry run deactivated %s", id)
+		}
+	}
+
+	if n := history(); n != before {
+		t.Errorf("dry run wrote %d history entries", n-before)
+	}
+
+	if n, err := uc.OutboxBacklog(ctx); err != nil || n != backlog {
+		t.Errorf("outbox backlog after dry run = %d, %v, want %d", n, err, backlog)
+	}
+
+	applied, err := uc.DeactivateUsers(ctx, "", batch, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if applied.DryRun || len(applied.Users) != len(plan.Users) || len(applied.Reassignments) != len(plan.Reassignments) {
+		t.Fatalf("applied = %+v, want the planned %+v", applied, plan)
+	}
+
+	for _, id := range batch {
+		if userActive(t, ctx, uc, id) {
+			t.Errorf("%s is still active", id)
+		}
+
+		if reviews, err := uc.GetUserReviews(ctx, id); err != nil || len(reviews) != 0 {
+			t.Errorf("%s reviews = %+v, %v, want reassigned", id, reviews, err)
+		}
+	}
+
+	// каждая замена — снятие и назначение
+	if n := history(); n != before+2*len(applied.Reassignments) {
+		t.Errorf("history has %d new entries, want %d", n-before, 2*len(applied.Reassignments))
+	}
+}
+
+func TestDeactivateTeamReportsReviewsWithoutReplacement(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 3)
+
+	pr, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := uc.DeactivateUsers(ctx, "backend", nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Users) != 3 || len(result.Reassignments) != len(pr.AssignReviewers) {
+		t.Fatalf("result = %+v, want all members and their reviews", result)
+	}
+
+	for _, r := range result.Reassignments {
+		if r.Error != entity.NoCandidate || r.NewReviewerID != "" {
+			t.Errorf("reassignment %+v, want %s without replacement", r, entity.NoCandidate)
+		}
+
+		// ревью без замены остается за деактивированным ревьюером
+		if reviews, err := uc.GetUserReviews(ctx, r.OldReviewerID); err != nil || len(reviews) != 1 {
+			t.Errorf("%s reviews = %+v, %v, want the kept review", r.OldReviewerID, reviews, err)
+		}
+	}
+}
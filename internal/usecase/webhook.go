@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"avito_test_task/internal/entity"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	webhookSecretBytes  = 32
+	maxWebhookURLLength = 2048
+
+	// WebhookBatchSize сколько доставок отправляется за один проход
+	WebhookBatchSize = 100
+)
+
+// WebhookSender отправляет доставку на URL вебхука; возвращает HTTP-статус ответа (0, если ответа нет)
+type WebhookSender interface {
+	Send(ctx context.Context, delivery *entity.WebhookDelivery) (int, error)
+}
+
+// CreateWebhook подписывает URL на события команды (или всех команд) с записью в журнал аудита.
+// Если секрет не задан, он генерируется; секрет возвращается только в ответе на создание
+func (uc *UseCase) CreateWebhook(ctx context.Context, hook *entity.Webhook) (*entity.Webhook, string, error) {
+	hook.URL = strings.TrimSpace(hook.URL)
+	if err := validateWebhook(hook); err != nil {
+		return nil, "", err
+	}
+
+	if hook.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			slog.Error("failed to generate webhook secret", "error", err)
+			return nil, "", err
+		}
+
+		hook.Secret = secret
+	}
+
+	hook.CreatedBy = entity.ActorFromContext(ctx).ID
+	err := uc.audited(ctx, entity.AuditCreateWebhook, entity.AuditWebhook, hook.URL, func(ctx context.Context) error {
+		return uc.repo.CreateWebhook(ctx, hook)
+	})
+
+	if err != nil {
+		slog.Error("failed to create webhook", "error", err, "url", hook.URL)
+		return nil, "", err
+	}
+
+	slog.Info("webhook created", "webhookID", hook.ID, "team", hook.TeamName, "events", hook.Events)
+	return hook, hook.Secret, nil
+}
+
+// ListWebhooks получает подписки команды или все подписки при пустом teamName
+func (uc *UseCase) ListWebhooks(ctx context.Context, teamName string) ([]*entity.Webhook, error) {
+	hooks, err := uc.repo.GetWebhooks(ctx, teamName)
+	if err != nil {
+		slog.Error("failed to get webhooks", "error", err, "team", teamName)
+		return nil, err
+	}
+
+	return hooks, nil
+}
+
+// DeleteWebhook удаляет подписку с записью в журнал аудита; ее доставки удаляются вместе с ней,
+// dead-letter записи остаются
+func (uc *UseCase) DeleteWebhook(ctx context.Context, id int64) error {
+	err := uc.audited(ctx, entity.AuditDeleteWebhook, entity.AuditWebhook, strconv.FormatInt(id, 10), func(ctx context.Context) error {
+		return uc.repo.DeleteWebhook(ctx, id)
+	})
+
+	if err != nil {
+		slog.Error("failed to delete webhook", "error", err, "webhookID", id)
+		return err
+	}
+
+	slog.Info("webhook deleted", "webhookID", id)
+	return nil
+}
+
+// ListWebhookDeliveries получает журнал доставок от новых к старым и курсор следующей страницы
+func (uc *UseCase) ListWebhookDeliveries(ctx context.Context, filter entity.DeliveryFilter) ([]*entity.WebhookDelivery, string, error) {
+	if filter.Status != "" && !slices.Contains(entity.DeliveryStatuses, filter.Status) {
+		return nil, "", entity.NewError(entity.InvalidRequest, "unknown delivery status %s", filter.Status)
+	}
+
+	limit, err := pageLimit(&filter.Page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	deliveries, err := uc.repo.ListWebhookDeliveries(ctx, filter)
+	if err != nil {
+		slog.Error("failed to list webhook deliveries", "error", err)
+		return nil, "", err
+	}
+
+	if len(deliveries) <= limit {
+		return deliveries, "", nil
+	}
+
+	deliveries = deliveries[:limit]
+	return deliveries, entity.Cursor{ID: strconv.FormatInt(deliveries[limit-1].ID, 10)}.Encode(), nil
+}
+
+// ListDeadLetters получает доставки, исчерпавшие попытки, и курсор следующей страницы
+func (uc *UseCase) ListDeadLetters(ctx context.Context, filter entity.DeliveryFilter) ([]*entity.DeadLetter, string, error) {
+	limit, err := pageLimit(&filter.Page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	letters, err := uc.repo.ListDeadLetters(ctx, filter)
+	if err != nil {
+		slog.Error("failed to list dead letters", "error", err)
+		return nil, "", err
+	}
+
+	if len(letters) <= limit {
+		return letters, "", nil
+	}
+
+	letters = letters[:limit]
+	return letters, entity.Cursor{ID: strconv.FormatInt(letters[limit-1].ID, 10)}.Encode(), nil
+}
+
+// DispatchWebhooks отправляет доставки, срок попытки которых наступил. Неудачная доставка
+// повторяется с экспоненциальной задержкой, после policy.MaxAttempts попыток переносится
+// в dead-letter таблицу. Возвращает число отправленных попыток
+func (uc *UseCase) DispatchWebhooks(ctx context.Context, sender WebhookSender, policy entity.RetryPolicy, lease time.Duration) (int, error) {
+	deliveries, err := uc.repo.ClaimWebhookDeliveries(ctx, time.Now(), lease, WebhookBatchSize)
+	if err != nil {
+		slog.Error("failed to claim webhook deliveries", "error", err)
+		return 0, err
+	}
+
+	for _, d := range deliveries {
+		status, sendErr := sender.Send(ctx, d)
+		if err := uc.completeDelivery(ctx, d, status, sendErr, policy); err != nil {
+			slog.Error("failed to save webhook delivery", "error", err, "deliveryID", d.ID)
+			return 0, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// completeDelivery сохраняет результат попытки и планирует следующую либо переносит доставку в dead letters
+func (uc *UseCase) completeDelivery(ctx context.Context, d *entity.WebhookDelivery, status int, sendErr error, policy entity.RetryPolicy) error {
+	now := time.Now()
+	d.Attempts++
+	d.ResponseStatus = status
+
+	if sendErr == nil {
+		d.Status = entity.DeliveryDelivered
+		d.NextAttemptAt = nil
+		d.DeliveredAt = &now
+		d.LastError = ""
+		return uc.repo.UpdateWebhookDelivery(ctx, d)
+	}
+
+	d.LastError = sendErr.Error()
+	if d.Attempts < policy.MaxAttempts {
+		next := now.Add(policy.Delay(d.Attempts))
+		d.Status = entity.DeliveryRetrying
+		d.NextAttemptAt = &next
+
+		slog.Warn("webhook delivery failed, will retry",
+			"error", sendErr, "deliveryID", d.ID, "attempts", d.Attempts, "nextAttemptAt", next)
+		return uc.repo.UpdateWebhookDelivery(ctx, d)
+	}
+
+	d.Status = entity.DeliveryDead
+	d.NextAttemptAt = nil
+
+	slog.Error("webhook delivery failed permanently", "error", sendErr, "deliveryID", d.ID, "attempts", d.Attempts)
+	return uc.repo.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.repo.UpdateWebhookDelivery(ctx, d); err != nil {
+			return err
+		}
+
+		return uc.repo.AddDeadLetter(ctx, &entity.DeadLetter{
+			DeliveryID: &d.ID,
+			WebhookID:  d.WebhookID,
+			EventID:    d.EventID,
+			EventType:  d.EventType,
+			Payload:    d.Payload,
+			Attempts:   d.Attempts,
+			LastError:  d.LastError,
+		})
+	})
+}
+
+// enqueueWebhooks ставит событие в очередь доставки подписанным на него вебхукам
+func (uc *UseCase) enqueueWebhooks(ctx context.Context, event *entity.Event, payload []byte) error {
+	hooks, err := uc.repo.GetSubscribedWebhooks(ctx, event.TeamName, event.Type)
+	if err != nil {
+		slog.Error("failed to get subscribed webhooks", "error", err, "event", event.Type)
+		return err
+	}
+
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	deliveries := make([]*entity.WebhookDelivery, 0, len(hooks))
+	for _, hook := range hooks {
+		deliveries = append(deliveries, &entity.WebhookDelivery{
+			WebhookID: hook.ID,
+			EventID:   event.ID,
+			EventType: event.Type,
+			Payload:   payload,
+		})
+	}
+
+	if err := uc.repo.AddWebhookDeliveries(ctx, deliveries); err != nil {
+		slog.Error("failed to enqueue webhook deliveries", "error", err, "event", event.Type)
+		return err
+	}
+
+	return nil
+}
+
+func validateWebhook(hook *entity.Webhook) error {
+	if hook.URL == "" || len(hook.URL) > maxWebhookURLLength {
+		return entity.NewError(entity.InvalidRequest, "url is required and must be at most %d characters", maxWebhookURLLength)
+	}
+
+	u, err := url.Parse(hook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return entity.NewError(entity.InvalidRequest, "url must be an absolute http(s) URL")
+	}
+
+	if !entity.IsPublicHost(u.Hostname()) {
+		return entity.NewError(entity.InvalidRequest, "url must point to a public host")
+	}
+
+	if len(hook.Events) == 0 {
+		return entity.NewError(entity.InvalidRequest, "events must not be empty")
+	}
+
+	events := make([]entity.EventType, 0, len(hook.Events))
+	for _, event := range hook.Events {
+		if !event.IsValid() {
+			return entity.NewError(entity.InvalidRequest, "unknown event type %s", event)
+		}
+
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+
+	hook.Events = events
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,175 @@
+package usecase_test
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeSender отвечает статусом status и ошибкой err на каждую доставку
+type fakeSender struct {
+	status int
+	err    error
+	sent   []*entity.WebhookDelivery
+}
+
+func (s *fakeSender) Send(_ context.Context, delivery *entity.WebhookDelivery) (int, error) {
+	s.sent = append(s.sent, delivery)
+	return s.status, s.err
+}
+
+// newWebhook подписывает вебхук на события команды backend
+func newWebhook(t *testing.T, ctx context.Context, uc *usecase.UseCase, events ...entity.EventType) *entity.Webhook {
+	t.Helper()
+
+	hook, _, err := uc.CreateWebhook(ctx, &entity.Webhook{URL: "https://hooks.example.com/reviews", TeamName: "backend", Events: events})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+
+	return hook
+}
+
+func TestCreateWebhook(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+
+	invalid := []struct {
+		name string
+		hook entity.Webhook
+	}{
+		{"no url", entity.Webhook{Events: []entity.EventType{entity.EventPRCreated}}},
+		{"relative url", entity.Webhook{URL: "/hooks", Events: []entity.EventType{entity.EventPRCreated}}},
+		{"not http", entity.Webhook{URL: "ftp://hooks.example.com", Events: []entity.EventType{entity.EventPRCreated}}},
+		{"loopback", entity.Webhook{URL: "http://127.0.0.1:8080/hooks", Events: []entity.EventType{entity.EventPRCreated}}},
+		{"localhost", entity.Webhook{URL: "http://localhost/hooks", Events: []entity.EventType{entity.EventPRCreated}}},
+		{"private network", entity.Webhook{URL: "https://10.0.0.5/hooks", Events: []entity.EventType{entity.EventPRCreated}}},
+		{"no events", entity.Webhook{URL: "https://hooks.example.com"}},
+		{"unknown event", entity.Webhook{URL: "https://hooks.example.com", Events: []entity.EventType{"pr.deleted"}}},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := uc.CreateWebhook(ctx, &tt.hook); entity.CodeOf(err) != entity.InvalidRequest {
+				t.Errorf("CreateWebhook error = %v, want %s", err, entity.InvalidRequest)
+			}
+		})
+	}
+
+	hook, secret, err := uc.CreateWebhook(ctx, &entity.Webhook{
+		URL:    " https://hooks.example.com/reviews ",
+		Events: []entity.EventType{entity.EventPRCreated, entity.EventPRCreated, entity.EventPRMerged},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if secret == "" || hook.URL != "https://hooks.example.com/reviews" || len(hook.Events) != 2 {
+		t.Errorf("webhook = %+v, secret %q, want trimmed URL, generated secret and distinct events", hook, secret)
+	}
+
+	hooks, err := uc.ListWebhooks(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hooks) != 1 || hooks[0].ID != hook.ID {
+		t.Errorf("webhooks = %+v, want the created one", hooks)
+	}
+
+	if err := uc.DeleteWebhook(ctx, hook.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := uc.DeleteWebhook(ctx, hook.ID); entity.CodeOf(err) != entity.NotFound {
+		t.Errorf("second delete error = %v, want %s", err, entity.NotFound)
+	}
+}
+
+func TestDispatchWebhooksDeliversSubscribedEvents(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 3)
+	hook := newWebhook(t, ctx, uc, entity.EventPRCreated)
+
+	if _, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &fakeSender{status: 200}
+	policy := entity.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour}
+	n, err := uc.DispatchWebhooks(ctx, sender, policy, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// подписка только на pr.created: назначения ревьюеров не доставляются
+	if n != 1 || sender.sent[0].EventType != entity.EventPRCreated || sender.sent[0].WebhookID != hook.ID {
+		t.Fatalf("dispatched %d: %+v, want one pr.created delivery", n, sender.sent)
+	}
+
+	deliveries, _, err := uc.ListWebhookDeliveries(ctx, entity.DeliveryFilter{WebhookID: hook.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(deliveries) != 1 || deliveries[0].Status != entity.DeliveryDelivered || deliveries[0].DeliveredAt == nil {
+		t.Errorf("deliveries = %+v, want one delivered", deliveries)
+	}
+
+	if n, err := uc.DispatchWebhooks(ctx, sender, policy, time.Minute); err != nil || n != 0 {
+		t.Errorf("second dispatch = %d, %v, want nothing to send", n, err)
+	}
+}
+
+func TestDispatchWebhooksRetriesAndDeadLetters(t *testing.T) {
+	ctx := entity.WithSystemActor(context.Background())
+	uc := usecase.New(memory.New())
+	newTeam(t, ctx, uc, 3)
+	hook := newWebhook(t, ctx, uc, entity.EventPRCreated)
+
+	if _, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &fakeSender{status: 503, err: errors.New("unexpected status 503")}
+	policy := entity.RetryPolicy{MaxAttempts: 2}
+
+	if n, err := uc.DispatchWebhooks(ctx, sender, policy, time.Minute); err != nil || n != 1 {
+		t.Fatalf("first dispatch = %d, %v, want 1", n, err)
+	}
+
+	deliveries, _, err := uc.ListWebhookDeliveries(ctx, entity.DeliveryFilter{Status: entity.DeliveryRetrying})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(deliveries) != 1 || deliveries[0].Attempts != 1 || deliveries[0].ResponseStatus != 503 {
+		t.Fatalf("retrying deliveries = %+v, want one after the first attempt", deliveries)
+	}
+
+	if n, err := uc.DispatchWebhooks(ctx, sender, policy, time.Minute); err != nil || n != 1 {
+		t.Fatalf("second dispatch = %d, %v, want 1", n, err)
+	}
+
+	letters, _, err := uc.ListDeadLetters(ctx, entity.DeliveryFilter{WebhookID: hook.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(letters) != 1 || letters[0].Attempts != 2 || letters[0].LastError == "" {
+		t.Errorf("dead letters = %+v, want one after two attempts", letters)
+	}
+
+	if n, err := uc.DispatchWebhooks(ctx, sender, policy, time.Minute); err != nil || n != 0 {
+		t.Errorf("dispatch after dead letter = %d, %v, want nothing to send", n, err)
+	}
+
+	if _, _, err := uc.ListWebhookDeliveries(ctx, entity.DeliveryFilter{Status: "LOST"}); entity.CodeOf(err) != entity.InvalidRequest {
+		t.Errorf("unknown status error = %v, want %s", err, entity.InvalidRequest)
+	}
+}
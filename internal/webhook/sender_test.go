@@ -0,0 +1,270 @@
+package webhook
+
+import (
+	"avito_test_task/internal/entity"
+	"avito_test_task/internal/notify"
+	"avito_test_task/internal/repository/memory"
+	"avito_test_task/internal/usecase"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder httptest-эндпоинт, отвечающий статусами statuses по очереди (последним — на все
+// остальные запросы) и запоминающий полученные запросы
+type recorder struct {
+	mu       sync.Mutex
+	statuses []int
+	headers  []http.Header
+	bodies   [][]byte
+}
+
+func (rec *recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	status := rec.statuses[min(len(rec.headers), len(rec.statuses)-1)]
+	rec.headers = append(rec.headers, r.Header.Clone())
+	rec.bodies = append(rec.bodies, body)
+	w.WriteHeader(status)
+}
+
+func (rec *recorder) requests() int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	return len(rec.headers)
+}
+
+// testSender отправитель без проверки адреса: httptest слушает loopback
+func testSender(srv *httptest.Server) *Sender {
+	return &Sender{client: srv.Client()}
+}
+
+func TestSenderSignsTimestampAndBody(t *testing.T) {
+	rec := &recorder{statuses: []int{http.StatusNoContent}}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	payload := []byte(`{"id":"e1","type":"pr.created"}`)
+	before := time.Now().Unix()
+
+	status, err := testSender(srv).Send(context.Background(), &entity.WebhookDelivery{
+		EventID:   "e1",
+		EventType: entity.EventPRCreated,
+		Payload:   payload,
+		URL:       srv.URL,
+		Secret:    "whsec_test",
+	})
+	if err != nil || status != http.StatusNoContent {
+		t.Fatalf("Send = %d, %v, want %d", status, err, http.StatusNoContent)
+	}
+
+	header, body := rec.headers[0], rec.bodies[0]
+	if string(body) != string(payload) {
+		t.Errorf("body = %s, want %s", body, payload)
+	}
+
+	if header.Get(HeaderEvent) != "pr.created" || header.Get(HeaderDelivery) != "e1" {
+		t.Errorf("event headers = %s, %s", header.Get(HeaderEvent), header.Get(HeaderDelivery))
+	}
+
+	timestamp := header.Get(HeaderTimestamp)
+	if ts, err := strconv.ParseInt(timestamp, 10, 64); err != nil || ts < before || ts > time.Now().Unix() {
+		t.Errorf("timestamp = %q, want unix time of the request", timestamp)
+	}
+
+	// получатель проверяет подпись независимо от Sign: HMAC-SHA256 от "<timestamp>.<тело>"
+	mac := hmac.New(sha256.New, []byte("whsec_test"))
+	mac.Write([]byte(timestamp + "." + string(body)))
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); header.Get(HeaderSignature) != want {
+		t.Errorf("signature = %s, want %s", header.Get(HeaderSignature), want)
+	}
+
+	if Sign("whsec_other", timestamp, body) == header.Get(HeaderSignature) {
+		t.Error("signature does not depend on the secret")
+	}
+}
+
+func TestSenderReportsNon2xx(t *testing.T) {
+	srv := httptest.NewServer(&recorder{statuses: []int{http.StatusServiceUnavailable}})
+	defer srv.Close()
+
+	status, err := testSender(srv).Send(context.Background(), &entity.WebhookDelivery{URL: srv.URL, Payload: []byte("{}")})
+	if err == nil || status != http.StatusServiceUnavailable {
+		t.Errorf("Send = %d, %v, want %d with error", status, err, http.StatusServiceUnavailable)
+	}
+}
+
+func TestSenderRefusesNonPublicAddress(t *testing.T) {
+	rec := &recorder{statuses: []int{http.StatusOK}}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	_, err := NewSender(time.Second).Send(context.Background(), &entity.WebhookDelivery{URL: srv.URL, Payload: []byte("{}")})
+	if !errors.Is(err, notify.ErrNonPublicAddress) {
+		t.Fatalf("Send error = %v, want %v", err, notify.ErrNonPublicAddress)
+	}
+
+	if rec.requests() != 0 {
+		t.Error("request reached a loopback server")
+	}
+}
+
+// newDispatchSetup создает команду backend, подписывает на pr.created вебхук с адресом srv
+// (напрямую в хранилище: usecase не примет loopback-адрес) и создает PR, порождающий доставку
+func newDispatchSetup(t *testing.T, srv *httptest.Server) (context.Context, *usecase.UseCase, *entity.Webhook) {
+	t.Helper()
+
+	ctx := entity.WithSystemActor(context.Background())
+	repo := memory.New()
+	uc := usecase.New(repo)
+
+	_, err := uc.CreateTeam(ctx, &entity.Team{Name: "backend", Members: []*entity.TeamMember{
+		{UserID: "u1", Name: "Alice", IsActive: true},
+		{UserID: "u2", Name: "Bob", IsActive: true},
+	}})
+	if err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+
+	hook := &entity.Webhook{URL: srv.URL, TeamName: "backend", Events: []entity.EventType{entity.EventPRCreated}, Secret: "whsec_test"}
+	if err := repo.CreateWebhook(ctx, hook); err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+
+	if _, err := uc.CreatePR(ctx, "pr1", "feature", "u1", false); err != nil {
+		t.Fatalf("CreatePR: %v", err)
+	}
+
+	return ctx, uc, hook
+}
+
+// delivery возвращает единственную доставку вебхука
+func delivery(t *testing.T, ctx context.Context, uc *usecase.UseCase, hook *entity.Webhook) *entity.WebhookDelivery {
+	t.Helper()
+
+	deliveries, _, err := uc.ListWebhookDeliveries(ctx, entity.DeliveryFilter{WebhookID: hook.ID})
+	if err != nil {
+		t.Fatalf("ListWebhookDeliveries: %v", err)
+	}
+
+	if len(deliveries) != 1 {
+		t.Fatalf("deliveries = %+v, want one", deliveries)
+	}
+
+	return deliveries[0]
+}
+
+// dispatchDue ждет срока следующей попытки доставки и отправляет ее
+func dispatchDue(t *testing.T, ctx context.Context, uc *usecase.UseCase, sender *Sender, policy entity.RetryPolicy, d *entity.WebhookDelivery) {
+	t.Helper()
+
+	if d.NextAttemptAt != nil {
+		time.Sleep(time.Until(*d.NextAttemptAt))
+	}
+
+	if n, err := uc.DispatchWebhooks(ctx, sender, policy, time.Minute); err != nil || n != 1 {
+		t.Fatalf("DispatchWebhooks = %d, %v, want 1 attempt", n, err)
+	}
+}
+
+func TestDispatchRetriesWithBackoffUntilDelivered(t *testing.T) {
+	rec := &recorder{statuses: []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	ctx, uc, hook := newDispatchSetup(t, srv)
+	sender := testSender(srv)
+	policy := entity.RetryPolicy{MaxAttempts: 5, BaseDelay: 50 * time.Millisecond, MaxDelay: time.Second}
+
+	d := delivery(t, ctx, uc, hook)
+	for attempt := 1; attempt <= 2; attempt++ {
+		started := time.Now()
+		dispatchDue(t, ctx, uc, sender, policy, d)
+
+		d = delivery(t, ctx, uc, hook)
+		if d.Status != entity.DeliveryRetrying || d.Attempts != attempt || d.NextAttemptAt == nil {
+			t.Fatalf("delivery after attempt %d = %+v, want RETRYING", attempt, d)
+		}
+
+		// задержка удваивается: 50ms после первой попытки, 100ms после второй
+		wait := policy.Delay(attempt)
+		if delay := d.NextAttemptAt.Sub(started); delay < wait || delay > wait+time.Second {
+			t.Errorf("delay after attempt %d = %s, want about %s", attempt, delay, wait)
+		}
+
+		// до срока повтора доставка не отправляется
+		if n, err := uc.DispatchWebhooks(ctx, sender, policy, time.Minute); err != nil || n != 0 {
+			t.Errorf("dispatch before retry time = %d, %v, want nothing", n, err)
+		}
+	}
+
+	dispatchDue(t, ctx, uc, sender, policy, d)
+
+	d = delivery(t, ctx, uc, hook)
+	if d.Status != entity.DeliveryDelivered || d.Attempts != 3 || d.ResponseStatus != http.StatusOK || d.LastError != "" {
+		t.Errorf("delivery = %+v, want DELIVERED after 3 attempts", d)
+	}
+
+	// все повторы несут один и тот же ID события
+	for _, header := range rec.headers {
+		if header.Get(HeaderDelivery) != d.EventID {
+			t.Errorf("delivery header = %s, want %s", header.Get(HeaderDelivery), d.EventID)
+		}
+	}
+}
+
+func TestDispatchDeadLettersAfterMaxAttempts(t *testing.T) {
+	rec := &recorder{statuses: []int{http.StatusServiceUnavailable}}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	ctx, uc, hook := newDispatchSetup(t, srv)
+	sender := testSender(srv)
+	policy := entity.RetryPolicy{MaxAttempts: 2, BaseDelay: 20 * time.Millisecond, MaxDelay: time.Second}
+
+	d := delivery(t, ctx, uc, hook)
+	for range policy.MaxAttempts {
+		dispatchDue(t, ctx, uc, sender, policy, d)
+		d = delivery(t, ctx, uc, hook)
+	}
+
+	if d.Status != entity.DeliveryDead || d.Attempts != 2 || d.NextAttemptAt != nil || d.ResponseStatus != http.StatusServiceUnavailable {
+		t.Fatalf("delivery = %+v, want DEAD after 2 attempts", d)
+	}
+
+	letters, _, err := uc.ListDeadLetters(ctx, entity.DeliveryFilter{WebhookID: hook.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(letters) != 1 || letters[0].EventID != d.EventID || letters[0].Attempts != 2 || letters[0].LastError == "" {
+		t.Fatalf("dead letters = %+v, want the exhausted delivery", letters)
+	}
+
+	if string(letters[0].Payload) != string(rec.bodies[0]) {
+		t.Errorf("dead letter payload = %s, want the sent body %s", letters[0].Payload, rec.bodies[0])
+	}
+
+	// исчерпавшая попытки доставка больше не отправляется
+	time.Sleep(policy.MaxDelay / 10)
+	if n, err := uc.DispatchWebhooks(ctx, sender, policy, time.Minute); err != nil || n != 0 {
+		t.Errorf("dispatch after dead letter = %d, %v, want nothing", n, err)
+	}
+
+	if rec.requests() != 2 {
+		t.Errorf("endpoint got %d requests, want 2", rec.requests())
+	}
+}
@@ -0,0 +1,4 @@
+ALTER TABLE teams
+    ADD COLUMN reviewer_strategy TEXT NOT NULL DEFAULT 'LEAST_BUSY',
+    ADD CONSTRAINT chk_reviewer_strategy
+        CHECK (reviewer_strategy IN ('LEAST_BUSY', 'ROUND_ROBIN', 'RANDOM_WEIGHTED', 'SENIORITY_AWARE'));
@@ -0,0 +1,11 @@
+CREATE TABLE team_settings (
+    team_id            INT PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    min_reviewers      INT NOT NULL DEFAULT 0,
+    max_reviewers      INT NOT NULL DEFAULT 2,
+    required_approvals INT NOT NULL DEFAULT 0,
+    require_team_lead  BOOLEAN NOT NULL DEFAULT FALSE,
+    team_lead_id       TEXT REFERENCES users(user_id) ON DELETE SET NULL,
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT chk_reviewers_range CHECK (min_reviewers >= 0 AND min_reviewers <= max_reviewers),
+    CONSTRAINT chk_required_approvals CHECK (required_approvals >= 0 AND required_approvals <= max_reviewers)
+);
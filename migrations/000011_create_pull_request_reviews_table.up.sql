@@ -0,0 +1,12 @@
+CREATE TABLE pull_request_reviews (
+    id          BIGSERIAL PRIMARY KEY,
+    pr_id       TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
+    decision    TEXT NOT NULL,
+    comment     TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT chk_decision CHECK (decision IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED'))
+);
+
+-- Индекс для получения решений по PR в хронологическом порядке
+CREATE INDEX idx_pr_reviews_pr_id ON pull_request_reviews(pr_id, created_at);
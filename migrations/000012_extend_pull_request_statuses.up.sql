@@ -0,0 +1,5 @@
+ALTER TABLE pull_requests DROP CONSTRAINT chk_status;
+
+ALTER TABLE pull_requests
+    ADD COLUMN closed_at TIMESTAMPTZ,
+    ADD CONSTRAINT chk_status CHECK (status IN ('DRAFT', 'OPEN', 'MERGED', 'CLOSED'));
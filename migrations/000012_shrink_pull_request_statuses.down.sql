@@ -0,0 +1,7 @@
+UPDATE pull_requests SET status = 'OPEN' WHERE status IN ('DRAFT', 'CLOSED');
+
+ALTER TABLE pull_requests DROP CONSTRAINT chk_status;
+
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS closed_at,
+    ADD CONSTRAINT chk_status CHECK (status IN ('OPEN', 'MERGED'));
@@ -0,0 +1,12 @@
+CREATE TABLE pull_request_reassignments (
+    id            BIGSERIAL PRIMARY KEY,
+    pr_id         TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    old_user_id   TEXT NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
+    new_user_id   TEXT NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
+    reassigned_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Индексы для статистики переназначений
+CREATE INDEX idx_pr_reassignments_old_user_id ON pull_request_reassignments(old_user_id, reassigned_at);
+CREATE INDEX idx_pr_reassignments_new_user_id ON pull_request_reassignments(new_user_id, reassigned_at);
+CREATE INDEX idx_pull_requests_created_at ON pull_requests(created_at);
@@ -0,0 +1,18 @@
+CREATE TABLE user_absences (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    starts_at   TIMESTAMPTZ NOT NULL,
+    ends_at     TIMESTAMPTZ NOT NULL,
+    reason      TEXT NOT NULL DEFAULT '',
+    deactivated BOOLEAN NOT NULL DEFAULT false,
+    applied_at  TIMESTAMPTZ,
+    released_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (ends_at > starts_at)
+);
+
+-- Индекс для исключения отсутствующих из кандидатов
+CREATE INDEX idx_user_absences_user_id ON user_absences(user_id, starts_at, ends_at);
+
+-- Индекс для фоновой синхронизации границ отсутствий
+CREATE INDEX idx_user_absences_pending ON user_absences(ends_at) WHERE released_at IS NULL;
@@ -0,0 +1,16 @@
+-- Пользователь, удаленный из команды, остается в базе без команды: на него ссылаются PR и ревью
+ALTER TABLE users ALTER COLUMN team_id DROP NOT NULL;
+
+CREATE TABLE team_membership_changes (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    action     TEXT NOT NULL,
+    from_team  TEXT,
+    to_team    TEXT,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT chk_membership_action CHECK (action IN ('ADDED', 'REMOVED', 'MOVED'))
+);
+
+-- Индексы для истории состава команды
+CREATE INDEX idx_membership_changes_from_team ON team_membership_changes(from_team, changed_at);
+CREATE INDEX idx_membership_changes_to_team ON team_membership_changes(to_team, changed_at);
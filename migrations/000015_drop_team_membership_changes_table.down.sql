@@ -0,0 +1,13 @@
+-- Пользователей без команды нельзя вернуть в схему, где команда обязательна: на них ссылаются
+-- PR и ревью, поэтому откат останавливается, пока их не переведут в команды
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM users WHERE team_id IS NULL) THEN
+        RAISE EXCEPTION 'users without a team exist, move them to a team before rolling back';
+    END IF;
+END
+$$;
+
+DROP TABLE IF EXISTS team_membership_changes;
+
+ALTER TABLE users ALTER COLUMN team_id SET NOT NULL;
@@ -0,0 +1,16 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Индексы для постраничных списков PR: порядок (created_at DESC, pull_request_id DESC)
+CREATE INDEX idx_pull_requests_created_at_id ON pull_requests(created_at DESC, pull_request_id DESC);
+CREATE INDEX idx_pull_requests_status_created_at ON pull_requests(status, created_at DESC, pull_request_id DESC);
+CREATE INDEX idx_pull_requests_author_created_at ON pull_requests(author_id, created_at DESC, pull_request_id DESC);
+CREATE INDEX idx_pull_requests_merged_at ON pull_requests(merged_at) WHERE merged_at IS NOT NULL;
+
+-- Индексы для фильтров пользователей
+CREATE INDEX idx_users_team_id_user_id ON users(team_id, user_id);
+
+-- Индексы для поиска по подстроке (ILIKE '%...%')
+CREATE INDEX idx_pull_requests_name_trgm ON pull_requests USING gin (pull_request_name gin_trgm_ops);
+CREATE INDEX idx_users_username_trgm ON users USING gin (username gin_trgm_ops);
+CREATE INDEX idx_users_user_id_trgm ON users USING gin (user_id gin_trgm_ops);
+CREATE INDEX idx_teams_team_name_trgm ON teams USING gin (team_name gin_trgm_ops);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_teams_team_name_trgm;
+DROP INDEX IF EXISTS idx_users_user_id_trgm;
+DROP INDEX IF EXISTS idx_users_username_trgm;
+DROP INDEX IF EXISTS idx_pull_requests_name_trgm;
+DROP INDEX IF EXISTS idx_users_team_id_user_id;
+DROP INDEX IF EXISTS idx_pull_requests_merged_at;
+DROP INDEX IF EXISTS idx_pull_requests_author_created_at;
+DROP INDEX IF EXISTS idx_pull_requests_status_created_at;
+DROP INDEX IF EXISTS idx_pull_requests_created_at_id;
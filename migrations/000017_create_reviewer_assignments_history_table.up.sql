@@ -0,0 +1,55 @@
+CREATE TABLE reviewer_assignments_history (
+    id               BIGSERIAL PRIMARY KEY,
+    pr_id            TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE RESTRICT,
+    user_id          TEXT NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
+    event            TEXT NOT NULL,
+    reason           TEXT NOT NULL,
+    actor            TEXT NOT NULL,
+    replaced_user_id TEXT REFERENCES users(user_id) ON DELETE RESTRICT,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT chk_assignment_event CHECK (event IN ('ASSIGNED', 'UNASSIGNED')),
+    CONSTRAINT chk_assignment_replaced CHECK (replaced_user_id IS NULL OR event = 'ASSIGNED')
+);
+
+-- Индексы для истории по PR и по пользователю
+CREATE INDEX idx_assignments_history_pr_id ON reviewer_assignments_history(pr_id, created_at);
+CREATE INDEX idx_assignments_history_user_id ON reviewer_assignments_history(user_id, created_at);
+
+-- Индекс для статистики переназначений
+CREATE INDEX idx_assignments_history_replaced ON reviewer_assignments_history(replaced_user_id, created_at)
+    WHERE replaced_user_id IS NOT NULL;
+
+-- История только дополняется
+CREATE FUNCTION reviewer_assignments_history_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'reviewer_assignments_history is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_assignments_history_append_only
+    BEFORE UPDATE OR DELETE ON reviewer_assignments_history
+    FOR EACH ROW EXECUTE FUNCTION reviewer_assignments_history_append_only();
+
+-- Переназначения из pull_request_reassignments переносятся в историю парами снятие/назначение,
+-- дальше история — единственный источник переназначений
+INSERT INTO reviewer_assignments_history (pr_id, user_id, event, reason, actor, replaced_user_id, created_at)
+SELECT pr_id, user_id, event, 'BACKFILL', 'system', replaced_user_id, reassigned_at
+FROM (
+    SELECT id, pr_id, old_user_id AS user_id, 'UNASSIGNED' AS event, NULL AS replaced_user_id, reassigned_at, 0 AS ord
+    FROM pull_request_reassignments
+    UNION ALL
+    SELECT id, pr_id, new_user_id, 'ASSIGNED', old_user_id, reassigned_at, 1
+    FROM pull_request_reassignments
+) ra
+ORDER BY reassigned_at, id, ord;
+
+-- Текущие назначения, не появившиеся через переназначение, переносятся с причиной BACKFILL
+INSERT INTO reviewer_assignments_history (pr_id, user_id, event, reason, actor, created_at)
+SELECT prr.pr_id, prr.user_id, 'ASSIGNED', 'BACKFILL', 'system', prr.assigned_at
+FROM pull_request_reviewers prr
+WHERE NOT EXISTS (
+    SELECT 1 FROM reviewer_assignments_history ah
+    WHERE ah.pr_id = prr.pr_id AND ah.user_id = prr.user_id AND ah.event = 'ASSIGNED'
+);
+
+DROP TABLE pull_request_reassignments;
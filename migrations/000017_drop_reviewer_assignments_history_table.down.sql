@@ -0,0 +1,20 @@
+CREATE TABLE pull_request_reassignments (
+    id            BIGSERIAL PRIMARY KEY,
+    pr_id         TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    old_user_id   TEXT NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
+    new_user_id   TEXT NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
+    reassigned_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_pr_reassignments_old_user_id ON pull_request_reassignments(old_user_id, reassigned_at);
+CREATE INDEX idx_pr_reassignments_new_user_id ON pull_request_reassignments(new_user_id, reassigned_at);
+
+-- Переназначения восстанавливаются из истории до ее удаления
+INSERT INTO pull_request_reassignments (pr_id, old_user_id, new_user_id, reassigned_at)
+SELECT pr_id, replaced_user_id, user_id, created_at
+FROM reviewer_assignments_history
+WHERE replaced_user_id IS NOT NULL
+ORDER BY created_at, id;
+
+DROP TABLE IF EXISTS reviewer_assignments_history;
+DROP FUNCTION IF EXISTS reviewer_assignments_history_append_only();
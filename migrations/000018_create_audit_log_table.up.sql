@@ -0,0 +1,19 @@
+CREATE TABLE audit_log (
+    id           BIGSERIAL PRIMARY KEY,
+    actor        TEXT NOT NULL,
+    role         TEXT NOT NULL,
+    action       TEXT NOT NULL,
+    entity_type  TEXT NOT NULL,
+    entity_id    TEXT NOT NULL,
+    endpoint     TEXT NOT NULL DEFAULT '',
+    payload_hash TEXT NOT NULL DEFAULT '',
+    outcome      TEXT NOT NULL,
+    error_code   TEXT,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT chk_audit_outcome CHECK (outcome IN ('SUCCESS', 'FAILURE'))
+);
+
+-- Индексы для выборок аудита по инициатору, сущности и времени
+CREATE INDEX idx_audit_log_actor ON audit_log(actor, id DESC);
+CREATE INDEX idx_audit_log_entity ON audit_log(entity_type, entity_id, id DESC);
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
@@ -0,0 +1,12 @@
+CREATE TABLE role_bindings (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    role       TEXT NOT NULL,
+    -- NULL означает глобальную привязку
+    team_id    INT REFERENCES teams(id) ON DELETE CASCADE,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX uq_role_bindings ON role_bindings(user_id, role, COALESCE(team_id, 0));
+CREATE INDEX idx_role_bindings_team_id ON role_bindings(team_id);
@@ -0,0 +1,48 @@
+CREATE TABLE webhooks (
+    id         BIGSERIAL PRIMARY KEY,
+    url        TEXT NOT NULL,
+    -- NULL означает подписку на события всех команд
+    team_id    INT REFERENCES teams(id) ON DELETE CASCADE,
+    events     TEXT[] NOT NULL,
+    secret     TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_webhooks_team_id ON webhooks(team_id);
+
+CREATE TABLE webhook_deliveries (
+    id              BIGSERIAL PRIMARY KEY,
+    webhook_id      BIGINT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id        TEXT NOT NULL,
+    event_type      TEXT NOT NULL,
+    payload         JSONB NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'PENDING',
+    attempts        INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ DEFAULT now(),
+    last_error      TEXT,
+    response_status INT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at    TIMESTAMPTZ,
+    CONSTRAINT chk_webhook_delivery_status CHECK (status IN ('PENDING', 'DELIVERED', 'RETRYING', 'DEAD')),
+    CONSTRAINT uq_webhook_delivery_event UNIQUE (webhook_id, event_id)
+);
+
+-- Очередь доставок к отправке
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at)
+    WHERE status IN ('PENDING', 'RETRYING');
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, id DESC);
+
+-- Dead-letter записи переживают удаление подписки и ее доставок: webhook_id и payload остаются,
+-- delivery_id обнуляется
+CREATE TABLE webhook_dead_letters (
+    id          BIGSERIAL PRIMARY KEY,
+    delivery_id BIGINT UNIQUE REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+    webhook_id  BIGINT NOT NULL,
+    event_id    TEXT NOT NULL,
+    event_type  TEXT NOT NULL,
+    payload     JSONB NOT NULL,
+    attempts    INT NOT NULL,
+    last_error  TEXT NOT NULL,
+    failed_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,26 @@
+CREATE TABLE outbox (
+    id              BIGSERIAL PRIMARY KEY,
+    -- ключ идемпотентности для получателей, совпадает с ID события
+    event_key       TEXT NOT NULL UNIQUE,
+    event_type      TEXT NOT NULL,
+    team_name       TEXT NOT NULL,
+    payload         JSONB NOT NULL,
+    attempts        INT NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    -- срок следующей попытки публикации, он же аренда забранного события
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    published_at    TIMESTAMPTZ,
+    -- момент, когда событие исчерпало попытки публикации
+    failed_at       TIMESTAMPTZ,
+    -- транзакция, записавшая событие: ID из BIGSERIAL выдаются в порядке вставки, а не
+    -- фиксации, поэтому outbox читается по (txid, id) и только по завершенным транзакциям
+    txid            xid8 NOT NULL DEFAULT pg_current_xact_id()
+);
+
+-- Все события и неопубликованные события в порядке чтения
+CREATE INDEX idx_outbox_position ON outbox(txid, id);
+CREATE INDEX idx_outbox_pending ON outbox(txid, id) WHERE published_at IS NULL AND failed_at IS NULL;
+
+-- Опубликованные события для удаления по сроку хранения
+CREATE INDEX idx_outbox_published ON outbox(published_at) WHERE published_at IS NOT NULL;
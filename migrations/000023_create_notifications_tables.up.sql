@@ -0,0 +1,43 @@
+CREATE TABLE notification_preferences (
+    user_id          TEXT PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    email            TEXT,
+    chat_webhook_url TEXT,
+    channels         TEXT[] NOT NULL DEFAULT '{}',
+    -- тихие часы "HH:MM" в часовом поясе quiet_timezone, NULL — без тихих часов
+    quiet_start      TEXT,
+    quiet_end        TEXT,
+    quiet_timezone   TEXT,
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE notifications (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      TEXT NOT NULL,
+    channel      TEXT NOT NULL,
+    address      TEXT NOT NULL,
+    event_key    TEXT NOT NULL,
+    event_type   TEXT NOT NULL,
+    payload      JSONB NOT NULL,
+    status       TEXT NOT NULL DEFAULT 'PENDING',
+    attempts     INT NOT NULL DEFAULT 0,
+    last_error   TEXT,
+    scheduled_at TIMESTAMPTZ NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    sent_at      TIMESTAMPTZ,
+    CONSTRAINT chk_notification_channel CHECK (channel IN ('EMAIL', 'CHAT')),
+    CONSTRAINT chk_notification_status CHECK (status IN ('PENDING', 'RETRYING', 'SENT', 'FAILED')),
+    CONSTRAINT uq_notification_event UNIQUE (user_id, channel, event_key)
+);
+
+-- Очередь уведомлений к отправке
+CREATE INDEX idx_notifications_due ON notifications(scheduled_at)
+    WHERE status IN ('PENDING', 'RETRYING');
+CREATE INDEX idx_notifications_user ON notifications(user_id, id DESC);
+
+-- Позиции потребителей outbox, читающих его по порядку: пара (last_txid, last_id)
+CREATE TABLE event_consumers (
+    name       TEXT PRIMARY KEY,
+    last_txid  xid8 NOT NULL,
+    last_id    BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS event_consumers;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS notification_preferences;
@@ -0,0 +1,13 @@
+-- SLA ревью команды в минутах: напоминание и автоматическая замена ревьюера, 0 — отключено
+ALTER TABLE team_settings
+    ADD COLUMN review_sla_minutes INT NOT NULL DEFAULT 2880,
+    ADD COLUMN escalation_minutes INT NOT NULL DEFAULT 5760,
+    ADD CONSTRAINT chk_review_sla CHECK (
+        review_sla_minutes >= 0 AND escalation_minutes >= 0
+        AND (escalation_minutes = 0 OR escalation_minutes > review_sla_minutes)
+    );
+
+-- Последние напоминание и попытка замены по назначению
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN reminded_at  TIMESTAMPTZ,
+    ADD COLUMN escalated_at TIMESTAMPTZ;
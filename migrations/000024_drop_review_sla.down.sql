@@ -0,0 +1,8 @@
+ALTER TABLE pull_request_reviewers
+    DROP COLUMN IF EXISTS escalated_at,
+    DROP COLUMN IF EXISTS reminded_at;
+
+ALTER TABLE team_settings
+    DROP CONSTRAINT IF EXISTS chk_review_sla,
+    DROP COLUMN IF EXISTS escalation_minutes,
+    DROP COLUMN IF EXISTS review_sla_minutes;